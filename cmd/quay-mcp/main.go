@@ -0,0 +1,581 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/quay/quay-mcp-server/internal/client"
+	"github.com/quay/quay-mcp-server/internal/logging"
+	"github.com/quay/quay-mcp-server/internal/server"
+)
+
+func main() {
+	registryURL := flag.String("url", "", "Quay registry URL (e.g. https://quay.io)")
+	oauthToken := flag.String("token", "", "OAuth token for authentication (defaults to $QUAY_TOKEN or $QUAY_OAUTH_TOKEN)")
+	tokenFile := flag.String("token-file", "", "File to read the OAuth token from and -login writes to (default: user config dir)")
+	credentialsFile := flag.String("credentials-file", "", "JSON or YAML file mapping registry URLs to OAuth tokens, used when no token is passed")
+	dockerConfig := flag.String("docker-config", client.DefaultDockerConfig(), "Docker client config whose $oauthtoken or identity token entries are the last fallback for a registry's token (empty disables)")
+	login := flag.Bool("login", false, "Run the OAuth device-authorization flow and store the token in -token-file")
+	failClosed := flag.Bool("fail-closed", false, "Check the token at startup and exit non-zero if Quay rejects it with 401 or 403")
+	authMode := flag.String("auth", client.AuthBearer, fmt.Sprintf("How requests send the token: one of %v", client.AuthModes))
+	authUsername := flag.String("auth-username", "", "Username sent with the token as the password when -auth=basic")
+	authHeader := flag.String("auth-header", client.DefaultAuthHeader, "Header carrying the token when -auth=header")
+	smokeTest := flag.String("smoke-test", "", "GET tool to call once at startup, logging whether Quay answered (e.g. quay_listRepos; with -fail-closed a failure stops startup)")
+	smokeTestArgs := flag.String("smoke-test-args", "", "JSON object of arguments for the -smoke-test call, e.g. {\"namespace\": \"redhat\"}")
+	requireAuth := flag.Bool("require-auth", false, "Refuse to start unless an OAuth token is configured through -token, $QUAY_OAUTH_TOKEN, -credentials-file, -token-file or -docker-config")
+	allowPerCallToken := flag.Bool("allow-per-call-token", false, "Accept an auth_token argument on tool calls that replaces the configured token for that call")
+	dryRun := flag.Bool("dry-run", false, "Return the method, URL, path and query parameters each tool call would use as JSON instead of calling Quay")
+	clientID := flag.String("oauth-client-id", "", "OAuth application client ID used by -login")
+	scope := flag.String("oauth-scope", "", "OAuth scopes requested by -login (default: repo:read org:admin user:read)")
+	deviceAuthURL := flag.String("device-auth-url", "", "Device authorization endpoint used by -login (default: <url>/oauth/device/code)")
+	tokenURL := flag.String("oauth-token-url", "", "Token endpoint used by -login (default: <url>/oauth/access_token)")
+	strictParams := flag.Bool("strict-params", false, "Reject tool arguments that are not declared parameters of the endpoint")
+	resultAsResource := flag.Bool("result-as-resource", false, "Return tool results as embedded application/json resources instead of text")
+	inferNamespace := flag.Bool("infer-namespace", false, "Default omitted namespace/orgname arguments to the token's primary organization")
+	specURL := flag.String("spec-url", "", "Base URL to fetch the discovery spec from, if different from -url")
+	specTransform := flag.String("spec-transform", "", "JSON or YAML merge patch applied to the Swagger spec before parsing, to fix spec bugs such as a wrong basePath")
+	endpointCacheFile := flag.String("endpoint-cache-file", "", "Cache discovered endpoints and tools in this JSON file and reuse them on the next start instead of fetching the spec")
+	endpointCacheTTL := flag.Duration("endpoint-cache-ttl", 24*time.Hour, "Age after which the -endpoint-cache-file is rebuilt (0 never expires)")
+	specFile := flag.String("spec-file", "", "Load the Swagger spec from a local file, file:// or http(s):// URL (optionally .gz or .zz compressed) instead of the registry's discovery endpoint; OpenAPI 3 specs use only their first servers URL")
+	toolCountThreshold := flag.Int("warn-on-large-tool-count", server.DefaultToolCountThreshold, "Log a warning when more tools than this are generated (0 disables)")
+	maxResponseBytes := flag.Int64("max-response-bytes", 0, "Maximum size of an API response body in bytes (0 means unlimited)")
+	allowedNamespaces := flag.String("allowed-namespaces", "", "Comma-separated namespaces tool calls may target (default: all)")
+	summarize := flag.Bool("summarize", false, "Return a short summary of list responses alongside the raw JSON")
+	requestIDHeader := flag.String("request-id-header", "", "Header used to send each call's correlation ID to Quay (e.g. X-Request-ID)")
+	jsonOnly := flag.Bool("json-only", false, "Only expose endpoints whose produces list includes application/json")
+	maxParamValueLength := flag.Int("max-param-value-length", 0, "Reject tool calls with an argument value longer than this many characters (0 means unlimited)")
+	maxURLLength := flag.Int("max-url-length", client.DefaultMaxURLLength, "Reject tool calls whose request URL exceeds this many characters (0 means unlimited)")
+	mirrorURL := flag.String("mirror-url", "", "Mirror registry URL; enables the quay_compare tool that diffs responses against -url")
+	mirrorToken := flag.String("mirror-token", "", "OAuth token for the mirror registry (defaults to $QUAY_MIRROR_TOKEN)")
+	endpointAllowlist := flag.String("endpoint-allowlist-file", "", "File listing the only \"METHOD /path\" endpoints exposed as tools, one per line, regardless of tags")
+	toolPrefix := flag.String("tool-prefix", client.DefaultToolPrefix, "Prefix of every tool name, e.g. prod_ to namespace the tools of one registry among several servers")
+	toolNameSource := flag.String("toolname-source", client.ToolNameFromOperationID, "Build tool names from the \"operationId\" or the cleaned \"path\" of each endpoint")
+	noFallbackDescription := flag.Bool("no-fallback-description", false, "Skip endpoints without a summary or description instead of describing them as \"GET {path}\"")
+	allowedTags := flag.String("tags", strings.Join(client.DefaultAllowedTags, ","), "Comma-separated tags whose endpoints are exposed (\"all\" or empty disables tag filtering)")
+	disableTags := flag.String("disable-tags", "", "Comma-separated tags to remove from the exposed set (e.g. manifest)")
+	validateArgs := flag.Bool("json-schema-validate-args", false, "Validate tool arguments against the generated input schema before calling Quay")
+	retryEmpty200 := flag.Bool("retry-empty-200", false, "Retry once when Quay returns 200 with an empty body")
+	paramTypeHints := flag.String("param-type-hints", "", "JSON file mapping parameter names to types (string, int, number, bool, array) for untyped spec parameters")
+	paramMapping := flag.String("param-mapping", "", "JSON file mapping API parameter names to the names tools expose instead (e.g. {\"orgname\": \"organization\"})")
+	tagDescriptionMap := flag.String("tag-description-map", "", "JSON file mapping tags to descriptions appended to the tools of that tag")
+	manifestAccept := flag.String("manifest-accept", strings.Join(client.DefaultManifestAccept, ","), "Comma-separated Accept media types for manifest endpoints (empty sends application/json)")
+	traceHeaders := flag.String("trace-headers", "", "Comma-separated response headers to log (e.g. X-RateLimit-Remaining,ETag; default: all)")
+	slowCallThreshold := flag.Duration("slow-call-threshold", 0, "Log a warning when a Quay call takes longer than this (e.g. 2s; 0 disables)")
+	responseHook := flag.String("response-hook", "", "Shell command each successful response is piped through on stdin; its stdout becomes the result (e.g. \"jq .repositories\")")
+	normalizeErrors := flag.Bool("normalize-errors", false, "Return failed calls as {\"error\": {\"status\", \"type\", \"message\"}} regardless of Quay's error body")
+	autoPaginate := flag.Int("auto-paginate", 0, "Follow next_page in list responses, merging up to this many pages into one result (0 disables)")
+	paginationCursor := flag.Bool("pagination-cursor", false, "Return list responses as {\"items\": [...], \"cursor\": \"...\"} using next_page as the cursor")
+	chunkSize := flag.Int("chunk-size", 0, "Split responses larger than this many bytes into chunks read with a continuation token (0 disables)")
+	unwrapResponse := flag.Bool("unwrap-response", false, "Return the inner array of responses whose only top-level key holds one, e.g. [...] for {\"repositories\": [...]}")
+	summarizeWithLLM := flag.String("summarize-with-llm", "none", "Summarizer that condenses large responses before they are sent: none or truncate")
+	summarizeAbove := flag.Int("summarize-above", server.DefaultSummarizeThreshold, "Response size in bytes above which -summarize-with-llm is used, and that truncate cuts to")
+	continuationTTL := flag.Duration("continuation-ttl", server.DefaultContinuationTTL, "How long the rest of a split response is kept between continuation calls")
+	flattenResponse := flag.Bool("flatten-response", false, "Flatten JSON responses into dot-notation key/value pairs (e.g. tags[0].name)")
+	responseMaxDepth := flag.Int("response-max-depth", 0, "Replace JSON objects and arrays nested deeper than this many levels with {\"...\": \"truncated\"} (0 disables)")
+	timeout := flag.Duration("timeout", client.DefaultTimeout, "Time limit for each HTTP request to Quay (0 disables)")
+	maxRetries := flag.Int("max-retries", client.DefaultMaxRetries, "Retries after a -retry-statuses response (0 disables)")
+	connectionRetries := flag.Int("connection-retries", client.DefaultConnectionRetries, "Retries of GET, PUT and DELETE requests after a connection error such as a reset connection (0 disables; POST and PATCH are never retried)")
+	rateLimit := flag.Float64("rate-limit", 0, "Maximum requests per second sent to Quay across all tool calls (0 disables)")
+	caCert := flag.String("ca-cert", "", "PEM bundle of CA certificates to trust for the registry in addition to the system roots")
+	insecureSkipVerify := flag.Bool("insecure-skip-verify", false, "Skip TLS certificate verification (self-signed development instances only)")
+	retryStatuses := flag.String("retry-statuses", "429,502,503,504", "Comma-separated response statuses that are retried (empty disables retries)")
+	listResources := flag.Bool("list-resources", false, "Print the MCP resources and resource templates generated from the spec and exit (works offline with -spec-file)")
+	successStatuses := flag.String("success-statuses", "200-299", "Comma-separated response statuses or ranges treated as success, e.g. 200-299 or 200,204 (others are errors)")
+	methods := flag.String("methods", "GET", "Comma-separated HTTP methods exposed as tools, e.g. GET,POST,DELETE (write methods modify the registry)")
+	validateWrites := flag.Bool("validate-writes", false, "Probe each write call first (with dry_run=true when the operation declares it, otherwise a GET of the resource) and skip the write if the probe fails")
+	callTimeout := flag.Duration("call-timeout", 0, "Upper bound for each tool call including retries (e.g. 30s; 0 disables)")
+	endpointTimeoutMap := flag.String("endpoint-timeout-map", "", "JSON file mapping operationIds or path patterns to call timeouts that replace -call-timeout for those endpoints")
+	deadlinePropagation := flag.Bool("deadline-propagation", true, "Honor the deadline the MCP client sets on a call, whichever of it and -call-timeout is sooner (false applies only -call-timeout)")
+	diagnosticsJSON := flag.Bool("diagnostics-json", false, "Write a JSON snapshot of the configuration, spec, endpoint and tool counts and warnings to stderr after startup")
+	registryHealthPoll := flag.Duration("registry-health-poll", 0, "How often to poll the registry, logging when it becomes unreachable or reachable again and reporting not-ready on /readyz of the sse and http transports meanwhile (0 disables)")
+	metaTools := flag.Bool("meta-tools", true, "Register the quay_batch, quay_last_response_headers, quay_metrics and quay_list_tools meta-tools (false exposes only the generated tools)")
+	reconnectOnEOF := flag.Bool("reconnect-on-eof", false, "Keep serving stdio after stdin reaches EOF instead of exiting")
+	transport := flag.String("transport", server.TransportStdio, "How MCP is served: stdio, sse (server-sent events at /sse) or http (streamable HTTP at /mcp)")
+	listen := flag.String("listen", server.DefaultListenAddress, "Address the sse and http transports listen on")
+	listOperations := flag.Bool("list-operations-by-tag", false, "Print every operation in the spec grouped by tag and exit (works offline with -spec-file)")
+	listToolsMarkdown := flag.Bool("list-tools-markdown", false, "Print the generated tools as a Markdown table and exit (works offline with -spec-file)")
+	prewarmSpec := flag.String("prewarm-spec", "", "Offline check: load this spec file, validate the generated tools and exit non-zero on problems")
+	callTool := flag.String("call", "", "Invoke a single tool, print its result and exit instead of serving MCP")
+	callArgs := flag.String("args", "", "JSON object of arguments for -call")
+	outputFile := flag.String("output-file", "", "Also write the -call result to this file")
+	tagPrefixes := mappingFlag{}
+	flag.Var(tagPrefixes, "tag-to-prefix", "Tag to tool-name prefix mapping as tag=prefix, e.g. repository=repo (repeatable or comma-separated)")
+	tagMethods := tagMethodsFlag{}
+	flag.Var(tagMethods, "tag-methods", "HTTP methods enabled for a tag as tag:METHOD,..., e.g. tag:GET,DELETE, overriding -methods for its operations (repeatable)")
+	unwrapKeys := mappingFlag{}
+	flag.Var(unwrapKeys, "unwrap-key", "Return the value under this top-level key instead of a tool's whole response, as tool=key, e.g. quay_listRepos=repositories (repeatable)")
+	paramDefaults := mappingFlag{}
+	flag.Var(paramDefaults, "param-default", "Default for an omitted parameter as name=template, e.g. namespace={{.org}} or namespace={{env \"QUAY_NAMESPACE\"}} (repeatable)")
+	logLevel := flag.String("log-level", "info", "Minimum level of log records: debug, info, warn or error (debug includes full request and response dumps)")
+	logFormat := flag.String("log-format", "text", "Log record format on stderr: text or json")
+	flag.Usage = usage
+	flag.Parse()
+	if err := applyEnv(flag.CommandLine, os.LookupEnv); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	logger, err := logging.New(os.Stderr, *logLevel, *logFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	slog.SetDefault(logger)
+
+	if *registryURL == "" && *prewarmSpec == "" && !((*listOperations || *listResources || *listToolsMarkdown) && *specFile != "") {
+		fmt.Fprintln(os.Stderr, "Error: -url is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	statuses, err := parseStatuses(*retryStatuses)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid -retry-statuses: %v\n", err)
+		os.Exit(2)
+	}
+	accepted, err := parseStatusRanges(*successStatuses)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid -success-statuses: %v\n", err)
+		os.Exit(2)
+	}
+
+	if *tokenFile == "" {
+		*tokenFile = client.DefaultTokenFile()
+	}
+
+	token := *oauthToken
+	if token == "" {
+		token = os.Getenv("QUAY_OAUTH_TOKEN")
+	}
+	if token == "" {
+		token = storedToken("credentials file", *credentialsFile, *registryURL, false, client.CredentialsToken)
+	}
+	if token == "" && *tokenFile != "" {
+		if stored, err := client.ReadTokenFile(*tokenFile); err == nil {
+			slog.Info("Using OAuth token from file", "file", *tokenFile)
+			token = stored
+		}
+	}
+	if token == "" {
+		token = storedToken("Docker config", *dockerConfig, *registryURL, true, client.DockerConfigToken)
+	}
+
+	mcpServer := server.NewQuayMCPServer(*registryURL, token)
+	mcpServer.GetQuayClient().SetSpecURL(*specURL)
+	mcpServer.GetQuayClient().SetSpecFile(*specFile)
+	mcpServer.GetQuayClient().SetEndpointCache(*endpointCacheFile, *endpointCacheTTL)
+	if *specTransform != "" {
+		if err := mcpServer.GetQuayClient().LoadSpecTransform(*specTransform); err != nil {
+			fatalf("Failed to load spec transform: %v", err)
+		}
+	}
+	mcpServer.GetQuayClient().SetStrictParams(*strictParams)
+	mcpServer.GetQuayClient().SetJSONOnly(*jsonOnly)
+	mcpServer.GetQuayClient().SetAllowedTags(splitList(*allowedTags))
+	mcpServer.GetQuayClient().SetDisabledTags(splitList(*disableTags))
+	mcpServer.GetQuayClient().SetTagPrefixes(tagPrefixes)
+	if err := mcpServer.GetQuayClient().SetToolPrefix(*toolPrefix); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid -tool-prefix: %v\n", err)
+		os.Exit(2)
+	}
+	if err := mcpServer.GetQuayClient().SetToolNameSource(*toolNameSource); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid -toolname-source: %v\n", err)
+		os.Exit(2)
+	}
+	mcpServer.GetQuayClient().SetNoFallbackDescription(*noFallbackDescription)
+	if err := mcpServer.GetQuayClient().SetMethods(splitList(*methods)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid -methods: %v\n", err)
+		os.Exit(2)
+	}
+	mcpServer.GetQuayClient().SetValidateWrites(*validateWrites)
+	if err := mcpServer.GetQuayClient().SetTagMethods(tagMethods); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid -tag-methods: %v\n", err)
+		os.Exit(2)
+	}
+	if *endpointAllowlist != "" {
+		if err := mcpServer.GetQuayClient().LoadEndpointAllowlist(*endpointAllowlist); err != nil {
+			fatalf("Failed to load endpoint allowlist: %v", err)
+		}
+	}
+	mcpServer.GetQuayClient().SetMaxResponseBytes(*maxResponseBytes)
+	mcpServer.GetQuayClient().SetRequestIDHeader(*requestIDHeader)
+	mcpServer.GetQuayClient().SetMaxURLLength(*maxURLLength)
+	mcpServer.GetQuayClient().SetMaxParamValueLength(*maxParamValueLength)
+	mcpServer.GetQuayClient().SetRetryEmpty200(*retryEmpty200)
+	mcpServer.GetQuayClient().SetTimeout(*timeout)
+	if err := mcpServer.GetQuayClient().SetAuth(*authMode, *authUsername, *authHeader); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid -auth: %v\n", err)
+		os.Exit(2)
+	}
+	if err := mcpServer.GetQuayClient().SetTLSConfig(*caCert, *insecureSkipVerify); err != nil {
+		fatalf("Invalid TLS configuration: %v", err)
+	}
+	mcpServer.GetQuayClient().SetMaxRetries(*maxRetries)
+	mcpServer.GetQuayClient().SetConnectionRetries(*connectionRetries)
+	mcpServer.GetQuayClient().SetRateLimit(*rateLimit)
+
+	// -login talks to the registry with the same timeout and TLS settings as API calls
+	if *login {
+		quayClient := mcpServer.GetQuayClient()
+		cfg := quayClient.DefaultDeviceAuthConfig(*clientID)
+		if *scope != "" {
+			cfg.Scope = *scope
+		}
+		if *deviceAuthURL != "" {
+			cfg.DeviceAuthorizationURL = *deviceAuthURL
+		}
+		if *tokenURL != "" {
+			cfg.TokenURL = *tokenURL
+		}
+
+		issued, err := quayClient.DeviceLogin(context.Background(), cfg, os.Stdout)
+		if err != nil {
+			fatalf("Login failed: %v", err)
+		}
+		if *tokenFile == "" {
+			fatalf("Login succeeded but no token file location is available; pass -token-file")
+		}
+		if err := client.WriteTokenFile(*tokenFile, issued); err != nil {
+			fatalf("Failed to store token: %v", err)
+		}
+		fmt.Printf("Token stored in %s\n", *tokenFile)
+		return
+	}
+
+	if err := checkRequireAuth(*requireAuth, token); err != nil {
+		fatalf("%v", err)
+	}
+
+	mcpServer.GetQuayClient().SetRetryStatuses(statuses)
+	mcpServer.GetQuayClient().SetSuccessStatuses(accepted)
+	mcpServer.GetQuayClient().SetSlowCallThreshold(*slowCallThreshold)
+	mcpServer.GetQuayClient().SetTraceHeaders(splitList(*traceHeaders))
+	mcpServer.GetQuayClient().SetManifestAccept(splitList(*manifestAccept))
+	if err := mcpServer.GetQuayClient().SetParamDefaults(paramDefaults); err != nil {
+		fatalf("Invalid -param-default: %v", err)
+	}
+	if *paramTypeHints != "" {
+		if err := mcpServer.GetQuayClient().LoadParamTypeHints(*paramTypeHints); err != nil {
+			fatalf("Failed to load parameter type hints: %v", err)
+		}
+	}
+	if *paramMapping != "" {
+		if err := mcpServer.GetQuayClient().LoadParamMapping(*paramMapping); err != nil {
+			fatalf("Failed to load parameter mapping: %v", err)
+		}
+	}
+	if *tagDescriptionMap != "" {
+		if err := mcpServer.GetQuayClient().LoadTagDescriptions(*tagDescriptionMap); err != nil {
+			fatalf("Failed to load tag description map: %v", err)
+		}
+	}
+	mcpServer.SetResultAsResource(*resultAsResource)
+	mcpServer.SetSummarize(*summarize)
+	mcpServer.SetFailClosed(*failClosed)
+	if *smokeTest != "" {
+		var arguments map[string]interface{}
+		if *smokeTestArgs != "" {
+			if err := json.Unmarshal([]byte(*smokeTestArgs), &arguments); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid -smoke-test-args: %v\n", err)
+				os.Exit(2)
+			}
+		}
+		mcpServer.SetSmokeTest(*smokeTest, arguments)
+	}
+	mcpServer.SetAllowPerCallToken(*allowPerCallToken)
+	mcpServer.SetDryRun(*dryRun)
+	mcpServer.SetUnwrapResponse(*unwrapResponse, unwrapKeys)
+	mcpServer.SetFlattenResponse(*flattenResponse)
+	mcpServer.SetResponseMaxDepth(*responseMaxDepth)
+	mcpServer.SetPaginationCursor(*paginationCursor)
+	mcpServer.SetAutoPaginate(*autoPaginate)
+	mcpServer.SetNormalizeErrors(*normalizeErrors)
+	mcpServer.SetReconnectOnEOF(*reconnectOnEOF)
+	mcpServer.SetMetaTools(*metaTools)
+	if err := mcpServer.SetTransport(*transport, *listen); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid -transport: %v\n", err)
+		os.Exit(2)
+	}
+	mcpServer.SetRegistryHealthPoll(*registryHealthPoll)
+	if *diagnosticsJSON {
+		mcpServer.SetDiagnosticsOutput(os.Stderr)
+	}
+	mcpServer.SetCallTimeout(*callTimeout)
+	mcpServer.SetDeadlinePropagation(*deadlinePropagation)
+	if *endpointTimeoutMap != "" {
+		if err := mcpServer.LoadEndpointTimeouts(*endpointTimeoutMap); err != nil {
+			fatalf("Failed to load endpoint timeout map: %v", err)
+		}
+	}
+	mcpServer.SetResponseHook(*responseHook)
+	summarizer, err := server.NewResponseSummarizer(*summarizeWithLLM, *summarizeAbove)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid -summarize-with-llm: %v\n", err)
+		os.Exit(2)
+	}
+	mcpServer.SetResponseSummarizer(summarizer, *summarizeAbove)
+	mcpServer.SetChunkSize(*chunkSize, *continuationTTL)
+	mcpServer.SetValidateArgs(*validateArgs)
+	mcpServer.SetToolCountThreshold(*toolCountThreshold)
+	mcpServer.SetAllowedNamespaces(splitList(*allowedNamespaces))
+
+	if *listOperations {
+		if err := mcpServer.GetQuayClient().FetchSwaggerSpec(); err != nil {
+			fatalf("Failed to load swagger spec: %v", err)
+		}
+		if err := mcpServer.GetQuayClient().WriteOperationsByTag(os.Stdout); err != nil {
+			fatalf("Failed to list operations: %v", err)
+		}
+		return
+	}
+
+	if *listToolsMarkdown {
+		if err := mcpServer.GetQuayClient().FetchSwaggerSpec(); err != nil {
+			fatalf("Failed to load swagger spec: %v", err)
+		}
+		mcpServer.GetQuayClient().DiscoverEndpoints()
+		if err := mcpServer.GetQuayClient().WriteToolsMarkdown(os.Stdout); err != nil {
+			fatalf("Failed to list tools: %v", err)
+		}
+		return
+	}
+
+	if *prewarmSpec != "" {
+		mcpServer.GetQuayClient().SetSpecFile(*prewarmSpec)
+		problems, err := mcpServer.CheckToolGeneration()
+		if err != nil {
+			fatalf("Spec check failed: %v", err)
+		}
+		for _, problem := range problems {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", *prewarmSpec, problem)
+		}
+		if len(problems) > 0 {
+			os.Exit(1)
+		}
+		fmt.Printf("%s: tool generation OK\n", *prewarmSpec)
+		return
+	}
+
+	if *mirrorToken == "" && *mirrorURL != "" {
+		*mirrorToken = storedToken("credentials file", *credentialsFile, *mirrorURL, false, client.CredentialsToken)
+		if *mirrorToken == "" {
+			*mirrorToken = storedToken("Docker config", *dockerConfig, *mirrorURL, true, client.DockerConfigToken)
+		}
+	}
+	mcpServer.SetMirror(*mirrorURL, *mirrorToken)
+
+	if *inferNamespace {
+		namespace, err := mcpServer.GetQuayClient().InferNamespace()
+		if err != nil {
+			slog.Warn("Could not infer namespace", "error", err)
+		} else {
+			mcpServer.GetQuayClient().SetDefaultNamespace(namespace)
+		}
+	}
+
+	if *listResources {
+		if err := mcpServer.GetQuayClient().FetchSwaggerSpec(); err != nil {
+			fatalf("Failed to load swagger spec: %v", err)
+		}
+		mcpServer.GetQuayClient().DiscoverEndpoints()
+		if err := mcpServer.WriteResources(os.Stdout); err != nil {
+			fatalf("Failed to list resources: %v", err)
+		}
+		return
+	}
+
+	if *callTool != "" {
+		if err := mcpServer.Initialize(); err != nil {
+			fatalf("Server error: %v", err)
+		}
+		if err := runCall(context.Background(), mcpServer, *callTool, *callArgs, *outputFile, os.Stdout); err != nil {
+			fatalf("Call failed: %v", err)
+		}
+		return
+	}
+
+	if err := mcpServer.Start(); err != nil {
+		fatalf("Server error: %v", err)
+	}
+}
+
+// usage prints the flags and how to set them from the environment
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage of %s:\n", os.Args[0])
+	flag.PrintDefaults()
+	fmt.Fprintf(out, "\nEvery flag can also be set with an environment variable named %s followed by the flag\n"+
+		"name in upper case with dashes as underscores, e.g. %s for -log-level.\n"+
+		"Flags given on the command line take precedence over the environment.\n", envPrefix, envVarName("log-level"))
+}
+
+// fatalf logs an error and exits
+func fatalf(format string, args ...interface{}) {
+	slog.Error(fmt.Sprintf(format, args...))
+	os.Exit(1)
+}
+
+// checkRequireAuth refuses an empty token when -require-auth is set, so a misconfigured deployment
+// fails at startup instead of silently serving anonymous, read-only tools
+func checkRequireAuth(required bool, token string) error {
+	if required && token == "" {
+		return fmt.Errorf("-require-auth is set but no OAuth token is configured (pass -token or set $QUAY_OAUTH_TOKEN, or provide a credentials file, token file or Docker config entry for the registry)")
+	}
+	return nil
+}
+
+// storedToken looks up a registry's token in a credentials source, logging where it came from but
+// never the token itself. A missing entry yields an empty token, as does a missing file when the
+// source is optional.
+func storedToken(source, path, registryURL string, optional bool, lookup func(path, registryURL string) (string, error)) string {
+	if path == "" || registryURL == "" {
+		return ""
+	}
+	token, err := lookup(path, registryURL)
+	switch {
+	case optional && errors.Is(err, fs.ErrNotExist):
+		return ""
+	case err != nil:
+		slog.Warn("Could not read registry credentials", "source", source, "error", err)
+		return ""
+	case token != "":
+		slog.Info("Using OAuth token from "+source, "file", path, "registry", registryURL)
+	}
+	return token
+}
+
+// runCall invokes a single tool and writes its result to stdout and, when set, to outputFile
+func runCall(ctx context.Context, s *server.QuayMCPServer, name, argsJSON, outputFile string, stdout io.Writer) (err error) {
+	var arguments map[string]interface{}
+	if argsJSON != "" {
+		if err := json.Unmarshal([]byte(argsJSON), &arguments); err != nil {
+			return fmt.Errorf("invalid -args JSON: %w", err)
+		}
+	}
+
+	result, err := s.CallTool(ctx, name, arguments)
+	if err != nil {
+		return err
+	}
+	output := server.ResultText(result)
+
+	out := stdout
+	if outputFile != "" {
+		file, createErr := os.Create(outputFile)
+		if createErr != nil {
+			return fmt.Errorf("failed to create output file: %w", createErr)
+		}
+		// A failed close may be the only sign the result never reached the file
+		defer func() {
+			if closeErr := file.Close(); closeErr != nil && err == nil {
+				err = fmt.Errorf("failed to write output file: %w", closeErr)
+			}
+		}()
+		out = io.MultiWriter(stdout, file)
+	}
+
+	if _, err := fmt.Fprintln(out, output); err != nil {
+		return fmt.Errorf("failed to write result: %w", err)
+	}
+	if result.IsError {
+		return fmt.Errorf("tool %s returned an error", name)
+	}
+	return nil
+}
+
+// splitList parses a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// mappingFlag is a repeatable flag collecting key=value pairs, each occurrence holding one or more
+// comma-separated pairs
+type mappingFlag map[string]string
+
+func (m mappingFlag) String() string {
+	pairs := make([]string, 0, len(m))
+	for key, value := range m {
+		pairs = append(pairs, key+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (m mappingFlag) Set(value string) error {
+	for _, pair := range splitList(value) {
+		key, mapped, ok := strings.Cut(pair, "=")
+		key, mapped = strings.TrimSpace(key), strings.TrimSpace(mapped)
+		if !ok || key == "" || mapped == "" {
+			return fmt.Errorf("expected key=value, got %q", pair)
+		}
+		m[key] = mapped
+	}
+	return nil
+}
+
+// tagMethodsFlag is a repeatable flag mapping a tag to the HTTP methods enabled for it, each
+// occurrence holding one tag:METHOD,... rule
+type tagMethodsFlag map[string][]string
+
+func (m tagMethodsFlag) String() string {
+	rules := make([]string, 0, len(m))
+	for tag, methods := range m {
+		rules = append(rules, tag+":"+strings.Join(methods, ","))
+	}
+	return strings.Join(rules, " ")
+}
+
+func (m tagMethodsFlag) Set(value string) error {
+	tag, methods, ok := strings.Cut(value, ":")
+	tag = strings.TrimSpace(tag)
+	if !ok || tag == "" || len(splitList(methods)) == 0 {
+		return fmt.Errorf("expected tag:METHOD,..., got %q", value)
+	}
+	m[tag] = splitList(methods)
+	return nil
+}
+
+// parseStatuses parses a comma-separated list of HTTP status codes
+func parseStatuses(value string) ([]int, error) {
+	var statuses []int
+	for _, item := range splitList(value) {
+		status, err := strconv.Atoi(item)
+		if err != nil || status < 100 || status > 599 {
+			return nil, fmt.Errorf("%q is not an HTTP status code", item)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// parseStatusRanges parses a comma-separated list of HTTP status codes and min-max ranges
+func parseStatusRanges(value string) ([]client.StatusRange, error) {
+	var ranges []client.StatusRange
+	for _, item := range splitList(value) {
+		low, high, isRange := strings.Cut(item, "-")
+		if !isRange {
+			high = low
+		}
+		bounds, err := parseStatuses(low + "," + high)
+		if err != nil || len(bounds) != 2 || bounds[0] > bounds[1] {
+			return nil, fmt.Errorf("%q is not an HTTP status code or ascending min-max range", item)
+		}
+		ranges = append(ranges, client.StatusRange{Min: bounds[0], Max: bounds[1]})
+	}
+	return ranges, nil
+}
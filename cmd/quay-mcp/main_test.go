@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/quay/quay-mcp-server/internal/server"
+)
+
+const testSpec = `{
+	"swagger": "2.0",
+	"basePath": "/api/v1",
+	"paths": {
+		"/api/v1/repository": {
+			"get": {
+				"operationId": "listRepos",
+				"tags": ["repository"],
+				"parameters": [
+					{"name": "namespace", "in": "query", "type": "string"}
+				]
+			}
+		}
+	}
+}`
+
+func TestRunCallOutputFile(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/discovery" {
+			w.Write([]byte(testSpec))
+			return
+		}
+		w.Write([]byte(`{"repositories": [{"name": "ubi8"}]}`))
+	}))
+	defer mockServer.Close()
+
+	s := server.NewQuayMCPServer(mockServer.URL, "")
+	if err := s.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+
+	outputFile := filepath.Join(t.TempDir(), "result.json")
+	var stdout bytes.Buffer
+	if err := runCall(context.Background(), s, "quay_listRepos", `{"namespace": "redhat"}`, outputFile, &stdout); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	written, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	if string(written) != stdout.String() {
+		t.Errorf("Expected output file to match stdout %q, got %q", stdout.String(), string(written))
+	}
+	if !bytes.Contains(written, []byte("ubi8")) {
+		t.Errorf("Expected the tool result in the output file, got %q", string(written))
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	flags := flag.NewFlagSet("quay-mcp", flag.ContinueOnError)
+	registryURL := flags.String("url", "", "")
+	logLevel := flags.String("log-level", "info", "")
+	timeout := flags.Duration("timeout", time.Second, "")
+	summarize := flags.Bool("summarize", false, "")
+	if err := flags.Parse([]string{"-url", "https://flag.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{
+		"QUAY_URL":       "https://env.example.com",
+		"QUAY_LOG_LEVEL": "debug",
+		"QUAY_SUMMARIZE": "true",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+	if err := applyEnv(flags, lookup); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Explicit flag > environment > default
+	if *registryURL != "https://flag.example.com" {
+		t.Errorf("Expected the explicit flag to win, got %s", *registryURL)
+	}
+	if *logLevel != "debug" || !*summarize {
+		t.Errorf("Expected the environment to set unset flags, got log level %s and summarize %v", *logLevel, *summarize)
+	}
+	if *timeout != time.Second {
+		t.Errorf("Expected the default without a flag or variable, got %s", *timeout)
+	}
+
+	env["QUAY_TIMEOUT"] = "soon"
+	if err := applyEnv(flags, lookup); err == nil || !strings.Contains(err.Error(), "$QUAY_TIMEOUT") {
+		t.Errorf("Expected an error naming the invalid variable, got %v", err)
+	}
+}
+
+func TestCheckRequireAuth(t *testing.T) {
+	if err := checkRequireAuth(true, ""); err == nil || !strings.Contains(err.Error(), "-require-auth") {
+		t.Errorf("Expected startup to fail without a token when -require-auth is set, got %v", err)
+	}
+	if err := checkRequireAuth(true, "secret"); err != nil {
+		t.Errorf("Expected a configured token to satisfy -require-auth, got %v", err)
+	}
+	if err := checkRequireAuth(false, ""); err != nil {
+		t.Errorf("Expected anonymous startup without -require-auth, got %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadEndpointAllowlist reads a file listing the endpoints that may become tools, one
+// "METHOD path" entry per line (e.g. "GET /api/v1/repository"). Blank lines and lines starting
+// with # are ignored. Once loaded, only listed endpoints are exposed, regardless of their tags.
+func (c *QuayClient) LoadEndpointAllowlist(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open endpoint allowlist: %v", err)
+	}
+	defer file.Close()
+
+	allowlist := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 || !strings.HasPrefix(fields[1], "/") {
+			return fmt.Errorf("%s:%d: expected \"METHOD /path\", got %q", path, lineNumber, line)
+		}
+		allowlist[strings.ToUpper(fields[0])+" "+fields[1]] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read endpoint allowlist: %v", err)
+	}
+
+	c.endpointAllowlist = allowlist
+	return nil
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"strings"
+
+	v2high "github.com/pb33f/libopenapi/datamodel/high/v2"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// collectionSeparators maps Swagger collectionFormat values to the separator joining array items.
+// The multi format repeats the query key for every item instead (?tag=a&tag=b).
+var collectionSeparators = map[string]string{
+	"csv":   ",",
+	"ssv":   " ",
+	"tsv":   "\t",
+	"pipes": "|",
+}
+
+// arrayItemsSchema returns the items schema of an array parameter, string unless the spec says otherwise
+func arrayItemsSchema(items *v2high.Items) map[string]any {
+	if items != nil && items.Type != "" {
+		return map[string]any{"type": items.Type}
+	}
+	return map[string]any{"type": "string"}
+}
+
+// queryCollectionFormats returns the collectionFormat of each query parameter of an endpoint that declares one
+func queryCollectionFormats(endpoint *types.EndpointInfo) map[string]string {
+	formats := make(map[string]string)
+	for _, p := range endpoint.Parameters {
+		if param, ok := p.(*v2high.Parameter); ok && param.In == "query" && param.CollectionFormat != "" {
+			formats[param.Name] = param.CollectionFormat
+		}
+	}
+	return formats
+}
+
+// queryValues returns the query string values of an argument: one per item for an array in the
+// multi collection format, otherwise a single value with array items joined (csv by default)
+func queryValues(value interface{}, collectionFormat string) []string {
+	items, isArray := formatArrayItems(value)
+	if !isArray {
+		if formatted, ok := formatParamValue(value); ok {
+			return []string{formatted}
+		}
+		return nil
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	if collectionFormat == "multi" {
+		return items
+	}
+	separator, ok := collectionSeparators[collectionFormat]
+	if !ok {
+		separator = ","
+	}
+	return []string{strings.Join(items, separator)}
+}
+
+// formatArrayItems formats the items of an array argument, reporting false for other values
+func formatArrayItems(value interface{}) ([]string, bool) {
+	var values []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		values = v
+	case []string:
+		values = make([]interface{}, len(v))
+		for i, item := range v {
+			values[i] = item
+		}
+	default:
+		return nil, false
+	}
+
+	items := make([]string, 0, len(values))
+	for _, item := range values {
+		if formatted, ok := formatParamValue(item); ok {
+			items = append(items, formatted)
+		}
+	}
+	return items, true
+}
@@ -0,0 +1,91 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Auth modes selecting how requests carry the token
+const (
+	AuthBearer = "bearer" // Authorization: Bearer <token>
+	AuthBasic  = "basic"  // Authorization: Basic with a username and the token as the password
+	AuthHeader = "header" // the token as the value of a custom header, e.g. X-Api-Key
+)
+
+// AuthModes lists the accepted -auth values
+var AuthModes = []string{AuthBearer, AuthBasic, AuthHeader}
+
+// DefaultAuthHeader is the header carrying the token in the header auth mode unless configured otherwise
+const DefaultAuthHeader = "X-Api-Key"
+
+// SetAuth selects how requests send the token, for deployments behind a proxy expecting basic auth
+// or an API key header instead of a bearer token. The basic mode needs username and the header
+// mode headerName; both are ignored by the other modes.
+func (c *QuayClient) SetAuth(mode, username, headerName string) error {
+	switch mode {
+	case AuthBearer:
+	case AuthBasic:
+		if username == "" {
+			return fmt.Errorf("the basic auth mode needs a username")
+		}
+		if strings.Contains(username, ":") {
+			return fmt.Errorf("basic auth username %q can't contain a colon", username)
+		}
+	case AuthHeader:
+		if headerName == "" {
+			return fmt.Errorf("the header auth mode needs a header name")
+		}
+		headerName = http.CanonicalHeaderKey(headerName)
+	default:
+		return fmt.Errorf("unknown auth mode %q (expected one of %v)", mode, AuthModes)
+	}
+	c.authMode = mode
+	c.authUsername = username
+	c.authHeader = headerName
+	return nil
+}
+
+// setAuth adds token to req the way the auth mode asks for
+func (c *QuayClient) setAuth(req *http.Request, token string) {
+	switch c.authMode {
+	case AuthBasic:
+		req.SetBasicAuth(c.authUsername, token)
+	case AuthHeader:
+		req.Header.Set(c.authHeader, token)
+	default:
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// redactedHeader copies request headers, masking the credentials of every auth mode for security
+func (c *QuayClient) redactedHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	for i, value := range redacted.Values("Authorization") {
+		if scheme, _, ok := strings.Cut(value, " "); ok {
+			redacted["Authorization"][i] = scheme + " [REDACTED]"
+		} else {
+			redacted["Authorization"][i] = "[REDACTED]"
+		}
+	}
+	if c.authMode == AuthHeader {
+		for i := range redacted.Values(c.authHeader) {
+			redacted[c.authHeader][i] = "[REDACTED]"
+		}
+	}
+	return redacted
+}
+
+// AuthHeaderName returns the custom header carrying the token in the header auth mode, or an
+// empty string in the other modes
+func (c *QuayClient) AuthHeaderName() string {
+	if c.authMode != AuthHeader {
+		return ""
+	}
+	return c.authHeader
+}
+
+// Token returns the configured token, e.g. to scrub it from text sent to MCP clients
+func (c *QuayClient) Token() string {
+	return c.oauthToken
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+func TestAuthModes(t *testing.T) {
+	var header http.Header
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer mockServer.Close()
+
+	basic := "Basic " + base64.StdEncoding.EncodeToString([]byte("robot:secret-token"))
+	for _, tc := range []struct {
+		mode, username, headerName string
+		wantHeader, wantValue      string
+	}{
+		{AuthBearer, "", "", "Authorization", "Bearer secret-token"},
+		{AuthBasic, "robot", "", "Authorization", basic},
+		{AuthHeader, "", "x-api-key", "X-Api-Key", "secret-token"},
+	} {
+		t.Run(tc.mode, func(t *testing.T) {
+			client := NewQuayClient(mockServer.URL, "secret-token")
+			if err := client.SetAuth(tc.mode, tc.username, tc.headerName); err != nil {
+				t.Fatal(err)
+			}
+			logs := captureLogs(t)
+
+			if _, err := client.MakeAPICallWithParams(&types.EndpointInfo{Method: "GET", Path: "/user"}, nil); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if got := header.Get(tc.wantHeader); got != tc.wantValue {
+				t.Errorf("Expected %s %q, got %q", tc.wantHeader, tc.wantValue, got)
+			}
+			if tc.mode == AuthHeader && header.Get("Authorization") != "" {
+				t.Errorf("Expected no Authorization header, got %q", header.Get("Authorization"))
+			}
+			if strings.Contains(logs.String(), "secret-token") || strings.Contains(logs.String(), basic[len("Basic "):]) {
+				t.Errorf("Expected the credentials to be redacted from the logs, got:\n%s", logs.String())
+			}
+		})
+	}
+}
+
+func TestSetAuthValidation(t *testing.T) {
+	client := NewQuayClient("https://quay.io", "")
+	for _, tc := range []struct{ mode, username, headerName string }{
+		{"digest", "", ""},
+		{AuthBasic, "", ""},
+		{AuthBasic, "robot:name", ""},
+		{AuthHeader, "", ""},
+	} {
+		if err := client.SetAuth(tc.mode, tc.username, tc.headerName); err == nil {
+			t.Errorf("Expected %+v to be rejected", tc)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v2high "github.com/pb33f/libopenapi/datamodel/high/v2"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// rawBodyArgument is the tool argument carrying the whole request body when the body schema
+// declares no properties to expose individually
+const rawBodyArgument = "body"
+
+// bodyParameter returns the "in: body" parameter among parameters, or nil when there is none
+func bodyParameter(parameters []*v2high.Parameter) *v2high.Parameter {
+	for _, param := range parameters {
+		if param != nil && param.In == "body" {
+			return param
+		}
+	}
+	return nil
+}
+
+// endpointBodyParameter returns the body parameter of a discovered endpoint, or nil
+func endpointBodyParameter(endpoint *types.EndpointInfo) *v2high.Parameter {
+	var parameters []*v2high.Parameter
+	for _, p := range endpoint.Parameters {
+		if param, ok := p.(*v2high.Parameter); ok {
+			parameters = append(parameters, param)
+		}
+	}
+	return bodyParameter(parameters)
+}
+
+// bodyProperties returns the resolved schema of a body parameter when it declares properties
+func bodyProperties(param *v2high.Parameter) *base.Schema {
+	if param == nil || param.Schema == nil {
+		return nil
+	}
+	schema := param.Schema.Schema()
+	if schema == nil || schema.Properties == nil || schema.Properties.Len() == 0 {
+		return nil
+	}
+	return schema
+}
+
+// bodyToolOptions exposes the fields of an operation's body schema as tool parameters, skipping
+// names already taken by path or query parameters. A body without declared properties becomes
+// a single object argument named "body".
+func (c *QuayClient) bodyToolOptions(param *v2high.Parameter, taken map[string]bool) []mcp.ToolOption {
+	if param == nil {
+		return nil
+	}
+
+	schema := bodyProperties(param)
+	if schema == nil {
+		description := "Request body as a JSON object"
+		if param.Description != "" {
+			description = fmt.Sprintf("Request body: %s", param.Description)
+		}
+		opts := []mcp.PropertyOption{mcp.Description(description)}
+		if param.Required != nil && *param.Required {
+			opts = append(opts, mcp.Required())
+		}
+		return []mcp.ToolOption{mcp.WithObject(rawBodyArgument, opts...)}
+	}
+
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	var toolOptions []mcp.ToolOption
+	for pair := schema.Properties.First(); pair != nil; pair = pair.Next() {
+		name := pair.Key()
+		if taken[name] {
+			continue
+		}
+
+		description := fmt.Sprintf("Body field: %s", name)
+		var fieldType string
+		if field := pair.Value().Schema(); field != nil {
+			if field.Description != "" {
+				description = field.Description
+			}
+			if len(field.Type) > 0 {
+				fieldType = field.Type[0]
+			}
+		}
+
+		opts := []mcp.PropertyOption{mcp.Description(description)}
+		if required[name] {
+			opts = append(opts, mcp.Required())
+		}
+		toolOptions = append(toolOptions, c.typedParamOption(name, fieldType, opts...))
+	}
+	return toolOptions
+}
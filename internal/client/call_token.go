@@ -0,0 +1,21 @@
+package client
+
+import "context"
+
+// tokenKey is the context key holding an OAuth token that overrides the configured one
+type tokenKey struct{}
+
+// WithToken returns a context whose outbound requests authenticate with token instead of the
+// client's configured token, so one server can act on behalf of different users
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// tokenFromContext returns the OAuth token for a request: the one carried by ctx, if any, and
+// the configured token otherwise
+func (c *QuayClient) tokenFromContext(ctx context.Context) string {
+	if token, ok := ctx.Value(tokenKey{}).(string); ok && token != "" {
+		return token
+	}
+	return c.oauthToken
+}
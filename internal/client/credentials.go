@@ -0,0 +1,119 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// oauthTokenUsername is the username Quay accepts with an OAuth token as the password, as in
+// docker login -u '$oauthtoken'
+const oauthTokenUsername = "$oauthtoken"
+
+// CredentialsToken returns the token for a registry from a JSON or YAML credentials file mapping
+// registry URLs to OAuth tokens, e.g. {"https://quay.io": "token"}. Keys match with or without
+// a scheme or trailing slash. It returns an empty token when the file has no entry for the registry.
+func CredentialsToken(path, registryURL string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	// YAML is a superset of JSON, so this reads both formats
+	var credentials map[string]string
+	if err := yaml.Unmarshal(data, &credentials); err != nil {
+		// Type errors quote the offending value, which may be a token, so they aren't passed on
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			return "", fmt.Errorf("invalid credentials file %s: expected a mapping of registry URLs to tokens", path)
+		}
+		return "", fmt.Errorf("invalid credentials file %s: %v", path, err)
+	}
+
+	host := registryHost(registryURL)
+	for registry, token := range credentials {
+		if registryHost(registry) == host {
+			return strings.TrimSpace(token), nil
+		}
+	}
+	return "", nil
+}
+
+// DefaultDockerConfig returns the location of the Docker client configuration, honoring $DOCKER_CONFIG
+func DefaultDockerConfig() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return filepath.Join(dir, "config.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".docker", "config.json")
+}
+
+// dockerConfig is the part of a Docker client configuration holding registry credentials
+type dockerConfig struct {
+	Auths map[string]struct {
+		Auth          string `json:"auth"`
+		IdentityToken string `json:"identitytoken"`
+		RegistryToken string `json:"registrytoken"`
+	} `json:"auths"`
+}
+
+// DockerConfigToken returns the OAuth token for a registry from a Docker client configuration.
+// Only entries holding a token are used: an identity or registry token, or a password logged in
+// with the $oauthtoken username. Plain username/password entries can't call the Quay API and are
+// skipped, as are registries without an entry, returning an empty token.
+func DockerConfigToken(path, registryURL string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read Docker config: %w", err)
+	}
+
+	var config dockerConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return "", fmt.Errorf("invalid Docker config %s: %v", path, err)
+	}
+
+	host := registryHost(registryURL)
+	for registry, entry := range config.Auths {
+		if registryHost(registry) != host {
+			continue
+		}
+		if entry.RegistryToken != "" {
+			return entry.RegistryToken, nil
+		}
+		if entry.IdentityToken != "" {
+			return entry.IdentityToken, nil
+		}
+		decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+		if err != nil {
+			return "", fmt.Errorf("invalid auth entry for %s in Docker config %s", registry, path)
+		}
+		if username, password, ok := strings.Cut(string(decoded), ":"); ok && username == oauthTokenUsername {
+			return password, nil
+		}
+	}
+	return "", nil
+}
+
+// registryHost reduces a registry URL or bare host to its lowercase host and port, so
+// "https://Quay.io/" and "quay.io" match
+func registryHost(registry string) string {
+	registry = strings.TrimSpace(registry)
+	if !strings.Contains(registry, "://") {
+		registry = "https://" + registry
+	}
+	parsed, err := url.Parse(registry)
+	if err != nil {
+		return strings.ToLower(registry)
+	}
+	return strings.ToLower(parsed.Host)
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+func TestCredentialsToken(t *testing.T) {
+	dir := t.TempDir()
+	jsonFile := filepath.Join(dir, "credentials.json")
+	if err := os.WriteFile(jsonFile, []byte(`{"https://quay.io/": "quay-token", "registry.example.com:8443": "example-token"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	yamlFile := filepath.Join(dir, "credentials.yaml")
+	if err := os.WriteFile(yamlFile, []byte("quay.io: yaml-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		file, registry, want string
+	}{
+		{jsonFile, "https://quay.io", "quay-token"},
+		{jsonFile, "https://REGISTRY.example.com:8443/", "example-token"},
+		{jsonFile, "https://other.example.com", ""},
+		{yamlFile, "https://quay.io", "yaml-token"},
+	} {
+		token, err := CredentialsToken(tc.file, tc.registry)
+		if err != nil {
+			t.Fatalf("Expected no error for %s, got %v", tc.registry, err)
+		}
+		if token != tc.want {
+			t.Errorf("Expected token %q for %s, got %q", tc.want, tc.registry, token)
+		}
+	}
+
+	// A file that isn't a mapping is rejected without echoing its contents
+	badFile := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(badFile, []byte("secret-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := CredentialsToken(badFile, "https://quay.io")
+	if err == nil || strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("Expected an error without the file contents, got %v", err)
+	}
+}
+
+func TestDockerConfigToken(t *testing.T) {
+	auth := func(username, password string) string {
+		return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	}
+	configFile := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configFile, []byte(`{"auths": {
+		"quay.io": {"auth": "`+auth("$oauthtoken", "oauth-token")+`"},
+		"https://identity.example.com": {"identitytoken": "identity-token"},
+		"basic.example.com": {"auth": "`+auth("user", "password")+`"}
+	}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		registry, want string
+	}{
+		{"https://quay.io", "oauth-token"},
+		{"https://identity.example.com", "identity-token"},
+		// Username/password logins aren't OAuth tokens
+		{"https://basic.example.com", ""},
+		{"https://missing.example.com", ""},
+	} {
+		token, err := DockerConfigToken(configFile, tc.registry)
+		if err != nil {
+			t.Fatalf("Expected no error for %s, got %v", tc.registry, err)
+		}
+		if token != tc.want {
+			t.Errorf("Expected token %q for %s, got %q", tc.want, tc.registry, token)
+		}
+	}
+}
+
+func TestCredentialsFileTokenIsRedacted(t *testing.T) {
+	var authorization string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"tags": []}`))
+	}))
+	defer mockServer.Close()
+
+	credentialsFile := filepath.Join(t.TempDir(), "credentials.yaml")
+	if err := os.WriteFile(credentialsFile, []byte(mockServer.URL+": file-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	token, err := CredentialsToken(credentialsFile, mockServer.URL)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	logs := captureLogs(t)
+	client := NewQuayClient(mockServer.URL, token)
+	if _, err := client.MakeAPICallWithParams(&types.EndpointInfo{Method: "GET", Path: "/tags"}, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if authorization != "Bearer file-token" {
+		t.Errorf("Expected the token from the credentials file, got %q", authorization)
+	}
+	if strings.Contains(logs.String(), "file-token") {
+		t.Errorf("Expected the token not to be logged, got %q", logs.String())
+	}
+}
@@ -0,0 +1,200 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// deviceCodeGrantType is the grant type used when polling the token endpoint (RFC 8628)
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// DeviceAuthConfig describes the OAuth endpoints and client used for the device-authorization flow
+type DeviceAuthConfig struct {
+	ClientID               string
+	Scope                  string
+	DeviceAuthorizationURL string
+	TokenURL               string
+
+	// PollInterval overrides the polling interval returned by the authorization server when non-zero
+	PollInterval time.Duration
+}
+
+// deviceAuthorizationResponse is the response of the device authorization endpoint
+type deviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// deviceTokenResponse is the response of the token endpoint while polling
+type deviceTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// DefaultDeviceAuthConfig returns the device flow endpoints relative to the registry URL
+func (c *QuayClient) DefaultDeviceAuthConfig(clientID string) DeviceAuthConfig {
+	return DeviceAuthConfig{
+		ClientID:               clientID,
+		Scope:                  "repo:read org:admin user:read",
+		DeviceAuthorizationURL: c.registryURL + "/oauth/device/code",
+		TokenURL:               c.registryURL + "/oauth/access_token",
+	}
+}
+
+// DeviceLogin performs the OAuth device-authorization flow, printing the user code and
+// verification URL to out and polling the token endpoint until a token is issued. The requests
+// use the client's HTTP client, so they honor its timeout and TLS settings.
+func (c *QuayClient) DeviceLogin(ctx context.Context, cfg DeviceAuthConfig, out io.Writer) (string, error) {
+	if cfg.ClientID == "" {
+		return "", fmt.Errorf("an OAuth client ID is required for device login")
+	}
+
+	form := url.Values{}
+	form.Set("client_id", cfg.ClientID)
+	if cfg.Scope != "" {
+		form.Set("scope", cfg.Scope)
+	}
+
+	slog.Info("Requesting device code", "url", cfg.DeviceAuthorizationURL)
+
+	var authResp deviceAuthorizationResponse
+	status, err := postForm(ctx, c.httpClient, cfg.DeviceAuthorizationURL, form, &authResp)
+	if err != nil {
+		return "", fmt.Errorf("failed to request device code: %w", err)
+	}
+	if status != http.StatusOK {
+		return "", fmt.Errorf("device authorization request failed with status %d", status)
+	}
+	if authResp.DeviceCode == "" || authResp.UserCode == "" {
+		return "", fmt.Errorf("device authorization response is missing device_code or user_code")
+	}
+
+	verificationURI := authResp.VerificationURIComplete
+	if verificationURI == "" {
+		verificationURI = authResp.VerificationURI
+	}
+	fmt.Fprintf(out, "To authorize quay-mcp, visit:\n\n  %s\n\nand enter the code: %s\n\n", verificationURI, authResp.UserCode)
+
+	interval := cfg.PollInterval
+	if interval == 0 {
+		interval = time.Duration(authResp.Interval) * time.Second
+		if interval == 0 {
+			interval = 5 * time.Second
+		}
+	}
+
+	expiresIn := time.Duration(authResp.ExpiresIn) * time.Second
+	if expiresIn == 0 {
+		expiresIn = 15 * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(ctx, expiresIn)
+	defer cancel()
+
+	pollForm := url.Values{}
+	pollForm.Set("grant_type", deviceCodeGrantType)
+	pollForm.Set("device_code", authResp.DeviceCode)
+	pollForm.Set("client_id", cfg.ClientID)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("device login timed out waiting for authorization")
+		case <-time.After(interval):
+		}
+
+		var tokenResp deviceTokenResponse
+		if _, err := postForm(ctx, c.httpClient, cfg.TokenURL, pollForm, &tokenResp); err != nil {
+			return "", fmt.Errorf("failed to poll token endpoint: %w", err)
+		}
+
+		switch tokenResp.Error {
+		case "":
+			if tokenResp.AccessToken == "" {
+				return "", fmt.Errorf("token endpoint returned no access token")
+			}
+			slog.Info("Device login completed successfully")
+			return tokenResp.AccessToken, nil
+		case "authorization_pending":
+			slog.Debug("Authorization pending, polling again", "interval", interval)
+		case "slow_down":
+			interval += 5 * time.Second
+			slog.Info("Token endpoint asked to slow down", "interval", interval)
+		case "access_denied":
+			return "", fmt.Errorf("device login was denied by the user")
+		case "expired_token":
+			return "", fmt.Errorf("device code expired before authorization completed")
+		default:
+			return "", fmt.Errorf("device login failed: %s %s", tokenResp.Error, tokenResp.ErrorDescription)
+		}
+	}
+}
+
+// postForm posts a form-encoded request and decodes the JSON response into v, returning the status code
+func postForm(ctx context.Context, client *http.Client, endpoint string, form url.Values, v interface{}) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "quay-mcp-server/1.0.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return resp.StatusCode, fmt.Errorf("invalid JSON response (status %d): %w", resp.StatusCode, err)
+	}
+	return resp.StatusCode, nil
+}
+
+// DefaultTokenFile returns the default location used to persist a token obtained via device login
+func DefaultTokenFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "quay-mcp", "token")
+}
+
+// WriteTokenFile stores a token at path with owner-only permissions
+func WriteTokenFile(path, token string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create token directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(token+"\n"), 0o600); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+	return nil
+}
+
+// ReadTokenFile reads a token previously stored with WriteTokenFile
+func ReadTokenFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
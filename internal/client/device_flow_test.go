@@ -0,0 +1,141 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDeviceLogin(t *testing.T) {
+	var polls int32
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("failed to parse form: %v", err)
+		}
+		if r.Form.Get("client_id") != "test-client" {
+			t.Errorf("Expected client_id 'test-client', got '%s'", r.Form.Get("client_id"))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/oauth/device/code":
+			w.Write([]byte(`{"device_code": "dev-123", "user_code": "ABCD-EFGH", "verification_uri": "https://quay.example/device", "expires_in": 60, "interval": 5}`))
+		case "/oauth/access_token":
+			if r.Form.Get("grant_type") != deviceCodeGrantType {
+				t.Errorf("Unexpected grant_type '%s'", r.Form.Get("grant_type"))
+			}
+			if r.Form.Get("device_code") != "dev-123" {
+				t.Errorf("Expected device_code 'dev-123', got '%s'", r.Form.Get("device_code"))
+			}
+			if atomic.AddInt32(&polls, 1) < 3 {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error": "authorization_pending"}`))
+				return
+			}
+			w.Write([]byte(`{"access_token": "device-token", "token_type": "Bearer"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewQuayClient(mockServer.URL, "")
+	cfg := client.DefaultDeviceAuthConfig("test-client")
+	cfg.PollInterval = 10 * time.Millisecond
+
+	var out bytes.Buffer
+	token, err := client.DeviceLogin(context.Background(), cfg, &out)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if token != "device-token" {
+		t.Errorf("Expected token 'device-token', got '%s'", token)
+	}
+	if polls != 3 {
+		t.Errorf("Expected 3 polls, got %d", polls)
+	}
+	if !strings.Contains(out.String(), "ABCD-EFGH") || !strings.Contains(out.String(), "https://quay.example/device") {
+		t.Errorf("Expected user code and verification URL in output, got %q", out.String())
+	}
+}
+
+func TestDeviceLoginDenied(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/oauth/device/code" {
+			w.Write([]byte(`{"device_code": "dev-123", "user_code": "ABCD-EFGH", "verification_uri": "https://quay.example/device"}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "access_denied"}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewQuayClient(mockServer.URL, "")
+	cfg := client.DefaultDeviceAuthConfig("test-client")
+	cfg.PollInterval = 10 * time.Millisecond
+
+	if _, err := client.DeviceLogin(context.Background(), cfg, &bytes.Buffer{}); err == nil {
+		t.Fatal("Expected an error when access is denied")
+	}
+}
+
+func TestDeviceLoginHonorsTLSConfig(t *testing.T) {
+	mockServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/oauth/device/code" {
+			w.Write([]byte(`{"device_code": "dev-123", "user_code": "ABCD-EFGH", "verification_uri": "https://quay.example/device"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token": "device-token", "token_type": "Bearer"}`))
+	}))
+	defer mockServer.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: mockServer.Certificate().Raw})
+	if err := os.WriteFile(caFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The flow goes through the client's HTTP client, which trusts the configured CA
+	client := NewQuayClient(mockServer.URL, "")
+	if err := client.SetTLSConfig(caFile, false); err != nil {
+		t.Fatal(err)
+	}
+	cfg := client.DefaultDeviceAuthConfig("test-client")
+	cfg.PollInterval = 10 * time.Millisecond
+
+	token, err := client.DeviceLogin(context.Background(), cfg, &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if token != "device-token" {
+		t.Errorf("Expected token 'device-token', got '%s'", token)
+	}
+}
+
+func TestTokenFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "token")
+
+	if err := WriteTokenFile(path, "secret-token"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	token, err := ReadTokenFile(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if token != "secret-token" {
+		t.Errorf("Expected token 'secret-token', got '%s'", token)
+	}
+}
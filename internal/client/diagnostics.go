@@ -0,0 +1,97 @@
+package client
+
+import "sort"
+
+// Diagnostics is a machine-readable snapshot of the client's configuration and discovery results
+type Diagnostics struct {
+	RegistryURL string   `json:"registry_url"`
+	SpecSource  string   `json:"spec_source"`
+	SpecVersion string   `json:"spec_version,omitempty"`
+	Endpoints   int      `json:"endpoints"`
+	Methods     []string `json:"methods"`
+	AllowedTags []string `json:"allowed_tags"`
+	AuthMode    string   `json:"auth_mode"`
+	ToolPrefix  string   `json:"tool_prefix"`
+
+	Retries   RetryDiagnostics     `json:"retries"`
+	Cache     CacheDiagnostics     `json:"cache"`
+	Transport TransportDiagnostics `json:"transport"`
+}
+
+// RetryDiagnostics describes the retry policy
+type RetryDiagnostics struct {
+	MaxRetries        int     `json:"max_retries"`
+	ConnectionRetries int     `json:"connection_retries"`
+	Statuses          []int   `json:"statuses"`
+	RateLimit         float64 `json:"rate_limit,omitempty"`
+}
+
+// CacheDiagnostics describes the endpoint cache
+type CacheDiagnostics struct {
+	File   string `json:"file,omitempty"`
+	MaxAge string `json:"max_age,omitempty"`
+	Loaded bool   `json:"loaded"`
+}
+
+// TransportDiagnostics describes the HTTP client used for Quay requests
+type TransportDiagnostics struct {
+	Timeout            string `json:"timeout"`
+	CACert             string `json:"ca_cert,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+}
+
+// Diagnostics returns a snapshot of the client's configuration and what discovery produced
+func (c *QuayClient) Diagnostics() Diagnostics {
+	d := Diagnostics{
+		RegistryURL: c.registryURL,
+		SpecSource:  "discovery",
+		Endpoints:   len(c.endpoints),
+		Methods:     []string{},
+		AllowedTags: c.allowedTags,
+		AuthMode:    AuthBearer,
+		ToolPrefix:  c.toolPrefix,
+		Retries:     RetryDiagnostics{MaxRetries: c.maxRetries, ConnectionRetries: c.connRetries, Statuses: []int{}},
+		Cache:       CacheDiagnostics{File: c.endpointCacheFile, Loaded: c.endpointCacheLoaded},
+		Transport: TransportDiagnostics{
+			Timeout:            c.httpClient.Timeout.String(),
+			CACert:             c.caCertFile,
+			InsecureSkipVerify: c.insecureSkipVerify,
+		},
+	}
+
+	if c.authMode != "" {
+		d.AuthMode = c.authMode
+	}
+	switch {
+	case c.endpointCacheLoaded:
+		d.SpecSource = "endpoint cache"
+	case c.specFile != "":
+		d.SpecSource = c.specFile
+	case c.specURL != "":
+		d.SpecSource = c.specURL
+	}
+	if c.model != nil && c.model.Model.Info != nil {
+		d.SpecVersion = c.model.Model.Info.Version
+	}
+	if c.endpointCacheFile != "" && c.endpointCacheMaxAge > 0 {
+		d.Cache.MaxAge = c.endpointCacheMaxAge.String()
+	}
+	if d.AllowedTags == nil {
+		d.AllowedTags = []string{"all"}
+	}
+
+	for _, method := range supportedMethods {
+		if c.methods[method] {
+			d.Methods = append(d.Methods, method)
+		}
+	}
+	for status := range c.retryStatuses {
+		d.Retries.Statuses = append(d.Retries.Statuses, status)
+	}
+	sort.Ints(d.Retries.Statuses)
+	if c.rateLimiter != nil {
+		d.Retries.RateLimit = c.rateLimiter.rate
+	}
+
+	return d
+}
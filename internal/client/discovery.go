@@ -0,0 +1,146 @@
+package client
+
+import (
+	"log/slog"
+	"runtime"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	v2high "github.com/pb33f/libopenapi/datamodel/high/v2"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// parallelDiscoveryPaths is the number of paths from which the spec is walked by a pool of
+// goroutines; smaller specs aren't worth the coordination
+const parallelDiscoveryPaths = 128
+
+// discoveryWorkers is the size of the worker pool walking large specs (0 uses GOMAXPROCS)
+var discoveryWorkers = 0
+
+// specPath is a path of the spec with its path item
+type specPath struct {
+	path string
+	item *v2high.PathItem
+}
+
+// discovery is what walking the spec produced, in spec order
+type discovery struct {
+	endpoints map[string]*types.EndpointInfo
+	tools     []mcp.Tool
+	total     int
+}
+
+// Discover walks the Swagger spec once, replacing the discovered endpoints and returning the tools
+// generated for them, which is what DiscoverEndpoints followed by GenerateTools does in two walks
+func (c *QuayClient) Discover() []mcp.Tool {
+	return c.discover(true)
+}
+
+// discover replaces the discovered endpoints with those of the spec, also generating their tools
+// when withTools is set
+func (c *QuayClient) discover(withTools bool) []mcp.Tool {
+	if c.model == nil {
+		return nil
+	}
+
+	// Start from scratch so rediscovery reflects the current filters
+	walk := c.walkSpec(withTools)
+	c.endpoints = walk.endpoints
+	if hasPaths(c.model) {
+		slog.Info("Discovered endpoints", "included", len(walk.endpoints), "total", walk.total)
+	}
+	return walk.tools
+}
+
+// walkSpec builds the endpoints of every included operation and, when withTools is set, their
+// tools, in spec order. The paths of large specs are split between a pool of workers.
+func (c *QuayClient) walkSpec(withTools bool) discovery {
+	walk := discovery{endpoints: make(map[string]*types.EndpointInfo)}
+	if c.model == nil {
+		return walk
+	}
+
+	// A partially built model may lack paths entirely
+	if !hasPaths(c.model) {
+		slog.Warn("The Swagger model has no paths; no tools generated")
+		return walk
+	}
+
+	if c.allowedTags != nil {
+		slog.Debug("Filtering endpoints by tag", "allowed_tags", c.allowedTags, "disabled_tags", c.disabledTags)
+	} else {
+		slog.Debug("Tag filtering disabled; including endpoints with any tag", "disabled_tags", c.disabledTags)
+	}
+
+	// Iterate through all paths using the ordered map API
+	var paths []specPath
+	for pathPair := c.model.Model.Paths.PathItems.First(); pathPair != nil; pathPair = pathPair.Next() {
+		paths = append(paths, specPath{path: pathPair.Key(), item: pathPair.Value()})
+	}
+
+	workers := discoveryWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if len(paths) < parallelDiscoveryPaths || workers == 1 {
+		for _, p := range paths {
+			c.discoverPath(&walk, p.path, p.item, withTools)
+		}
+		return walk
+	}
+
+	// Each worker walks its own contiguous run of paths into its own discovery, so workers share
+	// nothing but the read-only client settings and the model. libopenapi renders schemas lazily,
+	// but its schema proxies lock while rendering and share rendered $refs through a sync.Map, so
+	// the model may be walked concurrently. The runs are merged in spec order.
+	chunk := (len(paths) + workers - 1) / workers
+	walks := make([]discovery, 0, workers)
+	for start := 0; start < len(paths); start += chunk {
+		walks = append(walks, discovery{endpoints: make(map[string]*types.EndpointInfo)})
+	}
+	var wg sync.WaitGroup
+	for i := range walks {
+		run := paths[i*chunk : min((i+1)*chunk, len(paths))]
+		wg.Add(1)
+		go func(own *discovery) {
+			defer wg.Done()
+			for _, p := range run {
+				c.discoverPath(own, p.path, p.item, withTools)
+			}
+		}(&walks[i])
+	}
+	wg.Wait()
+
+	for _, own := range walks {
+		walk.total += own.total
+		for key, endpoint := range own.endpoints {
+			walk.endpoints[key] = endpoint
+		}
+		walk.tools = append(walk.tools, own.tools...)
+	}
+	return walk
+}
+
+// discoverPath adds the endpoints of the included operations of one path to walk and, when
+// withTools is set, their tools
+func (c *QuayClient) discoverPath(walk *discovery, path string, item *v2high.PathItem, withTools bool) {
+	// Only process operations of the enabled methods (GET by default)
+	for _, methodOp := range c.enabledOperations(item) {
+		walk.total++
+		method, operation := methodOp.Method, methodOp.Operation
+
+		if !c.includeOperation(method, path, operation) {
+			continue
+		}
+
+		// Store endpoint info for later API calls
+		walk.endpoints[endpointKey(method, path)] = operationEndpoint(method, path, operation)
+		if !withTools {
+			continue
+		}
+		if tool, ok := c.operationTool(method, path, operation); ok {
+			walk.tools = append(walk.tools, tool)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+// withDiscoveryWorkers sets the size of the discovery worker pool for the rest of the test
+func withDiscoveryWorkers(tb testing.TB, workers int) {
+	tb.Helper()
+	previous := discoveryWorkers
+	discoveryWorkers = workers
+	tb.Cleanup(func() {
+		discoveryWorkers = previous
+	})
+}
+
+func TestDiscoverInParallel(t *testing.T) {
+	client := NewQuayClient("https://quay.example.com", "")
+	client.SetMethods([]string{"GET", "PUT"})
+	if err := client.loadSwaggerSpec(largeSpec(2 * parallelDiscoveryPaths)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The workers walk first, so they are the ones resolving the body schema every PUT shares
+	withDiscoveryWorkers(t, 8)
+	tools := client.Discover()
+	endpoints := client.GetEndpoints()
+	if len(tools) != 4*parallelDiscoveryPaths {
+		t.Fatalf("Expected %d tools, got %d", 4*parallelDiscoveryPaths, len(tools))
+	}
+
+	// The worker pool must not change the tools or their spec order
+	withDiscoveryWorkers(t, 1)
+	if sequential := client.Discover(); !reflect.DeepEqual(tools, sequential) {
+		t.Error("Expected the parallel walk to generate the same tools in the same order")
+	}
+	if !reflect.DeepEqual(endpoints, client.GetEndpoints()) {
+		t.Error("Expected the parallel walk to discover the same endpoints")
+	}
+}
+
+func TestDiscoverInOneWalk(t *testing.T) {
+	client := NewQuayClient("https://quay.example.com", "")
+	client.SetMethods([]string{"GET", "PUT"})
+	if err := client.loadSwaggerSpec(largeSpec(50)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	client.DiscoverEndpoints()
+	endpoints := client.GetEndpoints()
+	generated := client.GenerateTools()
+
+	tools := client.Discover()
+	if len(tools) != 100 {
+		t.Fatalf("Expected 100 tools, got %d", len(tools))
+	}
+	// One walk must produce the same tools, in spec order, and endpoints as two
+	if !reflect.DeepEqual(tools, generated) {
+		t.Error("Expected Discover to generate the same tools as GenerateTools")
+	}
+	if !reflect.DeepEqual(client.GetEndpoints(), endpoints) {
+		t.Error("Expected Discover to discover the same endpoints as DiscoverEndpoints")
+	}
+}
+
+// BenchmarkDiscoverLargeSpec compares discovering the endpoints and generating the tools of a
+// 600-path spec in two sequential walks, as before, against a single walk without and with the
+// worker pool (one worker per GOMAXPROCS, so compare with -cpu 1,4)
+func BenchmarkDiscoverLargeSpec(b *testing.B) {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	client := NewQuayClient("https://quay.example.com", "")
+	client.SetMethods([]string{"GET", "PUT"})
+	if err := client.loadSwaggerSpec(largeSpec(600)); err != nil {
+		b.Fatalf("Expected no error, got %v", err)
+	}
+
+	b.Run("two walks", func(b *testing.B) {
+		withDiscoveryWorkers(b, 1)
+		for i := 0; i < b.N; i++ {
+			client.DiscoverEndpoints()
+			client.GenerateTools()
+		}
+	})
+	b.Run("one walk", func(b *testing.B) {
+		withDiscoveryWorkers(b, 1)
+		for i := 0; i < b.N; i++ {
+			client.Discover()
+		}
+	})
+	b.Run("one parallel walk", func(b *testing.B) {
+		withDiscoveryWorkers(b, 0)
+		for i := 0; i < b.N; i++ {
+			client.Discover()
+		}
+	})
+}
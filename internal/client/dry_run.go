@@ -0,0 +1,55 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// DryRun describes the request a call would send to Quay, without sending it
+type DryRun struct {
+	Method      string              `json:"method"`
+	URL         string              `json:"url"`
+	PathParams  map[string]string   `json:"path_params,omitempty"`
+	QueryParams map[string][]string `json:"query_params,omitempty"`
+	Headers     map[string]string   `json:"headers,omitempty"`
+	Body        json.RawMessage     `json:"body,omitempty"`
+}
+
+// DryRunCall resolves a call the way CallAPI does, with mapped names translated, defaults applied
+// and the path substituted, and returns the request it would send instead of sending it. Only the
+// header parameters of the endpoint are listed among the headers; the token is never included.
+func (c *QuayClient) DryRunCall(endpoint *types.EndpointInfo, params map[string]interface{}) (*DryRun, error) {
+	params, apiURL, body, err := c.prepareCall(endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := url.Parse(apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse API URL: %w", err)
+	}
+
+	dryRun := &DryRun{Method: endpoint.Method, URL: apiURL, Body: body}
+	for _, name := range extractPathParameterNames(endpoint.Path) {
+		if value, ok := formatParamValue(params[name]); ok {
+			if dryRun.PathParams == nil {
+				dryRun.PathParams = make(map[string]string)
+			}
+			dryRun.PathParams[name] = value
+		}
+	}
+	if query := parsed.Query(); len(query) > 0 {
+		dryRun.QueryParams = query
+	}
+	for _, header := range endpointHeaderArguments(endpoint) {
+		if value, ok := formatParamValue(params[header.name]); ok {
+			if dryRun.Headers == nil {
+				dryRun.Headers = make(map[string]string)
+			}
+			dryRun.Headers[header.param.Name] = value
+		}
+	}
+	return dryRun, nil
+}
@@ -0,0 +1,223 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	v2high "github.com/pb33f/libopenapi/datamodel/high/v2"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// endpointCacheVersion is bumped whenever the cache layout changes, invalidating older files
+const endpointCacheVersion = 1
+
+// endpointCache is the file written by SaveEndpointCache: the discovered endpoints and generated
+// tools, plus what is needed to tell whether they still match the current configuration
+type endpointCache struct {
+	Version   int                       `json:"version"`
+	Key       string                    `json:"key"`
+	CreatedAt time.Time                 `json:"created_at"`
+	BasePath  string                    `json:"base_path"`
+	Endpoints map[string]cachedEndpoint `json:"endpoints"`
+	Tools     []mcp.Tool                `json:"tools"`
+}
+
+// cachedEndpoint is the serializable form of types.EndpointInfo
+type cachedEndpoint struct {
+	Method          string            `json:"method"`
+	Path            string            `json:"path"`
+	Summary         string            `json:"summary,omitempty"`
+	OperationID     string            `json:"operation_id,omitempty"`
+	Tags            []string          `json:"tags,omitempty"`
+	Parameters      []cachedParameter `json:"parameters,omitempty"`
+	ResponseExample string            `json:"response_example,omitempty"`
+}
+
+// cachedParameter keeps the parameter fields used when calling an endpoint
+type cachedParameter struct {
+	Name             string `json:"name"`
+	In               string `json:"in"`
+	Type             string `json:"type,omitempty"`
+	Description      string `json:"description,omitempty"`
+	Required         bool   `json:"required,omitempty"`
+	CollectionFormat string `json:"collection_format,omitempty"`
+}
+
+// SetEndpointCache caches the discovered endpoints and generated tools in path, reusing them on
+// the next start instead of fetching and parsing the spec while they are younger than maxAge
+// (0 never expires) and were built with the same configuration
+func (c *QuayClient) SetEndpointCache(path string, maxAge time.Duration) {
+	c.endpointCacheFile = path
+	c.endpointCacheMaxAge = maxAge
+}
+
+// cacheKey fingerprints the settings that shape discovery and tool generation, so a cache built
+// with different settings is never reused
+func (c *QuayClient) cacheKey() string {
+	settings, _ := json.Marshal(map[string]interface{}{
+		"registry":         c.registryURL,
+		"spec_url":         c.specURL,
+		"spec_file":        c.specFile,
+		"spec_transform":   c.specTransform,
+		"allowed_tags":     c.allowedTags,
+		"disabled_tags":    c.disabledTags,
+		"methods":          c.methods,
+		"tag_methods":      c.tagMethods,
+		"allowlist":        c.endpointAllowlist,
+		"json_only":        c.jsonOnly,
+		"tag_prefixes":     c.tagPrefixes,
+		"tool_prefix":      c.toolPrefix,
+		"tool_name_path":   c.toolNameFromPath,
+		"no_fallback":      c.noFallbackDescription,
+		"param_type_hints": c.paramTypeHints,
+		"tag_descriptions": c.tagDescriptions,
+		"param_mapping":    c.paramMapping,
+	})
+	sum := sha256.Sum256(settings)
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadEndpointCache restores endpoints and tools from the endpoint cache. It reports false, and
+// leaves the client untouched, when no cache is configured or the cache is missing or stale.
+func (c *QuayClient) LoadEndpointCache() ([]mcp.Tool, bool) {
+	if c.endpointCacheFile == "" {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(c.endpointCacheFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("Failed to read endpoint cache", "error", err)
+		}
+		return nil, false
+	}
+
+	var cache endpointCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		slog.Warn("Ignoring unreadable endpoint cache", "file", c.endpointCacheFile, "error", err)
+		return nil, false
+	}
+	if reason := c.staleReason(&cache); reason != "" {
+		slog.Info("Endpoint cache is stale; rediscovering", "file", c.endpointCacheFile, "reason", reason)
+		return nil, false
+	}
+
+	c.endpoints = make(map[string]*types.EndpointInfo, len(cache.Endpoints))
+	for key, endpoint := range cache.Endpoints {
+		c.endpoints[key] = endpoint.endpointInfo()
+	}
+	c.cachedBasePath = cache.BasePath
+	c.endpointCacheLoaded = true
+
+	slog.Info("Loaded endpoint cache", "file", c.endpointCacheFile, "endpoints", len(c.endpoints),
+		"tools", len(cache.Tools), "built", cache.CreatedAt.Format(time.RFC3339))
+	return cache.Tools, true
+}
+
+// staleReason explains why a cache can't be reused, or returns "" when it is fresh
+func (c *QuayClient) staleReason(cache *endpointCache) string {
+	switch {
+	case cache.Version != endpointCacheVersion:
+		return fmt.Sprintf("version %d, expected %d", cache.Version, endpointCacheVersion)
+	case cache.Key != c.cacheKey():
+		return "built with different settings"
+	case c.endpointCacheMaxAge > 0 && time.Since(cache.CreatedAt) > c.endpointCacheMaxAge:
+		return fmt.Sprintf("older than %s", c.endpointCacheMaxAge)
+	}
+	return ""
+}
+
+// SaveEndpointCache writes the discovered endpoints and the given tools to the endpoint cache
+func (c *QuayClient) SaveEndpointCache(tools []mcp.Tool) error {
+	if c.endpointCacheFile == "" {
+		return nil
+	}
+
+	cache := endpointCache{
+		Version:   endpointCacheVersion,
+		Key:       c.cacheKey(),
+		CreatedAt: time.Now(),
+		BasePath:  c.basePath(),
+		Endpoints: make(map[string]cachedEndpoint, len(c.endpoints)),
+		Tools:     tools,
+	}
+	for key, endpoint := range c.endpoints {
+		cache.Endpoints[key] = newCachedEndpoint(endpoint)
+	}
+
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode endpoint cache: %w", err)
+	}
+
+	// Write to a temporary file first so a crash never leaves a truncated cache behind
+	tmp := c.endpointCacheFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write endpoint cache: %w", err)
+	}
+	if err := os.Rename(tmp, c.endpointCacheFile); err != nil {
+		return fmt.Errorf("failed to write endpoint cache: %w", err)
+	}
+
+	slog.Info("Saved endpoint cache", "file", c.endpointCacheFile, "endpoints", len(c.endpoints), "tools", len(tools))
+	return nil
+}
+
+// newCachedEndpoint converts a discovered endpoint into its serializable form
+func newCachedEndpoint(endpoint *types.EndpointInfo) cachedEndpoint {
+	cached := cachedEndpoint{
+		Method:          endpoint.Method,
+		Path:            endpoint.Path,
+		Summary:         endpoint.Summary,
+		OperationID:     endpoint.OperationID,
+		Tags:            endpoint.Tags,
+		ResponseExample: endpoint.ResponseExample,
+	}
+	for _, p := range endpoint.Parameters {
+		param, ok := p.(*v2high.Parameter)
+		if !ok {
+			continue
+		}
+		cached.Parameters = append(cached.Parameters, cachedParameter{
+			Name:             param.Name,
+			In:               param.In,
+			Type:             param.Type,
+			Description:      param.Description,
+			Required:         param.Required != nil && *param.Required,
+			CollectionFormat: param.CollectionFormat,
+		})
+	}
+	return cached
+}
+
+// endpointInfo rebuilds a discovered endpoint from its cached form. Body schemas aren't cached;
+// the cached tools already expose their fields.
+func (e cachedEndpoint) endpointInfo() *types.EndpointInfo {
+	endpoint := &types.EndpointInfo{
+		Method:          e.Method,
+		Path:            e.Path,
+		Summary:         e.Summary,
+		OperationID:     e.OperationID,
+		Tags:            e.Tags,
+		ResponseExample: e.ResponseExample,
+	}
+	for _, p := range e.Parameters {
+		required := p.Required
+		endpoint.Parameters = append(endpoint.Parameters, &v2high.Parameter{
+			Name:             p.Name,
+			In:               p.In,
+			Type:             p.Type,
+			Description:      p.Description,
+			Required:         &required,
+			CollectionFormat: p.CollectionFormat,
+		})
+	}
+	return endpoint
+}
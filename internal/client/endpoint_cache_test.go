@@ -0,0 +1,118 @@
+package client
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	v2high "github.com/pb33f/libopenapi/datamodel/high/v2"
+)
+
+func TestEndpointCacheRoundTrip(t *testing.T) {
+	original := newTestClient(t, `{
+		"swagger": "2.0",
+		"basePath": "/quay",
+		"paths": {
+			"/api/v1/repository/{repository}": {
+				"get": {
+					"operationId": "getRepo",
+					"summary": "Get repository",
+					"tags": ["repository"],
+					"parameters": [
+						{"name": "repository", "in": "path", "type": "string", "required": true},
+						{"name": "includeTags", "in": "query", "type": "boolean"}
+					]
+				}
+			}
+		}
+	}`)
+	cacheFile := filepath.Join(t.TempDir(), "endpoints.json")
+	original.SetEndpointCache(cacheFile, time.Hour)
+
+	tools := original.GenerateTools()
+	if err := original.SaveEndpointCache(tools); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// A new client with the same settings restores everything without loading the spec
+	restored := NewQuayClient(original.GetRegistryURL(), "")
+	restored.SetEndpointCache(cacheFile, time.Hour)
+	cachedTools, ok := restored.LoadEndpointCache()
+	if !ok {
+		t.Fatal("Expected the fresh cache to be loaded")
+	}
+	if restored.GetModel() != nil {
+		t.Error("Expected the spec not to be loaded")
+	}
+
+	if len(cachedTools) != 1 || cachedTools[0].Name != "quay_getRepo" || cachedTools[0].Description != tools[0].Description {
+		t.Errorf("Expected the cached tool, got %+v", cachedTools)
+	}
+	if !reflect.DeepEqual(cachedTools[0].InputSchema.Required, tools[0].InputSchema.Required) {
+		t.Errorf("Expected the cached input schema, got %+v", cachedTools[0].InputSchema)
+	}
+
+	endpoint := restored.GetEndpoints()["quay://api/v1/repository/{repository}"]
+	if endpoint == nil || endpoint.OperationID != "getRepo" || endpoint.Method != "GET" {
+		t.Fatalf("Expected the cached endpoint, got %+v", endpoint)
+	}
+	if len(endpoint.Parameters) != 2 {
+		t.Fatalf("Expected 2 cached parameters, got %d", len(endpoint.Parameters))
+	}
+	if param := endpoint.Parameters[1].(*v2high.Parameter); param.Name != "includeTags" || param.In != "query" || param.Type != "boolean" {
+		t.Errorf("Expected the query parameter to be restored, got %+v", param)
+	}
+
+	apiURL, err := restored.BuildAPIURLWithParams(endpoint, map[string]interface{}{"repository": "redhat/ubi8", "includeTags": true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := original.GetRegistryURL() + "/quay/api/v1/repository/redhat/ubi8?includeTags=true"; apiURL != want {
+		t.Errorf("Expected %s using the cached base path, got %s", want, apiURL)
+	}
+}
+
+func TestEndpointCacheStaleness(t *testing.T) {
+	original := newTestClient(t, `{
+		"swagger": "2.0",
+		"paths": {
+			"/api/v1/repository": {
+				"get": {"operationId": "listRepos", "tags": ["repository"]}
+			}
+		}
+	}`)
+	cacheFile := filepath.Join(t.TempDir(), "endpoints.json")
+	original.SetEndpointCache(cacheFile, 0)
+	if err := original.SaveEndpointCache(original.GenerateTools()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		configure func(*QuayClient)
+		wantFresh bool
+	}{
+		{"same settings", func(c *QuayClient) {}, true},
+		{"expired", func(c *QuayClient) { c.SetEndpointCache(cacheFile, time.Nanosecond) }, false},
+		{"different tags", func(c *QuayClient) { c.SetAllowedTags([]string{"robot"}) }, false},
+		{"different methods", func(c *QuayClient) { c.SetMethods([]string{"GET", "POST"}) }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewQuayClient(original.GetRegistryURL(), "")
+			client.SetEndpointCache(cacheFile, 0)
+			tt.configure(client)
+
+			if _, fresh := client.LoadEndpointCache(); fresh != tt.wantFresh {
+				t.Errorf("Expected fresh=%v, got %v", tt.wantFresh, fresh)
+			}
+		})
+	}
+
+	missing := NewQuayClient(original.GetRegistryURL(), "")
+	missing.SetEndpointCache(filepath.Join(t.TempDir(), "missing.json"), 0)
+	if _, fresh := missing.LoadEndpointCache(); fresh {
+		t.Error("Expected a missing cache not to load")
+	}
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// enumOption declares the allowed values of a parameter in its tool schema, so clients can offer
+// them as choices and the server can reject other values before calling Quay. Values of string
+// parameters are kept as written; others keep their YAML type, e.g. integers. It returns nil when
+// the parameter has no enum.
+func enumOption(paramType string, values []*yaml.Node) mcp.PropertyOption {
+	if len(values) == 0 {
+		return nil
+	}
+
+	if paramType == "" || paramType == "string" {
+		enum := make([]string, 0, len(values))
+		for _, node := range values {
+			if node != nil {
+				enum = append(enum, node.Value)
+			}
+		}
+		return mcp.Enum(enum...)
+	}
+
+	enum := make([]any, 0, len(values))
+	for _, node := range values {
+		var value any
+		if node != nil && node.Decode(&value) == nil {
+			enum = append(enum, value)
+		}
+	}
+	return func(schema map[string]any) {
+		schema["enum"] = enum
+	}
+}
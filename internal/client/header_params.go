@@ -0,0 +1,79 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	v2high "github.com/pb33f/libopenapi/datamodel/high/v2"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// headerArgumentPrefix is put before the argument name of a header parameter whose name a path or
+// query parameter of the same operation already uses, e.g. header_page next to the query's page
+const headerArgumentPrefix = "header_"
+
+// headerArgument is a header parameter of an operation and the tool argument that sets it
+type headerArgument struct {
+	name  string
+	param *v2high.Parameter
+}
+
+// headerArguments returns the header parameters among parameters, in spec order, with their
+// argument names. taken holds the path and query parameter names. Authorization is left out, since
+// the client sets it from the configured token.
+func headerArguments(parameters []*v2high.Parameter, taken map[string]bool) []headerArgument {
+	var headers []headerArgument
+	for _, param := range parameters {
+		if param == nil || param.In != "header" || http.CanonicalHeaderKey(param.Name) == "Authorization" {
+			continue
+		}
+		name := param.Name
+		if taken[name] {
+			name = headerArgumentPrefix + name
+		}
+		headers = append(headers, headerArgument{name: name, param: param})
+	}
+	return headers
+}
+
+// endpointHeaderArguments is headerArguments for a discovered endpoint
+func endpointHeaderArguments(endpoint *types.EndpointInfo) []headerArgument {
+	taken := queryParamNames(endpoint)
+	for _, name := range extractPathParameterNames(endpoint.Path) {
+		taken[name] = true
+	}
+	var parameters []*v2high.Parameter
+	for _, p := range endpoint.Parameters {
+		if param, ok := p.(*v2high.Parameter); ok {
+			parameters = append(parameters, param)
+		}
+	}
+	return headerArguments(parameters, taken)
+}
+
+// headerArgumentNames returns the argument names of an endpoint's header parameters
+func headerArgumentNames(endpoint *types.EndpointInfo) map[string]bool {
+	names := make(map[string]bool)
+	for _, header := range endpointHeaderArguments(endpoint) {
+		names[header.name] = true
+	}
+	return names
+}
+
+// setHeaderParams sets the header parameters given in params on req
+func setHeaderParams(req *http.Request, endpoint *types.EndpointInfo, params map[string]interface{}) {
+	for _, header := range endpointHeaderArguments(endpoint) {
+		if value, ok := formatParamValue(params[header.name]); ok {
+			req.Header.Set(header.param.Name, value)
+		}
+	}
+}
+
+// headerParamDescription describes the argument of a header parameter
+func headerParamDescription(param *v2high.Parameter) string {
+	if param.Description != "" {
+		return param.Description
+	}
+	return fmt.Sprintf("Header parameter: %s", param.Name)
+}
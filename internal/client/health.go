@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// AuthError reports that Quay rejected the configured credentials
+type AuthError struct {
+	StatusCode int
+}
+
+func (e *AuthError) Error() string {
+	if e.StatusCode == http.StatusForbidden {
+		return fmt.Sprintf("Quay refused the configured credentials (status %d): the token lacks the required scope", e.StatusCode)
+	}
+	return fmt.Sprintf("Quay rejected the configured credentials (status %d): the token is missing, invalid or expired", e.StatusCode)
+}
+
+// HealthCheck verifies that the registry is reachable and accepts the configured token by
+// fetching the authenticated user. Rejected credentials are reported as an *AuthError.
+func (c *QuayClient) HealthCheck(ctx context.Context) error {
+	req, err := c.newRequest(ctx, http.MethodGet, strings.TrimRight(c.registryURL, "/")+"/api/v1/user/", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	if _, err := c.executeRequest(req); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+			return &AuthError{StatusCode: apiErr.StatusCode}
+		}
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	v2high "github.com/pb33f/libopenapi/datamodel/high/v2"
+	v3high "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+// linksExtension is the operation extension carrying the OpenAPI 3 links of an operation's
+// success responses through the conversion to Swagger 2.0, which has no links
+const linksExtension = "x-links"
+
+// operationLink is a link from an operation's response to a related operation
+type operationLink struct {
+	Name         string            `json:"name" yaml:"name"`
+	OperationID  string            `json:"operationId,omitempty" yaml:"operationId,omitempty"`
+	OperationRef string            `json:"operationRef,omitempty" yaml:"operationRef,omitempty"`
+	Parameters   map[string]string `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Description  string            `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// convertLinks collects the links of the 2xx responses of an OpenAPI 3 operation, keeping the
+// first link of each name
+func convertLinks(responses *v3high.Responses) []operationLink {
+	if responses == nil || responses.Codes == nil {
+		return nil
+	}
+
+	var links []operationLink
+	seen := make(map[string]bool)
+	for codePair := responses.Codes.First(); codePair != nil; codePair = codePair.Next() {
+		response := codePair.Value()
+		if !strings.HasPrefix(codePair.Key(), "2") || response == nil || response.Links == nil {
+			continue
+		}
+		for linkPair := response.Links.First(); linkPair != nil; linkPair = linkPair.Next() {
+			link := linkPair.Value()
+			if link == nil || seen[linkPair.Key()] {
+				continue
+			}
+			seen[linkPair.Key()] = true
+
+			converted := operationLink{
+				Name:         linkPair.Key(),
+				OperationID:  link.OperationId,
+				OperationRef: link.OperationRef,
+				Description:  link.Description,
+			}
+			if link.Parameters != nil {
+				converted.Parameters = make(map[string]string)
+				for param := link.Parameters.First(); param != nil; param = param.Next() {
+					converted.Parameters[param.Key()] = param.Value()
+				}
+			}
+			links = append(links, converted)
+		}
+	}
+	return links
+}
+
+// operationLinks returns the links an operation declares in its links extension
+func operationLinks(operation *v2high.Operation) []operationLink {
+	if operation.Extensions == nil {
+		return nil
+	}
+	node, ok := operation.Extensions.Get(linksExtension)
+	if !ok || node == nil {
+		return nil
+	}
+
+	var links []operationLink
+	if err := node.Decode(&links); err != nil {
+		slog.Warn("Ignoring malformed operation links", "operation", operation.OperationId, "error", err)
+		return nil
+	}
+	return links
+}
+
+// linkDescriptionText returns the hints appended to a tool's description for the operations its
+// responses link to, naming the tool to call next and where its arguments come from
+func (c *QuayClient) linkDescriptionText(operation *v2high.Operation) string {
+	links := operationLinks(operation)
+	if len(links) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, link := range links {
+		target := c.linkTarget(link)
+		if target == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "\n- %s: call %s", link.Name, target)
+
+		names := make([]string, 0, len(link.Parameters))
+		for name := range link.Parameters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for i, name := range names {
+			separator := ", "
+			if i == 0 {
+				separator = " with "
+			}
+			fmt.Fprintf(&b, "%s%s from %s", separator, name, link.Parameters[name])
+		}
+		if link.Description != "" {
+			fmt.Fprintf(&b, " (%s)", link.Description)
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "\nRelated operations:" + b.String()
+}
+
+// linkTarget returns the name of the tool a link points to, or its operationId or operationRef
+// when the target isn't in the spec
+func (c *QuayClient) linkTarget(link operationLink) string {
+	if c.model != nil && hasPaths(c.model) {
+		refPath, refMethod := operationRefTarget(link.OperationRef)
+		for pathPair := c.model.Model.Paths.PathItems.First(); pathPair != nil; pathPair = pathPair.Next() {
+			for method, operation := range pathOperations(pathPair.Value()) {
+				matchesID := link.OperationID != "" && operation.OperationId == link.OperationID
+				matchesRef := refPath != "" && pathPair.Key() == refPath && method == refMethod
+				if matchesID || matchesRef {
+					return c.ToolName(method, operation.OperationId, pathPair.Key(), operation.Tags)
+				}
+			}
+		}
+	}
+
+	if link.OperationID != "" {
+		return link.OperationID
+	}
+	return link.OperationRef
+}
+
+// operationRefTarget returns the path and method of a local operationRef such as
+// #/paths/~1api~1v1~1repository~1{repository}/get, or empty strings for other references
+func operationRefTarget(ref string) (string, string) {
+	pointer, ok := strings.CutPrefix(ref, "#/paths/")
+	if !ok {
+		return "", ""
+	}
+	escapedPath, method, ok := strings.Cut(pointer, "/")
+	if !ok {
+		return "", ""
+	}
+	path := strings.NewReplacer("~1", "/", "~0", "~").Replace(escapedPath)
+	return path, strings.ToUpper(method)
+}
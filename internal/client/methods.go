@@ -0,0 +1,157 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	v2high "github.com/pb33f/libopenapi/datamodel/high/v2"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// supportedMethods are the HTTP methods that can be exposed as tools, in generation order
+var supportedMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// methodOperation is an operation of a path item together with its HTTP method
+type methodOperation struct {
+	Method    string
+	Operation *v2high.Operation
+}
+
+// SetMethods sets the HTTP methods whose operations become tools. Only GET is exposed by default,
+// since the other methods modify the registry.
+func (c *QuayClient) SetMethods(methods []string) error {
+	enabled, err := methodSet(methods)
+	if err != nil {
+		return err
+	}
+	c.methods = enabled
+	return nil
+}
+
+// SetTagMethods sets the HTTP methods enabled for operations carrying a tag, replacing the global
+// methods for them. An operation with several configured tags needs every one of them to enable
+// its method, so a read-only tag can't be widened by another tag on the same operation.
+func (c *QuayClient) SetTagMethods(tagMethods map[string][]string) error {
+	c.tagMethods = nil
+	for tag, methods := range tagMethods {
+		enabled, err := methodSet(methods)
+		if err != nil {
+			return fmt.Errorf("tag %s: %w", tag, err)
+		}
+		if c.tagMethods == nil {
+			c.tagMethods = make(map[string]map[string]bool)
+		}
+		c.tagMethods[tag] = enabled
+	}
+	return nil
+}
+
+// methodSet validates a list of HTTP methods and returns them as an uppercase set
+func methodSet(methods []string) (map[string]bool, error) {
+	enabled := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		method = strings.ToUpper(method)
+		supported := false
+		for _, m := range supportedMethods {
+			supported = supported || m == method
+		}
+		if !supported {
+			return nil, fmt.Errorf("unsupported method %q (expected one of %s)", method, strings.Join(supportedMethods, ", "))
+		}
+		enabled[method] = true
+	}
+	return enabled, nil
+}
+
+// methodEnabled reports whether an operation with the given method and tags becomes a tool: the
+// per-tag methods decide when any of its tags has them, the global methods otherwise
+func (c *QuayClient) methodEnabled(method string, tags []string) bool {
+	configured := false
+	for _, tag := range tags {
+		if enabled, ok := c.tagMethods[tag]; ok {
+			if !enabled[method] {
+				return false
+			}
+			configured = true
+		}
+	}
+	return configured || c.methods[method]
+}
+
+// enabledOperations returns the operations of a path item whose methods are enabled, in
+// supportedMethods order
+func (c *QuayClient) enabledOperations(pathItem *v2high.PathItem) []methodOperation {
+	operations := pathOperations(pathItem)
+
+	var enabled []methodOperation
+	for _, method := range supportedMethods {
+		if operation, ok := operations[method]; ok && c.methodEnabled(method, operation.Tags) {
+			enabled = append(enabled, methodOperation{Method: method, Operation: operation})
+		}
+	}
+	return enabled
+}
+
+// endpointKey is the key of an endpoint in the endpoints map: its quay:// URI, prefixed with the
+// method for anything but GET so operations sharing a path don't overwrite each other
+func endpointKey(method, path string) string {
+	uri := fmt.Sprintf("quay://%s", strings.TrimPrefix(path, "/"))
+	if method == http.MethodGet {
+		return uri
+	}
+	return method + " " + uri
+}
+
+// hasRequestBody reports whether calls to an endpoint send their non-path, non-query arguments as
+// a JSON body instead of the query string
+func hasRequestBody(endpoint *types.EndpointInfo) bool {
+	switch endpoint.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return endpointBodyParameter(endpoint) != nil
+}
+
+// queryParamNames returns the names of the query parameters an endpoint declares
+func queryParamNames(endpoint *types.EndpointInfo) map[string]bool {
+	names := make(map[string]bool)
+	for _, p := range endpoint.Parameters {
+		if param, ok := p.(*v2high.Parameter); ok && param.In == "query" {
+			names[param.Name] = true
+		}
+	}
+	return names
+}
+
+// requestBody returns the value sent as the JSON body of a call: the "body" argument when the
+// body schema declares no properties, otherwise every argument that isn't a path parameter, a
+// declared query or header parameter or resource_uri
+func requestBody(endpoint *types.EndpointInfo, params map[string]interface{}) interface{} {
+	if !hasRequestBody(endpoint) {
+		return nil
+	}
+	if param := endpointBodyParameter(endpoint); param != nil && bodyProperties(param) == nil {
+		if raw, ok := params[rawBodyArgument]; ok {
+			return raw
+		}
+	}
+
+	excluded := queryParamNames(endpoint)
+	excluded["resource_uri"] = true
+	for name := range headerArgumentNames(endpoint) {
+		excluded[name] = true
+	}
+	for _, name := range extractPathParameterNames(endpoint.Path) {
+		excluded[name] = true
+	}
+
+	body := make(map[string]interface{})
+	for key, value := range params {
+		if !excluded[key] {
+			body[key] = value
+		}
+	}
+	return body
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	v2high "github.com/pb33f/libopenapi/datamodel/high/v2"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// namespaceParams are the parameter names that identify a Quay namespace
+var namespaceParams = []string{"namespace", "orgname"}
+
+// currentUser is the subset of Quay's /api/v1/user/ response used for namespace inference
+type currentUser struct {
+	Username      string `json:"username"`
+	Organizations []struct {
+		Name string `json:"name"`
+	} `json:"organizations"`
+}
+
+// SetDefaultNamespace sets the namespace used for namespace/orgname parameters the caller omits
+func (c *QuayClient) SetDefaultNamespace(namespace string) {
+	c.defaultNamespace = namespace
+}
+
+// GetDefaultNamespace returns the namespace used for omitted namespace/orgname parameters
+func (c *QuayClient) GetDefaultNamespace() string {
+	return c.defaultNamespace
+}
+
+// InferNamespace looks up the authenticated user and returns their primary organization,
+// falling back to the username when the user belongs to no organization
+func (c *QuayClient) InferNamespace() (string, error) {
+	if c.oauthToken == "" {
+		return "", fmt.Errorf("an OAuth token is required to infer the namespace")
+	}
+
+	req, err := c.newRequest(context.Background(), http.MethodGet, c.registryURL+"/api/v1/user/", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch current user: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read current user: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch current user: status code %d", resp.StatusCode)
+	}
+
+	var user currentUser
+	if err := json.Unmarshal(body, &user); err != nil {
+		return "", fmt.Errorf("failed to parse current user: %w", err)
+	}
+
+	if len(user.Organizations) > 0 && user.Organizations[0].Name != "" {
+		slog.Info("Inferred namespace from the first organization", "namespace", user.Organizations[0].Name, "organizations", len(user.Organizations))
+		return user.Organizations[0].Name, nil
+	}
+	if user.Username != "" {
+		slog.Info("Inferred namespace from the authenticated username", "namespace", user.Username)
+		return user.Username, nil
+	}
+	return "", fmt.Errorf("current user response contains no organization or username")
+}
+
+// ApplyNamespaceDefault returns params with the default namespace filled in for any
+// namespace/orgname parameter the endpoint declares but the caller omitted
+func (c *QuayClient) ApplyNamespaceDefault(endpoint *types.EndpointInfo, params map[string]interface{}) map[string]interface{} {
+	if c.defaultNamespace == "" {
+		return params
+	}
+
+	declared := declaredParams(endpoint)
+
+	var filled map[string]interface{}
+	for _, name := range namespaceParams {
+		if !declared[name] {
+			continue
+		}
+		if value, ok := params[name].(string); ok && value != "" {
+			continue
+		}
+		if filled == nil {
+			filled = make(map[string]interface{}, len(params)+1)
+			for k, v := range params {
+				filled[k] = v
+			}
+		}
+		filled[name] = c.defaultNamespace
+		slog.Debug("Defaulting parameter to the inferred namespace", "parameter", name, "namespace", c.defaultNamespace)
+	}
+
+	if filled == nil {
+		return params
+	}
+	return filled
+}
+
+// declaredParams returns the names of the path placeholders and spec parameters of an endpoint
+func declaredParams(endpoint *types.EndpointInfo) map[string]bool {
+	declared := make(map[string]bool)
+	for _, name := range extractPathParameterNames(endpoint.Path) {
+		declared[name] = true
+	}
+	for _, p := range endpoint.Parameters {
+		if param, ok := p.(*v2high.Parameter); ok {
+			declared[param.Name] = true
+		}
+	}
+	return declared
+}
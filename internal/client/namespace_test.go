@@ -0,0 +1,77 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+func TestInferNamespace(t *testing.T) {
+	var gotNamespace string
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/user/":
+			w.Write([]byte(`{"username": "jdoe", "organizations": [{"name": "acme"}, {"name": "other"}]}`))
+		case "/api/v1/organization/acme":
+			gotNamespace = "acme"
+			w.Write([]byte(`{"name": "acme"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := NewQuayClient(mockServer.URL, "test-token")
+
+	namespace, err := client.InferNamespace()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if namespace != "acme" {
+		t.Fatalf("Expected namespace 'acme', got '%s'", namespace)
+	}
+
+	client.SetDefaultNamespace(namespace)
+
+	endpoint := &types.EndpointInfo{
+		Method: "GET",
+		Path:   "/api/v1/organization/{orgname}",
+	}
+	params := map[string]interface{}{}
+	if _, err := client.MakeAPICallWithParams(endpoint, params); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if gotNamespace != "acme" {
+		t.Errorf("Expected orgname to default to 'acme'")
+	}
+	if _, exists := params["orgname"]; exists {
+		t.Errorf("Expected caller's parameters not to be modified")
+	}
+}
+
+func TestInferNamespaceFallsBackToUsername(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"username": "jdoe", "organizations": []}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewQuayClient(mockServer.URL, "test-token")
+
+	namespace, err := client.InferNamespace()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if namespace != "jdoe" {
+		t.Errorf("Expected namespace 'jdoe', got '%s'", namespace)
+	}
+}
@@ -0,0 +1,300 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v3high "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// isOpenAPI3 reports whether a parsed document is an OpenAPI 3.x specification
+func isOpenAPI3(document libopenapi.Document) bool {
+	format := document.GetSpecInfo().SpecFormat
+	return format == datamodel.OAS3 || format == datamodel.OAS31
+}
+
+// convertOpenAPI3 translates an OpenAPI 3.x document into the equivalent Swagger 2.0 document, so
+// endpoint discovery, tool generation and URL building work on a single model whichever version
+// the registry serves. The first server URL provides the host, scheme and base path, path-level
+// parameters are merged into each operation, a JSON request body becomes a body parameter,
+// schema references are inlined and the links of success responses move to an x-links extension.
+func convertOpenAPI3(document libopenapi.Document) ([]byte, error) {
+	model, errs := document.BuildV3Model()
+	for _, err := range errs {
+		slog.Warn("Error while building OpenAPI 3 model", "error", err)
+	}
+	if model == nil {
+		return nil, fmt.Errorf("failed to build OpenAPI 3 model")
+	}
+	doc := &model.Model
+
+	swagger := map[string]interface{}{"swagger": "2.0"}
+	if doc.Info != nil {
+		swagger["info"] = map[string]interface{}{"title": doc.Info.Title, "version": doc.Info.Version}
+	}
+	if len(doc.Servers) > 1 {
+		// The Swagger 2.0 model has a single host and base path, so the other servers are dropped
+		slog.Warn("The OpenAPI 3 spec lists several servers; only the first provides the API base path",
+			"used", doc.Servers[0].URL, "ignored", len(doc.Servers)-1)
+	}
+	if len(doc.Servers) > 0 {
+		scheme, host, basePath := serverLocation(doc.Servers[0])
+		if scheme != "" {
+			swagger["schemes"] = []string{scheme}
+		}
+		if host != "" {
+			swagger["host"] = host
+		}
+		if basePath != "" {
+			swagger["basePath"] = basePath
+		}
+	}
+
+	paths := make(map[string]interface{})
+	if doc.Paths != nil && doc.Paths.PathItems != nil {
+		for pathPair := doc.Paths.PathItems.First(); pathPair != nil; pathPair = pathPair.Next() {
+			paths[pathPair.Key()] = convertPathItem(pathPair.Value())
+		}
+	}
+	swagger["paths"] = paths
+
+	slog.Info("Converted OpenAPI 3 spec to Swagger 2.0", "version", doc.Version, "paths", len(paths))
+	return json.Marshal(swagger)
+}
+
+// serverLocation splits a server URL, with its variables set to their defaults, into scheme,
+// host and base path. Relative URLs such as /api/v1 only provide the base path.
+func serverLocation(server *v3high.Server) (string, string, string) {
+	serverURL := server.URL
+	if server.Variables != nil {
+		for variable := server.Variables.First(); variable != nil; variable = variable.Next() {
+			serverURL = strings.ReplaceAll(serverURL, "{"+variable.Key()+"}", variable.Value().Default)
+		}
+	}
+
+	parsed, err := url.Parse(serverURL)
+	if err != nil {
+		return "", "", ""
+	}
+	return parsed.Scheme, parsed.Host, strings.TrimSuffix(parsed.Path, "/")
+}
+
+// convertPathItem converts the operations of a path item, keyed by lowercase method
+func convertPathItem(pathItem *v3high.PathItem) map[string]interface{} {
+	operations := map[string]*v3high.Operation{
+		"get":    pathItem.Get,
+		"put":    pathItem.Put,
+		"post":   pathItem.Post,
+		"patch":  pathItem.Patch,
+		"delete": pathItem.Delete,
+	}
+
+	converted := make(map[string]interface{})
+	for method, operation := range operations {
+		if operation != nil {
+			converted[method] = convertOperation(operation, pathItem.Parameters)
+		}
+	}
+	return converted
+}
+
+// convertOperation converts an operation, merging in the path-level parameters it doesn't override
+func convertOperation(operation *v3high.Operation, pathParameters []*v3high.Parameter) map[string]interface{} {
+	converted := map[string]interface{}{
+		"operationId": operation.OperationId,
+		"summary":     operation.Summary,
+		"description": operation.Description,
+		"tags":        operation.Tags,
+	}
+
+	params := append([]*v3high.Parameter{}, operation.Parameters...)
+	overridden := make(map[string]bool)
+	for _, param := range operation.Parameters {
+		overridden[param.In+" "+param.Name] = true
+	}
+	for _, param := range pathParameters {
+		if !overridden[param.In+" "+param.Name] {
+			params = append(params, param)
+		}
+	}
+
+	// Cookie parameters have no Swagger 2.0 equivalent
+	var parameters []interface{}
+	for _, param := range params {
+		if param != nil && param.In != "cookie" {
+			parameters = append(parameters, convertParameter(param))
+		}
+	}
+	if body := convertRequestBody(operation.RequestBody); body != nil {
+		parameters = append(parameters, body)
+	}
+	if parameters != nil {
+		converted["parameters"] = parameters
+	}
+
+	if operation.Responses != nil && operation.Responses.Codes != nil {
+		responses := make(map[string]interface{})
+		produces := make(map[string]bool)
+		var producesList []string
+		for codePair := operation.Responses.Codes.First(); codePair != nil; codePair = codePair.Next() {
+			responses[codePair.Key()] = convertResponse(codePair.Value())
+			for mediaType := range mediaTypes(codePair.Value().Content) {
+				if !produces[mediaType] {
+					produces[mediaType] = true
+					producesList = append(producesList, mediaType)
+				}
+			}
+		}
+		converted["responses"] = responses
+		if producesList != nil {
+			converted["produces"] = producesList
+		}
+		if links := convertLinks(operation.Responses); links != nil {
+			converted[linksExtension] = links
+		}
+	}
+	return converted
+}
+
+// convertParameter converts a path, query or header parameter, taking its type from its schema
+func convertParameter(param *v3high.Parameter) map[string]interface{} {
+	converted := map[string]interface{}{
+		"name":        param.Name,
+		"in":          param.In,
+		"description": param.Description,
+		"required":    param.Required != nil && *param.Required,
+	}
+
+	if param.Schema == nil {
+		converted["type"] = "string"
+		return converted
+	}
+	schema := param.Schema.Schema()
+	if schema == nil {
+		converted["type"] = "string"
+		return converted
+	}
+	converted["type"] = schemaType(schema)
+	if schema.Format != "" {
+		converted["format"] = schema.Format
+	}
+	if schema.Items != nil && schema.Items.IsA() {
+		if items := schema.Items.A.Schema(); items != nil {
+			converted["items"] = map[string]interface{}{"type": schemaType(items)}
+		}
+	}
+	return converted
+}
+
+// schemaType returns the first non-null type of a schema, defaulting to string
+func schemaType(schema *base.Schema) string {
+	for _, t := range schema.Type {
+		if t != "null" {
+			return t
+		}
+	}
+	return "string"
+}
+
+// convertRequestBody converts a JSON request body into a body parameter, or returns nil when the
+// operation has no JSON body
+func convertRequestBody(body *v3high.RequestBody) map[string]interface{} {
+	if body == nil {
+		return nil
+	}
+	mediaType := jsonMediaType(body.Content)
+	if mediaType == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"name":        rawBodyArgument,
+		"in":          "body",
+		"description": body.Description,
+		"required":    body.Required != nil && *body.Required,
+		"schema":      inlineSchema(mediaType.Schema),
+	}
+}
+
+// convertResponse converts a response, keeping its JSON schema and example
+func convertResponse(response *v3high.Response) map[string]interface{} {
+	converted := map[string]interface{}{"description": response.Description}
+	mediaType := jsonMediaType(response.Content)
+	if mediaType == nil {
+		return converted
+	}
+	if mediaType.Schema != nil {
+		converted["schema"] = inlineSchema(mediaType.Schema)
+	}
+	if example := exampleValue(mediaType); example != nil {
+		converted["examples"] = map[string]interface{}{"application/json": example}
+	}
+	return converted
+}
+
+// jsonMediaType returns the JSON entry of a content map, or nil when it has none
+func jsonMediaType(content *orderedmap.Map[string, *v3high.MediaType]) *v3high.MediaType {
+	for mediaType, value := range mediaTypes(content) {
+		if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+			return value
+		}
+	}
+	return nil
+}
+
+// mediaTypes returns the entries of a content map
+func mediaTypes(content *orderedmap.Map[string, *v3high.MediaType]) map[string]*v3high.MediaType {
+	types := make(map[string]*v3high.MediaType)
+	if content == nil {
+		return types
+	}
+	for pair := content.First(); pair != nil; pair = pair.Next() {
+		types[pair.Key()] = pair.Value()
+	}
+	return types
+}
+
+// inlineSchema renders a schema as JSON with its references resolved, falling back to an empty
+// schema when it can't be rendered (e.g. a circular reference)
+func inlineSchema(proxy *base.SchemaProxy) interface{} {
+	schema := proxy.Schema()
+	if schema == nil {
+		return map[string]interface{}{}
+	}
+	rendered, err := schema.MarshalJSONInline()
+	if err != nil {
+		slog.Warn("Failed to inline OpenAPI 3 schema", "error", err)
+		return map[string]interface{}{}
+	}
+	var value interface{}
+	if err := json.Unmarshal(rendered, &value); err != nil {
+		return map[string]interface{}{}
+	}
+	return value
+}
+
+// exampleValue returns the example of a media type, preferring the single example over the first
+// of its named examples
+func exampleValue(mediaType *v3high.MediaType) interface{} {
+	node := mediaType.Example
+	if node == nil && mediaType.Examples != nil {
+		if first := mediaType.Examples.First(); first != nil && first.Value() != nil {
+			node = first.Value().Value
+		}
+	}
+	if node == nil {
+		return nil
+	}
+
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return nil
+	}
+	return jsonCompatible(value)
+}
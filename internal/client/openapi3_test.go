@@ -0,0 +1,175 @@
+package client
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const openAPI3Spec = `{
+	"openapi": "3.0.3",
+	"info": {"title": "Quay", "version": "v1"},
+	"servers": [{"url": "https://{host}/api/v1", "variables": {"host": {"default": "quay.example.com"}}}],
+	"paths": {
+		"/repository/{repository}": {
+			"parameters": [{"$ref": "#/components/parameters/repository"}],
+			"get": {
+				"operationId": "getRepo",
+				"summary": "Get repository",
+				"tags": ["repository"],
+				"parameters": [
+					{"name": "includeTags", "in": "query", "schema": {"type": "boolean"}},
+					{"name": "session", "in": "cookie", "schema": {"type": "string"}}
+				],
+				"responses": {
+					"200": {
+						"description": "OK",
+						"content": {"application/json": {"example": {"name": "ubi9"}}}
+					}
+				}
+			},
+			"put": {
+				"operationId": "changeRepo",
+				"summary": "Change repository",
+				"tags": ["repository"],
+				"requestBody": {
+					"required": true,
+					"content": {"application/json": {"schema": {"$ref": "#/components/schemas/RepoUpdate"}}}
+				}
+			}
+		}
+	},
+	"components": {
+		"parameters": {
+			"repository": {"name": "repository", "in": "path", "required": true, "schema": {"type": "string"}}
+		},
+		"schemas": {
+			"RepoUpdate": {
+				"type": "object",
+				"required": ["description"],
+				"properties": {"description": {"type": "string", "description": "New description"}}
+			}
+		}
+	}
+}`
+
+func TestOpenAPI3Spec(t *testing.T) {
+	client := newTestClient(t, openAPI3Spec)
+	if err := client.SetMethods([]string{"GET", "PUT"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	client.DiscoverEndpoints()
+
+	if got := client.basePath(); got != "/api/v1" {
+		t.Errorf("Expected the base path from servers[], got %q", got)
+	}
+
+	get := client.GetEndpoints()["quay://repository/{repository}"]
+	if get == nil {
+		t.Fatalf("Expected the GET endpoint, got %v", client.GetEndpoints())
+	}
+	if get.ResponseExample == "" {
+		t.Error("Expected the response example to be kept")
+	}
+	apiURL, err := client.BuildAPIURLWithParams(get, map[string]interface{}{"repository": "ubi9", "includeTags": true})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := client.GetRegistryURL() + "/api/v1/repository/ubi9?includeTags=true"; apiURL != want {
+		t.Errorf("Expected %s, got %s", want, apiURL)
+	}
+
+	tools := make(map[string]map[string]any)
+	for _, tool := range client.GenerateTools() {
+		tools[tool.Name] = tool.InputSchema.Properties
+	}
+
+	// The path-level $ref parameter is merged in and typed query parameters are kept; cookies are dropped
+	getProps := tools["quay_getRepo"]
+	if _, ok := getProps["repository"]; !ok {
+		t.Errorf("Expected the path-level repository parameter, got %v", getProps)
+	}
+	if includeTags, _ := getProps["includeTags"].(map[string]any); includeTags["type"] != "boolean" {
+		t.Errorf("Expected a boolean includeTags parameter, got %v", getProps["includeTags"])
+	}
+	if _, ok := getProps["session"]; ok {
+		t.Errorf("Expected the cookie parameter to be dropped, got %v", getProps)
+	}
+
+	// The referenced request body schema becomes tool parameters
+	putProps := tools["quay_changeRepo"]
+	if description, _ := putProps["description"].(map[string]any); description["description"] != "New description" {
+		encoded, _ := json.Marshal(putProps)
+		t.Errorf("Expected the body's description field, got %s", encoded)
+	}
+}
+
+func TestOpenAPI3Links(t *testing.T) {
+	client := newTestClient(t, `{
+		"openapi": "3.0.3",
+		"info": {"title": "Quay", "version": "v1"},
+		"servers": [{"url": "/api/v1"}],
+		"paths": {
+			"/repository": {
+				"get": {
+					"operationId": "listRepos",
+					"summary": "List repositories",
+					"tags": ["repository"],
+					"responses": {
+						"200": {
+							"description": "OK",
+							"links": {
+								"GetRepository": {
+									"operationId": "getRepo",
+									"parameters": {"repository": "$response.body#/repositories/0/name"},
+									"description": "Details of a listed repository"
+								},
+								"GetTags": {"operationRef": "#/paths/~1repository~1{repository}~1tag/get"}
+							}
+						}
+					}
+				}
+			},
+			"/repository/{repository}": {
+				"get": {"operationId": "getRepo", "summary": "Get repository", "tags": ["repository"]}
+			},
+			"/repository/{repository}/tag": {
+				"get": {"summary": "List tags", "tags": ["repository"]}
+			}
+		}
+	}`)
+
+	var description string
+	for _, tool := range client.GenerateTools() {
+		if tool.Name == "quay_listRepos" {
+			description = tool.Description
+		}
+	}
+	for _, want := range []string{
+		"Related operations:",
+		"- GetRepository: call quay_getRepo with repository from $response.body#/repositories/0/name (Details of a listed repository)",
+		"- GetTags: call quay_repository_repository_tag",
+	} {
+		if !strings.Contains(description, want) {
+			t.Errorf("Expected the description to contain %q, got:\n%s", want, description)
+		}
+	}
+}
+
+func TestOpenAPI3MultipleServers(t *testing.T) {
+	logs := captureLogs(t)
+	client := newTestClient(t, `{
+		"openapi": "3.0.3",
+		"info": {"title": "Quay", "version": "v1"},
+		"servers": [{"url": "/api/v1"}, {"url": "https://mirror.example.com/api/v2"}],
+		"paths": {"/repository": {"get": {"operationId": "listRepos", "summary": "List repositories"}}}
+	}`)
+
+	// Only the first server is used, and the dropped one is reported
+	if got := client.basePath(); got != "/api/v1" {
+		t.Errorf("Expected the base path of the first server, got %q", got)
+	}
+	if !strings.Contains(logs.String(), "only the first provides the API base path") || !strings.Contains(logs.String(), "ignored=1") {
+		t.Errorf("Expected a warning about the ignored server, got:\n%s", logs.String())
+	}
+}
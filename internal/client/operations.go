@@ -0,0 +1,96 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	v2high "github.com/pb33f/libopenapi/datamodel/high/v2"
+)
+
+// untaggedGroup is the heading for operations without tags
+const untaggedGroup = "(untagged)"
+
+// operationSummary is one operation listed by WriteOperationsByTag
+type operationSummary struct {
+	Method  string
+	Path    string
+	Summary string
+}
+
+// pathOperations returns the operations of a path item keyed by HTTP method
+func pathOperations(pathItem *v2high.PathItem) map[string]*v2high.Operation {
+	operations := map[string]*v2high.Operation{
+		http.MethodGet:     pathItem.Get,
+		http.MethodPut:     pathItem.Put,
+		http.MethodPost:    pathItem.Post,
+		http.MethodDelete:  pathItem.Delete,
+		http.MethodOptions: pathItem.Options,
+		http.MethodHead:    pathItem.Head,
+		http.MethodPatch:   pathItem.Patch,
+	}
+	for method, operation := range operations {
+		if operation == nil {
+			delete(operations, method)
+		}
+	}
+	return operations
+}
+
+// WriteOperationsByTag writes every operation in the loaded spec, whatever the tag filters,
+// grouped under each of its tags. Tags are sorted, and operations within a tag by path and method.
+func (c *QuayClient) WriteOperationsByTag(w io.Writer) error {
+	model := c.GetModel()
+	if model == nil {
+		return fmt.Errorf("no swagger spec loaded")
+	}
+
+	groups := make(map[string][]operationSummary)
+	if hasPaths(model) {
+		for pathPair := model.Model.Paths.PathItems.First(); pathPair != nil; pathPair = pathPair.Next() {
+			for method, operation := range pathOperations(pathPair.Value()) {
+				summary := operationSummary{Method: method, Path: pathPair.Key(), Summary: operation.Summary}
+				tags := operation.Tags
+				if len(tags) == 0 {
+					tags = []string{untaggedGroup}
+				}
+				for _, tag := range tags {
+					groups[tag] = append(groups[tag], summary)
+				}
+			}
+		}
+	}
+
+	tags := make([]string, 0, len(groups))
+	for tag := range groups {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	for i, tag := range tags {
+		operations := groups[tag]
+		sort.Slice(operations, func(a, b int) bool {
+			if operations[a].Path != operations[b].Path {
+				return operations[a].Path < operations[b].Path
+			}
+			return operations[a].Method < operations[b].Method
+		})
+
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s (%d)\n", tag, len(operations))
+		for _, operation := range operations {
+			line := fmt.Sprintf("  %-7s %s", operation.Method, operation.Path)
+			if operation.Summary != "" {
+				line += "  " + operation.Summary
+			}
+			if _, err := fmt.Fprintln(w, strings.TrimRight(line, " ")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteOperationsByTag(t *testing.T) {
+	client := newTestClient(t, `{
+		"swagger": "2.0",
+		"paths": {
+			"/api/v1/repository": {
+				"get": {"operationId": "listRepos", "summary": "List repositories", "tags": ["repository"]},
+				"post": {"operationId": "createRepo", "summary": "Create a repository", "tags": ["repository"]}
+			},
+			"/api/v1/organization/{orgname}": {
+				"get": {"operationId": "getOrganization", "summary": "Get an organization", "tags": ["organization"]}
+			},
+			"/api/v1/discovery": {
+				"get": {"operationId": "discovery"}
+			}
+		}
+	}`)
+
+	var out bytes.Buffer
+	if err := client.WriteOperationsByTag(&out); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := `(untagged) (1)
+  GET     /api/v1/discovery
+
+organization (1)
+  GET     /api/v1/organization/{orgname}  Get an organization
+
+repository (2)
+  GET     /api/v1/repository  List repositories
+  POST    /api/v1/repository  Create a repository
+`
+	if out.String() != want {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// LoadParamMapping reads a JSON file mapping API parameter names to the names tools expose
+// instead, e.g. {"orgname": "organization"}
+func (c *QuayClient) LoadParamMapping(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read parameter mapping file: %v", err)
+	}
+
+	var mapping map[string]string
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return fmt.Errorf("invalid parameter mapping file %s: %v", path, err)
+	}
+	return c.SetParamMapping(mapping)
+}
+
+// SetParamMapping renames tool arguments: each API parameter name in mapping is exposed under its
+// tool name, and calls translate the tool name back. A parameter keeps its API name in tools that
+// already have a parameter with the tool name.
+func (c *QuayClient) SetParamMapping(mapping map[string]string) error {
+	toolNames := make(map[string]string, len(mapping))
+	apiNames := make(map[string]string, len(mapping))
+	for apiName, toolName := range mapping {
+		if apiName == "" || toolName == "" {
+			return fmt.Errorf("parameter mapping %q -> %q needs both names", apiName, toolName)
+		}
+		if other, ok := apiNames[toolName]; ok {
+			return fmt.Errorf("parameters %s and %s are both mapped to %s", other, apiName, toolName)
+		}
+		toolNames[apiName] = toolName
+		apiNames[toolName] = apiName
+	}
+	c.paramMapping = toolNames
+	return nil
+}
+
+// renameToolParams renames the parameters of a generated tool to their mapped tool names
+func (c *QuayClient) renameToolParams(tool mcp.Tool) mcp.Tool {
+	if len(c.paramMapping) == 0 {
+		return tool
+	}
+
+	properties := make(map[string]any, len(tool.InputSchema.Properties))
+	renamed := make(map[string]string)
+	for name, property := range tool.InputSchema.Properties {
+		toolName, ok := c.paramMapping[name]
+		if _, taken := tool.InputSchema.Properties[toolName]; ok && !taken {
+			renamed[name] = toolName
+			name = toolName
+		}
+		properties[name] = property
+	}
+	tool.InputSchema.Properties = properties
+
+	if len(renamed) > 0 {
+		required := make([]string, len(tool.InputSchema.Required))
+		for i, name := range tool.InputSchema.Required {
+			if toolName, ok := renamed[name]; ok {
+				name = toolName
+			}
+			required[i] = name
+		}
+		tool.InputSchema.Required = required
+	}
+	return tool
+}
+
+// APIParams translates the mapped tool argument names in params back to the API parameter names
+// of the endpoint. Arguments already using the API names are kept, and params is left untouched.
+func (c *QuayClient) APIParams(endpoint *types.EndpointInfo, params map[string]interface{}) map[string]interface{} {
+	if len(c.paramMapping) == 0 || len(params) == 0 {
+		return params
+	}
+
+	declared := endpointArgumentNames(endpoint)
+	translated := make(map[string]interface{}, len(params))
+	for name, value := range params {
+		translated[name] = value
+	}
+	for apiName, toolName := range c.paramMapping {
+		value, ok := params[toolName]
+		if !ok || !declared[apiName] || declared[toolName] {
+			continue
+		}
+		delete(translated, toolName)
+		if _, exists := params[apiName]; !exists {
+			translated[apiName] = value
+		}
+	}
+	return translated
+}
+
+// endpointArgumentNames returns the names of the arguments an endpoint's tool is generated with
+func endpointArgumentNames(endpoint *types.EndpointInfo) map[string]bool {
+	names := queryParamNames(endpoint)
+	names["resource_uri"] = true
+	for _, name := range extractPathParameterNames(endpoint.Path) {
+		names[name] = true
+	}
+	for name := range headerArgumentNames(endpoint) {
+		names[name] = true
+	}
+	if param := endpointBodyParameter(endpoint); param != nil {
+		if schema := bodyProperties(param); schema != nil {
+			for pair := schema.Properties.First(); pair != nil; pair = pair.Next() {
+				names[pair.Key()] = true
+			}
+		} else {
+			names[rawBodyArgument] = true
+		}
+	}
+	return names
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParamMapping(t *testing.T) {
+	var requested string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/discovery" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{
+				"swagger": "2.0",
+				"paths": {
+					"/api/v1/organization/{orgname}/robots": {
+						"get": {
+							"operationId": "getOrgRobots",
+							"tags": ["robot"],
+							"parameters": [
+								{"name": "orgname", "in": "path", "type": "string", "required": true},
+								{"name": "limit", "in": "query", "type": "integer"},
+								{"name": "max", "in": "query", "type": "integer"}
+							]
+						}
+					}
+				}
+			}`))
+			return
+		}
+		requested = r.URL.RequestURI()
+		w.Write([]byte(`{"robots": []}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewQuayClient(mockServer.URL, "")
+	if err := client.FetchSwaggerSpec(); err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	client.DiscoverEndpoints()
+
+	mappingFile := filepath.Join(t.TempDir(), "mapping.json")
+	if err := os.WriteFile(mappingFile, []byte(`{"orgname": "organization", "limit": "max"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LoadParamMapping(mappingFile); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tools := client.GenerateTools()
+	if len(tools) != 1 {
+		t.Fatalf("Expected 1 tool, got %d", len(tools))
+	}
+	schema := tools[0].InputSchema
+	if _, ok := schema.Properties["organization"]; !ok {
+		t.Errorf("Expected the tool to advertise organization, got %v", schema.Properties)
+	}
+	if _, ok := schema.Properties["orgname"]; ok {
+		t.Error("Expected orgname to be hidden behind its mapped name")
+	}
+	if len(schema.Required) != 1 || schema.Required[0] != "organization" {
+		t.Errorf("Expected organization to be required, got %v", schema.Required)
+	}
+	// max is already a parameter, so limit keeps its name
+	if _, ok := schema.Properties["limit"]; !ok {
+		t.Errorf("Expected limit to keep its name, got %v", schema.Properties)
+	}
+
+	endpoint := client.GetEndpoints()["quay://api/v1/organization/{orgname}/robots"]
+	if _, err := client.MakeAPICallWithParams(endpoint, map[string]interface{}{"organization": "redhat", "max": float64(5)}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if requested != "/api/v1/organization/redhat/robots?max=5" {
+		t.Errorf("Expected the request to use the API name, got %s", requested)
+	}
+
+	if err := client.SetParamMapping(map[string]string{"orgname": "org", "namespace": "org"}); err == nil {
+		t.Error("Expected an error for two parameters mapped to one name")
+	}
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// templateFuncs are the only functions a parameter template may call besides env: comparisons,
+// boolean logic and formatting. Notably call is excluded, so templates can't invoke arbitrary code.
+var templateFuncs = map[string]bool{
+	"and": true, "or": true, "not": true,
+	"eq": true, "ne": true, "lt": true, "le": true, "gt": true, "ge": true,
+	"len": true, "index": true, "print": true, "printf": true, "println": true, "urlquery": true,
+	"env": true,
+}
+
+// SetParamDefaults sets default values for parameters the caller omits. Each value is a
+// text/template rendered over the call's arguments, with env reading environment variables,
+// e.g. namespace={{.org}} or namespace={{env "QUAY_NAMESPACE"}}.
+func (c *QuayClient) SetParamDefaults(defaults map[string]string) error {
+	parsed := make(map[string]*template.Template, len(defaults))
+	for name, text := range defaults {
+		tmpl, err := template.New(name).
+			Funcs(template.FuncMap{"env": os.Getenv}).
+			Option("missingkey=error").
+			Parse(text)
+		if err != nil {
+			return fmt.Errorf("invalid default for parameter %s: %v", name, err)
+		}
+		if err := checkTemplateFuncs(tmpl.Tree.Root); err != nil {
+			return fmt.Errorf("invalid default for parameter %s: %v", name, err)
+		}
+		parsed[name] = tmpl
+	}
+	c.paramDefaults = parsed
+	return nil
+}
+
+// checkTemplateFuncs rejects templates calling functions outside templateFuncs
+func checkTemplateFuncs(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkTemplateFuncs(child); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return checkTemplateFuncs(n.Pipe)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			if err := checkTemplateFuncs(cmd); err != nil {
+				return err
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if err := checkTemplateFuncs(arg); err != nil {
+				return err
+			}
+		}
+	case *parse.IdentifierNode:
+		if !templateFuncs[n.Ident] {
+			return fmt.Errorf("function %q is not allowed", n.Ident)
+		}
+	case *parse.IfNode:
+		return checkBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		return checkBranch(&n.BranchNode)
+	case *parse.WithNode:
+		return checkBranch(&n.BranchNode)
+	case *parse.TemplateNode:
+		return fmt.Errorf("nested templates are not allowed")
+	}
+	return nil
+}
+
+// checkBranch checks the pipeline and both branches of an if, range or with node
+func checkBranch(branch *parse.BranchNode) error {
+	for _, node := range []parse.Node{branch.Pipe, branch.List, branch.ElseList} {
+		if err := checkTemplateFuncs(node); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyParamDefaults returns params with the templated defaults rendered for every parameter the
+// endpoint declares but the caller omitted. Defaults that fail to render are left out.
+func (c *QuayClient) applyParamDefaults(endpoint *types.EndpointInfo, params map[string]interface{}) map[string]interface{} {
+	if len(c.paramDefaults) == 0 {
+		return params
+	}
+
+	declared := declaredParams(endpoint)
+
+	var filled map[string]interface{}
+	for name, tmpl := range c.paramDefaults {
+		if !declared[name] {
+			continue
+		}
+		if value, ok := formatParamValue(params[name]); ok && value != "" {
+			continue
+		}
+
+		var rendered strings.Builder
+		if err := tmpl.Execute(&rendered, params); err != nil {
+			slog.Warn("Not defaulting parameter", "parameter", name, "error", err)
+			continue
+		}
+		if rendered.Len() == 0 {
+			continue
+		}
+
+		if filled == nil {
+			filled = make(map[string]interface{}, len(params)+1)
+			for k, v := range params {
+				filled[k] = v
+			}
+		}
+		filled[name] = rendered.String()
+		slog.Debug("Defaulting parameter", "parameter", name, "value", rendered.String())
+	}
+
+	if filled == nil {
+		return params
+	}
+	return filled
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	v2high "github.com/pb33f/libopenapi/datamodel/high/v2"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+func TestParamDefaultTemplates(t *testing.T) {
+	var query string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"repositories": []}`))
+	}))
+	defer mockServer.Close()
+
+	t.Setenv("QUAY_TEST_PUBLIC", "true")
+
+	endpoint := &types.EndpointInfo{
+		Method: "GET",
+		Path:   "/repository",
+		Parameters: []interface{}{
+			&v2high.Parameter{Name: "namespace", In: "query"},
+			&v2high.Parameter{Name: "public", In: "query"},
+		},
+	}
+	client := NewQuayClient(mockServer.URL, "")
+	if err := client.SetParamDefaults(map[string]string{
+		"namespace": "{{.org}}",
+		"public":    `{{env "QUAY_TEST_PUBLIC"}}`,
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The namespace default is derived from the org argument
+	if _, err := client.MakeAPICallWithParams(endpoint, map[string]interface{}{"org": "redhat"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{"namespace=redhat", "public=true"} {
+		if !containsQueryPart(query, want) {
+			t.Errorf("Expected %s in query %q", want, query)
+		}
+	}
+
+	// Explicit arguments win over defaults
+	if _, err := client.MakeAPICallWithParams(endpoint, map[string]interface{}{"org": "redhat", "namespace": "quay"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !containsQueryPart(query, "namespace=quay") {
+		t.Errorf("Expected the explicit namespace in query %q", query)
+	}
+
+	if err := client.SetParamDefaults(map[string]string{"namespace": `{{call .fn}}`}); err == nil {
+		t.Error("Expected templates using call to be rejected")
+	}
+}
+
+// containsQueryPart reports whether a raw query contains the key=value pair
+func containsQueryPart(query, part string) bool {
+	for _, p := range strings.Split(query, "&") {
+		if p == part {
+			return true
+		}
+	}
+	return false
+}
@@ -1,146 +1,436 @@
 package client
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"regexp"
+	"sort"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/pb33f/libopenapi"
 	v2high "github.com/pb33f/libopenapi/datamodel/high/v2"
+	"gopkg.in/yaml.v3"
 
+	"github.com/quay/quay-mcp-server/internal/retry"
 	"github.com/quay/quay-mcp-server/internal/types"
 )
 
+// DefaultAllowedTags are the Quay API tags whose endpoints are exposed unless configured otherwise
+var DefaultAllowedTags = []string{"manifest", "organization", "repository", "robot", "tag"}
+
+// DefaultManifestAccept are the media types requested from manifest endpoints: OCI and Docker v2
+// manifests and indexes, with JSON as a fallback
+var DefaultManifestAccept = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/json",
+}
+
+// DefaultMaxURLLength is a conservative request URL limit accepted by common servers and proxies
+const DefaultMaxURLLength = 8192
+
+// DefaultRetryStatuses are the transient response statuses retried unless configured otherwise
+var DefaultRetryStatuses = []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+// DefaultMaxRetries is how many times a request is retried after a retryable status
+const DefaultMaxRetries = 2
+
+// DefaultConnectionRetries is how many times an idempotent request is retried after a connection error
+const DefaultConnectionRetries = 2
+
+// DefaultTimeout bounds each HTTP request to Quay, so a hung instance can't block the server
+const DefaultTimeout = 30 * time.Second
+
 // QuayClient handles all interactions with the Quay registry API
 type QuayClient struct {
 	registryURL string
+	specURL     string // base URL serving the discovery document, if different from registryURL
 	oauthToken  string
 	document    libopenapi.Document
 	model       *libopenapi.DocumentModel[v2high.Swagger]
 	endpoints   map[string]*types.EndpointInfo // URI -> EndpointInfo mapping
+
+	authMode     string // how requests send the token, one of AuthModes (empty is bearer)
+	authUsername string // username sent with the token in the basic auth mode
+	authHeader   string // header carrying the token in the header auth mode
+
+	strictParams     bool     // reject arguments that aren't declared parameters
+	defaultNamespace string   // fills namespace/orgname when the caller omits them
+	maxResponseBytes int64    // maximum response body size (0 means unlimited)
+	requestIDHeader  string   // header carrying the correlation ID on outbound requests
+	jsonOnly         bool     // only expose endpoints that produce application/json
+	maxURLLength     int      // maximum length of a built request URL (0 means unlimited)
+	maxParamLength   int      // maximum length of a single argument value (0 means unlimited)
+	allowedTags      []string // tags whose endpoints are exposed (nil disables tag filtering)
+	disabledTags     []string // tags removed from the allowed set
+	retryEmpty200    bool     // retry once when a 200 response has an empty body
+	validateWrites   bool     // probe write calls before sending them
+
+	httpClient         *http.Client // shared client for every request to Quay
+	caCertFile         string       // PEM bundle trusted in addition to the system roots
+	insecureSkipVerify bool         // skip TLS certificate verification
+
+	retryStatuses map[int]bool  // response statuses that trigger a retry
+	retryBackoff  time.Duration // delay before the first retry, doubled for each further attempt
+	maxRetries    int           // retries after a retryable status
+	connRetries   int           // retries of idempotent requests after a connection error
+	rateLimiter   *rateLimiter  // limits the request rate to Quay (nil is unlimited)
+
+	paramTypeHints  map[string]string // parameter name -> JSON schema type for untyped parameters
+	tagDescriptions map[string]string // tag -> description appended to tools with that tag
+	paramMapping    map[string]string // API parameter name -> the name tools expose it under
+
+	slowCallThreshold time.Duration // log a warning for calls slower than this (0 disables)
+
+	tagPrefixes      map[string]string // tag -> prefix inserted after the tool prefix in tool names
+	toolNameFromPath bool              // name tools after their path even when they have an operation ID
+
+	toolPrefix string // prefix of every tool name (DefaultToolPrefix unless configured)
+
+	specFile      string      // local spec file loaded instead of fetching the discovery document
+	specTransform interface{} // JSON merge patch applied to the raw spec before parsing
+
+	endpointCacheFile   string        // file the discovered endpoints and tools are cached in
+	endpointCacheMaxAge time.Duration // age after which the endpoint cache is rebuilt (0 never expires)
+	cachedBasePath      string        // spec base path restored from the endpoint cache
+	endpointCacheLoaded bool          // endpoints and tools came from the endpoint cache
+
+	endpointAllowlist map[string]bool // "METHOD path" entries that may become tools (nil allows all)
+
+	traceHeaders []string // response headers to log (empty logs all of them)
+
+	noFallbackDescription bool // skip tools without a summary or description instead of using "METHOD {path}"
+
+	paramDefaults map[string]*template.Template // templated defaults for omitted parameters
+
+	manifestAccept []string // Accept media types for manifest-tagged endpoints (empty uses application/json)
+
+	methods    map[string]bool            // HTTP methods whose operations become tools
+	tagMethods map[string]map[string]bool // per-tag HTTP methods overriding methods for tagged operations
+
+	successStatuses []StatusRange // response statuses that count as success
 }
 
 // NewQuayClient creates a new Quay client for the given registry URL and optional OAuth token
 func NewQuayClient(registryURL, oauthToken string) *QuayClient {
-	return &QuayClient{
+	client := &QuayClient{
 		registryURL: strings.TrimRight(registryURL, "/"),
 		oauthToken:  oauthToken,
 		endpoints:   make(map[string]*types.EndpointInfo),
+
+		allowedTags:  DefaultAllowedTags,
+		maxURLLength: DefaultMaxURLLength,
+
+		httpClient:   &http.Client{Timeout: DefaultTimeout},
+		retryBackoff: 500 * time.Millisecond,
+		maxRetries:   DefaultMaxRetries,
+		connRetries:  DefaultConnectionRetries,
+
+		manifestAccept: DefaultManifestAccept,
+
+		toolPrefix: DefaultToolPrefix,
+
+		methods: map[string]bool{http.MethodGet: true},
+
+		successStatuses: DefaultSuccessStatuses,
 	}
+	client.SetRetryStatuses(DefaultRetryStatuses)
+	return client
 }
 
 // FetchSwaggerSpec fetches and parses the Swagger specification from the Quay registry
 func (c *QuayClient) FetchSwaggerSpec() error {
+	// A spec file or URL replaces discovery entirely
+	if c.specFile != "" {
+		return c.LoadSpecFromFile(c.specFile)
+	}
+
+	// The discovery document comes from the registry unless a separate spec URL is configured
+	specBase := c.registryURL
+	if c.specURL != "" {
+		specBase = c.specURL
+	}
+
 	// Construct the discovery URL - try /api/v1/discovery first, then fall back to /discovery
-	discoveryURL := strings.TrimSuffix(c.registryURL, "/") + "/api/v1/discovery"
+	discoveryURL := strings.TrimSuffix(specBase, "/") + "/api/v1/discovery"
 
-	log.Printf("=== FETCHING SWAGGER SPEC ===")
-	log.Printf("Registry URL: %s", c.registryURL)
-	log.Printf("Discovery URL: %s", discoveryURL)
+	slog.Debug("Fetching Swagger spec", "registry_url", c.registryURL, "spec_url", c.specURL, "discovery_url", discoveryURL)
 
-	resp, err := http.Get(discoveryURL)
+	resp, err := c.getWithRetry(discoveryURL)
 	if err != nil {
-		log.Printf("Failed to fetch from primary discovery URL: %v", err)
+		slog.Error("Failed to fetch from primary discovery URL", "url", discoveryURL, "error", err)
 		return fmt.Errorf("failed to fetch swagger spec: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// If /api/v1/discovery fails with 404, try /discovery as fallback
 	if resp.StatusCode == 404 {
-		log.Printf("Primary discovery URL returned 404, trying fallback...")
-		discoveryURL = strings.TrimSuffix(c.registryURL, "/") + "/discovery"
-		log.Printf("Fallback URL: %s", discoveryURL)
+		discoveryURL = strings.TrimSuffix(specBase, "/") + "/discovery"
+		slog.Info("Primary discovery URL returned 404, trying fallback", "url", discoveryURL)
 
-		resp, err = http.Get(discoveryURL)
+		resp, err = c.getWithRetry(discoveryURL)
 		if err != nil {
-			log.Printf("Failed to fetch from fallback discovery URL: %v", err)
+			slog.Error("Failed to fetch from fallback discovery URL", "url", discoveryURL, "error", err)
 			return fmt.Errorf("failed to fetch swagger spec from fallback URL: %w", err)
 		}
 		defer resp.Body.Close()
 	}
 
-	log.Printf("Discovery response status: %d %s", resp.StatusCode, resp.Status)
-	log.Printf("Discovery response headers:")
-	for name, values := range resp.Header {
-		for _, value := range values {
-			log.Printf("  %s: %s", name, value)
-		}
-	}
+	slog.Debug("Discovery response", "status", resp.StatusCode, headerAttr("headers", resp.Header))
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("Discovery request failed with status: %d", resp.StatusCode)
+		slog.Error("Discovery request failed", "url", discoveryURL, "status", resp.StatusCode)
 		return fmt.Errorf("failed to fetch swagger spec: status code %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("Failed to read discovery response body: %v", err)
+		slog.Error("Failed to read discovery response body", "error", err)
 		return fmt.Errorf("failed to read swagger spec: %w", err)
 	}
 
-	log.Printf("Discovery response body size: %d bytes", len(body))
+	slog.Debug("Read discovery response", "bytes", len(body))
 
-	// Log a sample of the spec for debugging (first 500 chars)
-	bodyStr := string(body)
-	if len(bodyStr) > 500 {
-		log.Printf("Swagger spec preview: %s...", bodyStr[:500])
-	} else {
-		log.Printf("Swagger spec content: %s", bodyStr)
+	return c.loadSwaggerSpec(body)
+}
+
+// loadSwaggerSpec parses a Swagger specification and builds its v2 model
+func (c *QuayClient) loadSwaggerSpec(body []byte) error {
+	body, err := c.applySpecTransform(body)
+	if err != nil {
+		return err
 	}
 
+	// Log a sample of the spec for debugging (first 500 chars)
+	slog.Debug("Swagger spec", "bytes", len(body), "preview", logPreview(body, 500))
+
 	// Create a new document from the specification bytes
 	document, err := libopenapi.NewDocument(body)
 	if err != nil {
-		log.Printf("Failed to create swagger document: %v", err)
+		slog.Error("Failed to create swagger document", "error", err)
 		return fmt.Errorf("failed to create swagger document: %w", err)
 	}
 
+	// OpenAPI 3 documents are translated to Swagger 2.0 so the rest of the client works on one model
+	if isOpenAPI3(document) {
+		converted, err := convertOpenAPI3(document)
+		if err != nil {
+			return err
+		}
+		if document, err = libopenapi.NewDocument(converted); err != nil {
+			return fmt.Errorf("failed to create swagger document from OpenAPI 3 spec: %w", err)
+		}
+	}
+
 	c.document = document
-	log.Printf("Successfully created libopenapi document")
 
 	// Build the V2 model from the document (Swagger 2.0)
 	docModel, errors := document.BuildV2Model()
-	if len(errors) > 0 {
-		log.Printf("Warning: errors occurred while building Swagger model:")
-		for _, buildErr := range errors {
-			log.Printf("  - %v", buildErr)
-		}
+	for _, buildErr := range errors {
+		slog.Warn("Error while building Swagger model", "error", buildErr)
 	}
 
 	if docModel == nil {
-		log.Printf("Failed to build Swagger v2 model - docModel is nil")
+		slog.Error("Failed to build Swagger v2 model")
 		return fmt.Errorf("failed to build Swagger v2 model")
 	}
 
 	c.model = docModel
-
-	// Log some basic info about the loaded spec
-	if c.model.Model.Info != nil {
-		log.Printf("Loaded Swagger spec - Title: %s, Version: %s", c.model.Model.Info.Title, c.model.Model.Info.Version)
-	}
-	log.Printf("Swagger spec host: %s", c.model.Model.Host)
-	log.Printf("Swagger spec base path: %s", c.model.Model.BasePath)
-	log.Printf("Swagger spec schemes: %v", c.model.Model.Schemes)
+	releaseSpecCopies(document)
 
 	// Count the number of paths
 	pathCount := 0
-	if c.model.Model.Paths != nil {
+	if hasPaths(c.model) {
 		for pathPair := c.model.Model.Paths.PathItems.First(); pathPair != nil; pathPair = pathPair.Next() {
 			pathCount++
 		}
 	}
-	log.Printf("Discovered %d API paths", pathCount)
-	log.Printf("==============================")
 
-	log.Printf("Successfully loaded Quay API with Swagger specification")
+	// Log some basic info about the loaded spec
+	var title, version string
+	if c.model.Model.Info != nil {
+		title, version = c.model.Model.Info.Title, c.model.Model.Info.Version
+	}
+	slog.Info("Loaded Swagger spec", "title", title, "version", version, "host", c.model.Model.Host,
+		"base_path", c.model.Model.BasePath, "schemes", c.model.Model.Schemes, "paths", pathCount)
 	return nil
 }
 
+// ForRegistry returns a client for another registry that shares this client's spec, endpoints and
+// settings, e.g. to compare a mirror against the primary registry
+func (c *QuayClient) ForRegistry(registryURL, oauthToken string) *QuayClient {
+	clone := *c
+	clone.registryURL = strings.TrimRight(registryURL, "/")
+	clone.oauthToken = oauthToken
+	return &clone
+}
+
+// SetSpecURL sets the base URL the discovery document is fetched from, leaving API calls on the registry URL
+func (c *QuayClient) SetSpecURL(specURL string) {
+	c.specURL = strings.TrimRight(specURL, "/")
+}
+
+// SetMaxResponseBytes limits the size of API response bodies (0 means unlimited)
+func (c *QuayClient) SetMaxResponseBytes(limit int64) {
+	c.maxResponseBytes = limit
+}
+
+// SetRequestIDHeader sets the header used to send each call's correlation ID to Quay (empty disables it)
+func (c *QuayClient) SetRequestIDHeader(header string) {
+	c.requestIDHeader = header
+}
+
+// SetJSONOnly restricts discovery and tool generation to endpoints that produce application/json
+func (c *QuayClient) SetJSONOnly(jsonOnly bool) {
+	c.jsonOnly = jsonOnly
+}
+
+// SetMaxURLLength sets the maximum length of a built request URL (0 means unlimited)
+func (c *QuayClient) SetMaxURLLength(length int) {
+	c.maxURLLength = length
+}
+
+// SetAllowedTags sets the tags whose endpoints are exposed by both discovery and tool generation.
+// An empty list or one containing "all" disables tag filtering.
+func (c *QuayClient) SetAllowedTags(tags []string) {
+	c.allowedTags = nil
+	for _, tag := range tags {
+		if tag == "all" {
+			return
+		}
+	}
+	if len(tags) > 0 {
+		c.allowedTags = tags
+	}
+}
+
+// SetDisabledTags removes tags from the allowed set; endpoints carrying any of them are excluded
+func (c *QuayClient) SetDisabledTags(tags []string) {
+	c.disabledTags = tags
+}
+
+// includeOperation reports whether an operation becomes an endpoint and tool. An endpoint allowlist
+// replaces the tag filter when configured.
+func (c *QuayClient) includeOperation(method, path string, operation *v2high.Operation) bool {
+	if c.endpointAllowlist != nil {
+		if !c.endpointAllowlist[method+" "+path] {
+			slog.Debug("Skipping operation not in the endpoint allowlist", "method", method, "path", path)
+			return false
+		}
+	} else if !c.tagsAllowed(operation.Tags) {
+		// Skip unless the operation has an allowed tag and no disabled one
+		return false
+	}
+
+	// Skip endpoints that don't produce JSON when requested
+	if c.jsonOnly && !c.producesJSON(operation) {
+		slog.Debug("Skipping operation that does not produce application/json", "method", method, "path", path)
+		return false
+	}
+	return true
+}
+
+// tagsAllowed reports whether an operation with the given tags should be exposed: it needs at least
+// one allowed tag, unless tag filtering is disabled, and no disabled tag
+func (c *QuayClient) tagsAllowed(tags []string) bool {
+	hasAllowedTag := c.allowedTags == nil
+	for _, tag := range tags {
+		for _, disabled := range c.disabledTags {
+			if tag == disabled {
+				return false
+			}
+		}
+		for _, allowed := range c.allowedTags {
+			if tag == allowed {
+				hasAllowedTag = true
+			}
+		}
+	}
+	return hasAllowedTag
+}
+
+// SetRetryEmpty200 makes an unexpectedly empty 200 response trigger a single retry
+func (c *QuayClient) SetRetryEmpty200(enabled bool) {
+	c.retryEmpty200 = enabled
+}
+
+// SetRetryStatuses sets the response statuses that are retried (none disables retries)
+func (c *QuayClient) SetRetryStatuses(statuses []int) {
+	c.retryStatuses = make(map[int]bool, len(statuses))
+	for _, status := range statuses {
+		c.retryStatuses[status] = true
+	}
+}
+
+// SetTimeout sets the time limit of each HTTP request to Quay, including reading the response (0 disables it)
+func (c *QuayClient) SetTimeout(timeout time.Duration) {
+	client := *c.httpClient
+	client.Timeout = timeout
+	c.httpClient = &client
+}
+
+// SetMaxRetries sets how many times a request is retried after a retryable status (0 disables retries)
+func (c *QuayClient) SetMaxRetries(retries int) {
+	c.maxRetries = retries
+}
+
+// SetConnectionRetries sets how many times an idempotent request is retried after a connection
+// error, such as a reset connection or a body cut short (0 disables retries). These retries are
+// counted separately from status retries, and POST and PATCH requests are never retried because
+// Quay may already have applied them.
+func (c *QuayClient) SetConnectionRetries(retries int) {
+	c.connRetries = retries
+}
+
+// SetNoFallbackDescription skips generating tools for operations without a summary or description
+func (c *QuayClient) SetNoFallbackDescription(enabled bool) {
+	c.noFallbackDescription = enabled
+}
+
+// SetManifestAccept sets the Accept media types sent to manifest endpoints (none sends application/json)
+func (c *QuayClient) SetManifestAccept(mediaTypes []string) {
+	c.manifestAccept = mediaTypes
+}
+
+// SetTraceHeaders limits response header logging to the given header names
+func (c *QuayClient) SetTraceHeaders(names []string) {
+	c.traceHeaders = names
+}
+
+// SetSlowCallThreshold sets the latency above which a call logs a warning (0 disables)
+func (c *QuayClient) SetSlowCallThreshold(threshold time.Duration) {
+	c.slowCallThreshold = threshold
+}
+
+// warnIfSlow logs a warning when a call to endpoint started at start exceeded the slow-call threshold
+func (c *QuayClient) warnIfSlow(endpoint *types.EndpointInfo, start time.Time) {
+	if c.slowCallThreshold <= 0 {
+		return
+	}
+	if elapsed := time.Since(start); elapsed > c.slowCallThreshold {
+		slog.Warn("Slow call", "method", endpoint.Method, "path", endpoint.Path,
+			"elapsed", elapsed.Round(time.Millisecond), "threshold", c.slowCallThreshold)
+	}
+}
+
+// SetStrictParams enables rejecting tool arguments that don't match a declared parameter
+func (c *QuayClient) SetStrictParams(strict bool) {
+	c.strictParams = strict
+}
+
 // GetRegistryURL returns the registry URL
 func (c *QuayClient) GetRegistryURL() string {
 	return c.registryURL
@@ -161,80 +451,101 @@ func (c *QuayClient) GetEndpoints() map[string]*types.EndpointInfo {
 	return c.endpoints
 }
 
-// DiscoverEndpoints processes the Swagger spec and discovers all GET endpoints
+// DiscoverEndpoints processes the Swagger spec and discovers the endpoints of the enabled methods
 func (c *QuayClient) DiscoverEndpoints() {
-	if c.model == nil {
-		return
-	}
+	c.discover(false)
+}
 
-	// Define allowed tags
-	allowedTags := map[string]bool{
-		"manifest":     true,
-		"organization": true,
-		"repository":   true,
-		"robot":        true,
-		"tag":          true,
+// operationEndpoint returns the endpoint info kept for calls to an operation
+func operationEndpoint(method, path string, operation *v2high.Operation) *types.EndpointInfo {
+	// Convert parameters to []interface{}
+	var parameters []interface{}
+	if operation.Parameters != nil {
+		for _, param := range operation.Parameters {
+			if param != nil {
+				parameters = append(parameters, param)
+			}
+		}
 	}
 
-	log.Printf("Filtering endpoints to include only tags: %v", []string{"manifest", "organization", "repository", "robot", "tag"})
+	return &types.EndpointInfo{
+		Method:      method,
+		Path:        path,
+		Summary:     operation.Summary,
+		OperationID: operation.OperationId,
+		Tags:        operation.Tags,
+		Parameters:  parameters,
 
-	totalEndpoints := 0
-	filteredEndpoints := 0
+		ResponseExample: responseExample(operation),
+	}
+}
 
-	// Iterate through all paths using the ordered map API
-	for pathPair := c.model.Model.Paths.PathItems.First(); pathPair != nil; pathPair = pathPair.Next() {
-		path := pathPair.Key()
-		pathItem := pathPair.Value()
+// producesJSON reports whether an operation produces JSON, using the spec-level produces list when the
+// operation declares none. Operations with no declared content types are assumed to produce JSON.
+func (c *QuayClient) producesJSON(operation *v2high.Operation) bool {
+	produces := operation.Produces
+	if len(produces) == 0 && c.model != nil {
+		produces = c.model.Model.Produces
+	}
+	if len(produces) == 0 {
+		return true
+	}
 
-		// Only process GET operations
-		if pathItem.Get == nil {
-			continue
+	for _, mediaType := range produces {
+		mediaType = strings.ToLower(strings.TrimSpace(strings.Split(mediaType, ";")[0]))
+		if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+			return true
 		}
+	}
+	return false
+}
 
-		totalEndpoints++
-		operation := pathItem.Get
-
-		// Check if the operation has any of the allowed tags
-		hasAllowedTag := false
-		if operation.Tags != nil {
-			for _, tag := range operation.Tags {
-				if allowedTags[tag] {
-					hasAllowedTag = true
-					break
-				}
-			}
-		}
+// responseExample returns the documented example of the operation's success response as JSON.
+// Response-level examples are preferred over an example declared on the response schema.
+func responseExample(operation *v2high.Operation) string {
+	if operation.Responses == nil || operation.Responses.Codes == nil {
+		return ""
+	}
 
-		// Skip if no allowed tags found
-		if !hasAllowedTag {
-			continue
+	var response *v2high.Response
+	for _, code := range []string{"200", "201", "202", "204"} {
+		if r, ok := operation.Responses.Codes.Get(code); ok && r != nil {
+			response = r
+			break
 		}
+	}
+	if response == nil {
+		return ""
+	}
 
-		filteredEndpoints++
-		uri := fmt.Sprintf("quay://%s", strings.TrimPrefix(path, "/"))
-
-		// Convert parameters to []interface{}
-		var parameters []interface{}
-		if operation.Parameters != nil {
-			for _, param := range operation.Parameters {
-				if param != nil {
-					parameters = append(parameters, param)
-				}
-			}
+	var node *yaml.Node
+	if response.Examples != nil && response.Examples.Values != nil {
+		if example, ok := response.Examples.Values.Get("application/json"); ok {
+			node = example
+		} else if first := response.Examples.Values.First(); first != nil {
+			node = first.Value()
 		}
-
-		// Store endpoint info for later API calls
-		c.endpoints[uri] = &types.EndpointInfo{
-			Method:      "GET",
-			Path:        path,
-			Summary:     operation.Summary,
-			OperationID: operation.OperationId,
-			Tags:        operation.Tags,
-			Parameters:  parameters,
+	}
+	if node == nil && response.Schema != nil {
+		if schema := response.Schema.Schema(); schema != nil {
+			node = schema.Example
 		}
 	}
+	if node == nil {
+		return ""
+	}
 
-	log.Printf("Filtered %d/%d GET endpoints based on allowed tags", filteredEndpoints, totalEndpoints)
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		slog.Warn("Failed to decode response example", "operation", operation.OperationId, "error", err)
+		return ""
+	}
+	example, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		slog.Warn("Failed to encode response example", "operation", operation.OperationId, "error", err)
+		return ""
+	}
+	return string(example)
 }
 
 // HasPathParameters checks if a path contains parameters (e.g., {id})
@@ -242,38 +553,47 @@ func (c *QuayClient) HasPathParameters(path string) bool {
 	return strings.Contains(path, "{") && strings.Contains(path, "}")
 }
 
-// BuildAPIURL constructs the full API URL for a given endpoint and resource URI
-func (c *QuayClient) BuildAPIURL(endpoint *types.EndpointInfo, resourceURI string) (string, error) {
-	// Start with the registry URL
-	baseURL := c.registryURL
+// basePath returns the spec's base path, or the cached one when endpoints came from the endpoint cache
+func (c *QuayClient) basePath() string {
+	if c.model != nil {
+		return c.model.Model.BasePath
+	}
+	return c.cachedBasePath
+}
 
-	// Add base path from Swagger spec if available
-	if c.model != nil && c.model.Model.BasePath != "" {
-		baseURL = strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(c.model.Model.BasePath, "/")
+// apiBaseURL returns the registry URL followed by the spec's base path, unless the endpoint path
+// already starts with it: Quay's spec repeats /api/v1 in every path, which would otherwise be doubled
+func (c *QuayClient) apiBaseURL(endpointPath string) string {
+	baseURL := c.registryURL
+	basePath := "/" + strings.Trim(c.basePath(), "/")
+	if basePath != "/" && endpointPath != basePath && !strings.HasPrefix(endpointPath, basePath+"/") {
+		baseURL = strings.TrimRight(baseURL, "/") + basePath
 	}
+	return baseURL
+}
+
+// BuildAPIURL constructs the full API URL for a given endpoint and resource URI
+func (c *QuayClient) BuildAPIURL(endpoint *types.EndpointInfo, resourceURI string) (string, error) {
+	// Start with the registry URL and the base path from the Swagger spec
+	baseURL := c.apiBaseURL(endpoint.Path)
 
 	// Add the endpoint path
 	fullURL := strings.TrimRight(baseURL, "/") + endpoint.Path
 
 	// Extract any path parameters from the resource URI
 	pathParams := c.extractPathParameters(resourceURI, endpoint.Path)
-	for param, value := range pathParams {
-		placeholder := fmt.Sprintf("{%s}", param)
-		fullURL = strings.ReplaceAll(fullURL, placeholder, value)
-	}
+	fullURL = substitutePathParameters(fullURL, func(name string) (string, bool) {
+		value, ok := pathParams[name]
+		return value, ok
+	})
 
 	return fullURL, nil
 }
 
 // BuildAPIURLWithParams constructs the full API URL for a given endpoint with explicit parameters
 func (c *QuayClient) BuildAPIURLWithParams(endpoint *types.EndpointInfo, params map[string]interface{}) (string, error) {
-	// Start with the registry URL
-	baseURL := c.registryURL
-
-	// Add base path from Swagger spec if available
-	if c.model != nil && c.model.Model.BasePath != "" {
-		baseURL = strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(c.model.Model.BasePath, "/")
-	}
+	// Start with the registry URL and the base path from the Swagger spec
+	baseURL := c.apiBaseURL(endpoint.Path)
 
 	// Start with the endpoint path
 	finalPath := endpoint.Path
@@ -289,39 +609,52 @@ func (c *QuayClient) BuildAPIURLWithParams(endpoint *types.EndpointInfo, params
 		pathParamMap[name] = true
 	}
 
+	// Write methods send everything but declared query parameters in the body
+	var declaredQuery map[string]bool
+	if hasRequestBody(endpoint) {
+		declaredQuery = queryParamNames(endpoint)
+	}
+
+	// Header parameters are sent as headers, see setHeaderParams
+	headerNames := headerArgumentNames(endpoint)
+
 	// Separate path and query parameters
 	for key, value := range params {
-		if key == "resource_uri" {
-			continue // Skip the special resource_uri parameter
+		if key == "resource_uri" || headerNames[key] {
+			continue // Skip the special resource_uri parameter and header parameters
 		}
 		if pathParamMap[key] {
 			pathParams[key] = value
-		} else {
+		} else if declaredQuery == nil || declaredQuery[key] {
 			// Assume it's a query parameter
 			queryParams[key] = value
 		}
 	}
 
-	// Replace path parameters with actual values
+	// Replace path parameters with actual values, refusing to leave placeholders in the URL
 	if c.HasPathParameters(finalPath) {
-		for _, paramName := range pathParamNames {
-			if paramValue, exists := pathParams[paramName]; exists {
-				if paramValueStr, ok := paramValue.(string); ok {
-					placeholder := fmt.Sprintf("{%s}", paramName)
-					finalPath = strings.ReplaceAll(finalPath, placeholder, paramValueStr)
-				}
+		var missing []string
+		finalPath = substitutePathParameters(finalPath, func(name string) (string, bool) {
+			value, ok := formatParamValue(pathParams[name])
+			if !ok {
+				missing = append(missing, name)
 			}
+			return value, ok
+		})
+		if len(missing) > 0 {
+			return "", &MissingPathParametersError{Method: endpoint.Method, Path: endpoint.Path, Names: missing}
 		}
 	}
 
 	// Build the base URL
 	fullURL := strings.TrimRight(baseURL, "/") + finalPath
 
-	// Add query parameters if any
+	// Add query parameters if any, serializing arrays in their declared collection format
 	if len(queryParams) > 0 {
+		formats := queryCollectionFormats(endpoint)
 		queryParts := []string{}
 		for key, value := range queryParams {
-			if valueStr, ok := value.(string); ok && valueStr != "" {
+			for _, valueStr := range queryValues(value, formats[key]) {
 				queryParts = append(queryParts, fmt.Sprintf("%s=%s", key, url.QueryEscape(valueStr)))
 			}
 		}
@@ -330,9 +663,73 @@ func (c *QuayClient) BuildAPIURLWithParams(endpoint *types.EndpointInfo, params
 		}
 	}
 
+	// Reject URLs the server would likely refuse with a confusing 414
+	if c.maxURLLength > 0 && len(fullURL) > c.maxURLLength {
+		return "", fmt.Errorf("request URL is %d characters, exceeding the maximum of %d; use fewer or shorter parameters", len(fullURL), c.maxURLLength)
+	}
+
 	return fullURL, nil
 }
 
+// SetMaxParamValueLength sets the maximum length of a single argument value (0 means unlimited)
+func (c *QuayClient) SetMaxParamValueLength(length int) {
+	c.maxParamLength = length
+}
+
+// validateParamLengths rejects the first argument, in name order, whose value is longer than the
+// configured maximum; such values usually mean a blob was pasted where a name belongs
+func (c *QuayClient) validateParamLengths(params map[string]interface{}) error {
+	if c.maxParamLength <= 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if value, ok := formatParamValue(params[name]); ok && len(value) > c.maxParamLength {
+			return fmt.Errorf("parameter %q is %d characters, exceeding the maximum of %d", name, len(value), c.maxParamLength)
+		}
+	}
+	return nil
+}
+
+// validateKnownParams returns an error listing any argument that isn't a path, query or header
+// parameter of the endpoint (or the special resource_uri argument)
+func validateKnownParams(endpoint *types.EndpointInfo, params map[string]interface{}) error {
+	known := map[string]bool{"resource_uri": true}
+	for _, name := range extractPathParameterNames(endpoint.Path) {
+		known[name] = true
+	}
+	for _, p := range endpoint.Parameters {
+		if param, ok := p.(*v2high.Parameter); ok {
+			switch param.In {
+			case "path", "query":
+				known[param.Name] = true
+			}
+		}
+	}
+	for name := range headerArgumentNames(endpoint) {
+		known[name] = true
+	}
+
+	var unknown []string
+	for key := range params {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	return fmt.Errorf("unknown parameters for %s %s: %s", endpoint.Method, endpoint.Path, strings.Join(unknown, ", "))
+}
+
 // extractPathParameters extracts path parameters from a resource URI based on a path template
 func (c *QuayClient) extractPathParameters(resourceURI, pathTemplate string) map[string]string {
 	params := make(map[string]string)
@@ -348,15 +745,18 @@ func (c *QuayClient) extractPathParameters(resourceURI, pathTemplate string) map
 	regexPattern := pathTemplate
 	paramNames := []string{}
 
-	// Find all {param} patterns
-	paramRegex := regexp.MustCompile(`\{([^}]+)\}`)
-	matches := paramRegex.FindAllStringSubmatch(pathTemplate, -1)
+	// Find all {param} and {param:constraint} patterns
+	matches := pathParamPattern.FindAllStringSubmatch(pathTemplate, -1)
 
 	for _, match := range matches {
 		paramName := match[1]
 		paramNames = append(paramNames, paramName)
-		// Replace {param} with ([^/]+) capture group
-		regexPattern = strings.ReplaceAll(regexPattern, match[0], "([^/]+)")
+		// Replace {param} with ([^/]+) capture group, or with the constraint when one is given
+		group := "([^/]+)"
+		if match[2] != "" {
+			group = "(" + match[2] + ")"
+		}
+		regexPattern = strings.ReplaceAll(regexPattern, match[0], group)
 	}
 
 	// Compile and match against the resource path
@@ -377,332 +777,784 @@ func (c *QuayClient) extractPathParameters(resourceURI, pathTemplate string) map
 	return params
 }
 
+// newRequest creates an HTTP request with the standard headers, OAuth token and
+// correlation ID header applied
+func (c *QuayClient) newRequest(ctx context.Context, method, apiURL string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, apiURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set headers
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "quay-mcp-server/1.0.0")
+
+	// Add OAuth token if provided, preferring a per-call token carried by the context
+	if token := c.tokenFromContext(ctx); token != "" {
+		c.setAuth(req, token)
+	}
+
+	// Propagate the correlation ID so the call can be found in Quay's logs
+	if requestID := RequestIDFromContext(ctx); requestID != "" && c.requestIDHeader != "" {
+		req.Header.Set(c.requestIDHeader, requestID)
+	}
+
+	return req, nil
+}
+
+// newEndpointRequest creates a request for an endpoint with an optional JSON body, asking manifest
+// endpoints for the manifest media types instead of generic JSON
+func (c *QuayClient) newEndpointRequest(ctx context.Context, endpoint *types.EndpointInfo, apiURL string, body []byte) (*http.Request, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req, err := c.newRequest(ctx, endpoint.Method, apiURL, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if len(c.manifestAccept) > 0 && isManifestEndpoint(endpoint) {
+		req.Header.Set("Accept", strings.Join(c.manifestAccept, ", "))
+	}
+	return req, nil
+}
+
+// isManifestEndpoint reports whether an endpoint is tagged manifest
+func isManifestEndpoint(endpoint *types.EndpointInfo) bool {
+	for _, tag := range endpoint.Tags {
+		if tag == "manifest" {
+			return true
+		}
+	}
+	return false
+}
+
 // MakeAPICall makes an HTTP request to the Quay API and returns the response
 func (c *QuayClient) MakeAPICall(endpoint *types.EndpointInfo, resourceURI string) ([]byte, error) {
+	ctx := context.Background()
+	defer c.warnIfSlow(endpoint, time.Now())
+
 	apiURL, err := c.BuildAPIURL(endpoint, resourceURI)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build API URL: %v", err)
 	}
 
 	// Create HTTP request
-	req, err := http.NewRequest(endpoint.Method, apiURL, nil)
+	req, err := c.newEndpointRequest(ctx, endpoint, apiURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request: %v", err)
 	}
 
-	// Set headers
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "quay-mcp-server/1.0.0")
+	// Log the outgoing request
+	slog.Debug("Quay API request", "method", req.Method, "url", req.URL.String(),
+		headerAttr("headers", c.redactedHeader(req.Header)), "resource_uri", resourceURI,
+		"endpoint", endpoint.Method+" "+endpoint.Path, "operation", endpoint.OperationID)
+
+	resp, err := c.executeRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// MakeAPICallWithParams makes an HTTP request to the Quay API with explicit parameters and returns the response
+func (c *QuayClient) MakeAPICallWithParams(endpoint *types.EndpointInfo, params map[string]interface{}) ([]byte, error) {
+	return c.MakeAPICallWithParamsContext(context.Background(), endpoint, params)
+}
+
+// MakeAPICallWithParamsContext is MakeAPICallWithParams with a context that bounds the request
+// and carries the call's correlation ID
+func (c *QuayClient) MakeAPICallWithParamsContext(ctx context.Context, endpoint *types.EndpointInfo, params map[string]interface{}) ([]byte, error) {
+	resp, err := c.CallAPI(ctx, endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// CallAPI is MakeAPICallWithParamsContext returning the whole response, so callers can inspect
+// its status and headers such as Content-Type
+func (c *QuayClient) CallAPI(ctx context.Context, endpoint *types.EndpointInfo, params map[string]interface{}) (*APIResponse, error) {
+	defer c.warnIfSlow(endpoint, time.Now())
+
+	params, apiURL, body, err := c.prepareCall(endpoint, params)
+	if err != nil {
+		return nil, err
+	}
 
-	// Add OAuth token if provided
-	if c.oauthToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.oauthToken)
+	// Abandon writes that a validation probe shows would fail
+	if c.validateWrites && endpoint.Method != http.MethodGet {
+		if err := c.probeWrite(ctx, endpoint, params); err != nil {
+			return nil, err
+		}
 	}
 
+	// Create HTTP request
+	req, err := c.newEndpointRequest(ctx, endpoint, apiURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %v", err)
+	}
+	setHeaderParams(req, endpoint, params)
+
 	// Log the outgoing request
-	log.Printf("=== QUAY API REQUEST ===")
-	log.Printf("Method: %s", req.Method)
-	log.Printf("URL: %s", req.URL.String())
-	log.Printf("Headers:")
-	for name, values := range req.Header {
-		for _, value := range values {
-			// Mask the Authorization header for security
-			if name == "Authorization" && strings.HasPrefix(value, "Bearer ") {
-				log.Printf("  %s: Bearer [REDACTED]", name)
-			} else {
-				log.Printf("  %s: %s", name, value)
-			}
+	attrs := []any{"method", req.Method, "url", req.URL.String(), headerAttr("headers", c.redactedHeader(req.Header)),
+		"parameters", params, "endpoint", endpoint.Method + " " + endpoint.Path, "operation", endpoint.OperationID}
+	if body != nil {
+		attrs = append(attrs, "body", string(body))
+	}
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		attrs = append(attrs, "correlation_id", requestID)
+	}
+	slog.Debug("Quay API request", attrs...)
+
+	return c.executeRequest(req)
+}
+
+// prepareCall checks the arguments of a call and resolves them into the parameters sent, with
+// mapped names translated and defaults applied, the request URL and the JSON body, if any
+func (c *QuayClient) prepareCall(endpoint *types.EndpointInfo, params map[string]interface{}) (map[string]interface{}, string, []byte, error) {
+	params = c.APIParams(endpoint, params)
+
+	// Write methods accept arbitrary body fields, so only reads are checked
+	if c.strictParams && !hasRequestBody(endpoint) {
+		if err := validateKnownParams(endpoint, params); err != nil {
+			return nil, "", nil, err
 		}
 	}
-	log.Printf("Resource URI: %s", resourceURI)
-	log.Printf("Endpoint: %s %s (Operation: %s)", endpoint.Method, endpoint.Path, endpoint.OperationID)
 
-	// Make the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	if err := c.validateParamLengths(params); err != nil {
+		return nil, "", nil, err
+	}
+
+	params = c.ApplyNamespaceDefault(endpoint, params)
+	params = c.applyParamDefaults(endpoint, params)
+
+	if err := checkRequiredFilters(endpoint, params); err != nil {
+		return nil, "", nil, err
+	}
+
+	apiURL, err := c.BuildAPIURLWithParams(endpoint, params)
 	if err != nil {
-		log.Printf("=== QUAY API REQUEST FAILED ===")
-		log.Printf("Error: %v", err)
-		return nil, fmt.Errorf("failed to make API request: %v", err)
+		return nil, "", nil, fmt.Errorf("failed to build API URL: %w", err)
 	}
-	defer resp.Body.Close()
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	// Write methods carry their remaining arguments as a JSON body
+	var body []byte
+	if hasRequestBody(endpoint) {
+		if body, err = json.Marshal(requestBody(endpoint, params)); err != nil {
+			return nil, "", nil, fmt.Errorf("failed to encode request body: %v", err)
+		}
+	}
+	return params, apiURL, body, nil
+}
+
+// APIResponse is a fully read Quay API response
+type APIResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// APIError is returned when Quay answers with a status outside the accepted success range
+type APIError struct {
+	StatusCode int
+	Body       []byte
+	Method     string // method of the failed request
+	Path       string // URL path of the failed request, with its path parameters filled in
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API request failed with status %d: %s", e.StatusCode, string(e.Body))
+}
+
+// MissingPathParametersError is returned when arguments leave path parameters of an endpoint
+// unset or empty, so no request is made with placeholders left in its URL
+type MissingPathParametersError struct {
+	Method string
+	Path   string
+	Names  []string
+}
+
+func (e *MissingPathParametersError) Error() string {
+	return fmt.Sprintf("missing required path parameters for %s %s: %s", e.Method, e.Path, strings.Join(e.Names, ", "))
+}
+
+// executeRequest sends a prepared request, logs the response and returns it.
+// Responses with a status outside the success range are returned as errors. Retried attempts
+// only log a line each, and the outcome is logged once with the number of attempts it took.
+func (c *QuayClient) executeRequest(req *http.Request) (*APIResponse, error) {
+	resp, attempts, err := c.sendWithRetry(req)
 	if err != nil {
-		log.Printf("=== QUAY API RESPONSE READ FAILED ===")
-		log.Printf("Error reading body: %v", err)
-		return nil, fmt.Errorf("failed to read response body: %v", err)
+		slog.Warn("Quay API request failed", "method", req.Method, "url", req.URL.String(), "attempts", attempts, "error", err)
+		return nil, err
 	}
 
-	// Log the response
-	log.Printf("=== QUAY API RESPONSE ===")
-	log.Printf("Status: %d %s", resp.StatusCode, resp.Status)
-	log.Printf("Headers:")
-	for name, values := range resp.Header {
-		for _, value := range values {
-			log.Printf("  %s: %s", name, value)
+	// Quay occasionally answers 200 with an empty body during backend hiccups; retry once
+	if c.retryEmpty200 && resp.StatusCode == http.StatusOK && len(bytes.TrimSpace(resp.Body)) == 0 {
+		slog.Warn("Received an empty 200 response, retrying once", "url", req.URL.String())
+		retry, err := cloneRequest(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retry request: %v", err)
+		}
+		var retried int
+		resp, retried, err = c.sendWithRetry(retry)
+		attempts += retried
+		if err != nil {
+			slog.Warn("Quay API request failed", "method", req.Method, "url", req.URL.String(), "attempts", attempts, "error", err)
+			return nil, err
 		}
 	}
 
-	// Log response body (truncate if very long)
-	bodyStr := string(body)
-	if len(bodyStr) > 1000 {
-		log.Printf("Response Body (%d bytes, truncated to 1000): %s...", len(bodyStr), bodyStr[:1000])
+	// Check for statuses outside the accepted success range
+	if !c.isSuccess(resp.StatusCode) {
+		slog.Warn("Quay API request failed", "method", req.Method, "url", req.URL.String(), "status", resp.StatusCode,
+			"attempts", attempts, "body", logPreview(resp.Body, 1000))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: resp.Body, Method: req.Method, Path: req.URL.Path}
+	}
+
+	if attempts > 1 {
+		slog.Info("Quay API request succeeded after retrying", "method", req.Method, "url", req.URL.String(), "status", resp.StatusCode, "attempts", attempts)
 	} else {
-		log.Printf("Response Body (%d bytes): %s", len(bodyStr), bodyStr)
+		slog.Debug("Quay API request completed", "method", req.Method, "url", req.URL.String(), "status", resp.StatusCode)
 	}
-	log.Printf("========================")
+	return resp, nil
+}
 
-	// Check for HTTP errors
-	if resp.StatusCode >= 400 {
-		log.Printf("API request failed with status %d", resp.StatusCode)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+// retryableStatusError marks a response whose status is configured to be retried, with the
+// wait its Retry-After header asks for
+type retryableStatusError struct {
+	status     int
+	retryAfter time.Duration
+}
+
+func (e *retryableStatusError) Error() string {
+	if e.retryAfter > 0 {
+		return fmt.Sprintf("retryable status %d (retry after %s)", e.status, e.retryAfter)
 	}
+	return fmt.Sprintf("retryable status %d", e.status)
+}
 
-	log.Printf("API request completed successfully")
-	return body, nil
+// connectionError is a request that failed at the transport level, such as a refused or reset
+// connection, a body cut short or the HTTP client timeout
+type connectionError struct {
+	err error
 }
 
-// MakeAPICallWithParams makes an HTTP request to the Quay API with explicit parameters and returns the response
-func (c *QuayClient) MakeAPICallWithParams(endpoint *types.EndpointInfo, params map[string]interface{}) ([]byte, error) {
-	apiURL, err := c.BuildAPIURLWithParams(endpoint, params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build API URL: %v", err)
+func (e *connectionError) Error() string {
+	return fmt.Sprintf("failed to make API request: %v", e.err)
+}
+
+func (e *connectionError) Unwrap() error {
+	return e.err
+}
+
+// retryPolicy retries retryable statuses with exponential backoff, or after the wait their
+// Retry-After header asks for, up to maxRetries times. A status asking for a wait longer than
+// maxRetryAfter isn't retried.
+func (c *QuayClient) retryPolicy() retry.Policy {
+	return retry.Policy{
+		Attempts: c.maxRetries + 1,
+		Backoff:  retry.Exponential(c.retryBackoff, 0),
+		Retryable: func(err error) bool {
+			var statusErr *retryableStatusError
+			if !errors.As(err, &statusErr) {
+				return false
+			}
+			if statusErr.retryAfter > maxRetryAfter {
+				slog.Warn("Retry-After exceeds the maximum wait, not retrying", "status", statusErr.status, "retry_after", statusErr.retryAfter, "max_wait", maxRetryAfter)
+				return false
+			}
+			return true
+		},
+		RetryAfter: func(err error) time.Duration {
+			var statusErr *retryableStatusError
+			if errors.As(err, &statusErr) {
+				return statusErr.retryAfter
+			}
+			return 0
+		},
+		OnRetry: func(attempt int, delay time.Duration, err error) {
+			slog.Warn("Request failed, retrying", "error", err, "delay", delay, "attempt", attempt+1, "max_retries", c.maxRetries)
+		},
 	}
+}
 
-	// Create HTTP request
-	req, err := http.NewRequest(endpoint.Method, apiURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %v", err)
+// connectionRetryPolicy retries connection errors of idempotent methods with exponential backoff,
+// up to connRetries times. Requests whose context has ended are not retried.
+func (c *QuayClient) connectionRetryPolicy(ctx context.Context, method string) retry.Policy {
+	attempts := c.connRetries + 1
+	if !idempotentMethod(method) {
+		attempts = 1
+	}
+	return retry.Policy{
+		Attempts: attempts,
+		Backoff:  retry.Exponential(c.retryBackoff, 0),
+		Retryable: func(err error) bool {
+			var connErr *connectionError
+			return errors.As(err, &connErr) && ctx.Err() == nil
+		},
+		OnRetry: func(attempt int, delay time.Duration, err error) {
+			slog.Warn("Connection failed, retrying", "method", method, "error", err, "delay", delay, "attempt", attempt+1, "connection_retries", c.connRetries)
+		},
 	}
+}
 
-	// Set headers
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "quay-mcp-server/1.0.0")
+// idempotentMethod reports whether repeating a request with the method has the same effect as
+// sending it once, so it is safe to retry when it may already have reached Quay
+func idempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
 
-	// Add OAuth token if provided
-	if c.oauthToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.oauthToken)
+// sendWithRetry sends a request, retrying with exponential backoff after retryable statuses, up
+// to maxRetries times, and after connection errors of idempotent requests, up to connRetries
+// times per attempt. The last response is returned when the retries run out on a retryable status,
+// along with the number of times the request was sent.
+func (c *QuayClient) sendWithRetry(req *http.Request) (*APIResponse, int, error) {
+	var resp *APIResponse
+	attempts, sent := 0, 0
+	err := c.retryPolicy().Do(req.Context(), func(ctx context.Context) error {
+		var err error
+		var tries int
+		resp, tries, err = c.sendWithConnectionRetry(req, attempts > 0)
+		sent += tries
+		if err != nil {
+			return err
+		}
+		attempts++
+		if c.retryStatuses[resp.StatusCode] {
+			return &retryableStatusError{status: resp.StatusCode, retryAfter: retryAfter(resp.Header, time.Now())}
+		}
+		return nil
+	})
+
+	var statusErr *retryableStatusError
+	switch {
+	case err == nil, errors.As(err, &statusErr):
+		return resp, sent, nil
+	case req.Context().Err() != nil && resp != nil:
+		// The context ended between attempts rather than during a request
+		return nil, sent, fmt.Errorf("request cancelled while waiting to retry: %v", err)
 	}
+	return nil, sent, err
+}
 
-	// Log the outgoing request
-	log.Printf("=== QUAY API REQUEST ===")
-	log.Printf("Method: %s", req.Method)
-	log.Printf("URL: %s", req.URL.String())
-	log.Printf("Headers:")
-	for name, values := range req.Header {
-		for _, value := range values {
-			// Mask the Authorization header for security
-			if name == "Authorization" && strings.HasPrefix(value, "Bearer ") {
-				log.Printf("  %s: Bearer [REDACTED]", name)
-			} else {
-				log.Printf("  %s: %s", name, value)
+// sendWithConnectionRetry sends a request, retrying connection errors of idempotent methods. The
+// request is cloned before every attempt when resend is set, and before every retry otherwise.
+// The number of times the request was sent is returned with the response.
+func (c *QuayClient) sendWithConnectionRetry(req *http.Request, resend bool) (*APIResponse, int, error) {
+	var resp *APIResponse
+	attempts := 0
+	err := c.connectionRetryPolicy(req.Context(), req.Method).Do(req.Context(), func(ctx context.Context) error {
+		next := req
+		if resend || attempts > 0 {
+			clone, err := cloneRequest(req)
+			if err != nil {
+				return fmt.Errorf("failed to retry request: %v", err)
 			}
+			next = clone
 		}
+		attempts++
+
+		var err error
+		resp, err = c.sendRequest(next)
+		return err
+	})
+	return resp, attempts, err
+}
+
+// getWithRetry fetches a URL with the shared HTTP client and retry policy. Running out of retries
+// on a retryable status is an error naming the status, since that response's body is closed.
+func (c *QuayClient) getWithRetry(url string) (*http.Response, error) {
+	ctx := context.Background()
+
+	var resp *http.Response
+	err := c.retryPolicy().Do(ctx, func(ctx context.Context) error {
+		err := c.connectionRetryPolicy(ctx, http.MethodGet).Do(ctx, func(ctx context.Context) error {
+			if err := c.waitForRateLimit(ctx); err != nil {
+				return err
+			}
+			var err error
+			if resp, err = c.httpClient.Get(url); err != nil {
+				return &connectionError{err: err}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if c.retryStatuses[resp.StatusCode] {
+			resp.Body.Close()
+			return &retryableStatusError{status: resp.StatusCode, retryAfter: retryAfter(resp.Header, time.Now())}
+		}
+		return nil
+	})
+
+	var connErr *connectionError
+	var statusErr *retryableStatusError
+	switch {
+	case err == nil:
+		return resp, nil
+	case errors.As(err, &connErr):
+		return nil, connErr.err
+	case errors.As(err, &statusErr):
+		return nil, fmt.Errorf("status code %d", statusErr.status)
+	default:
+		return nil, err
+	}
+}
+
+// sendRequest performs a single HTTP exchange, reading and logging the response. Failures and
+// retryable responses are left to the retry and outcome lines, so retries don't repeat the dump.
+func (c *QuayClient) sendRequest(req *http.Request) (*APIResponse, error) {
+	if err := c.waitForRateLimit(req.Context()); err != nil {
+		return nil, fmt.Errorf("request cancelled while waiting for the rate limit: %v", err)
 	}
-	log.Printf("Parameters: %v", params)
-	log.Printf("Endpoint: %s %s (Operation: %s)", endpoint.Method, endpoint.Path, endpoint.OperationID)
 
 	// Make the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		log.Printf("=== QUAY API REQUEST FAILED ===")
-		log.Printf("Error: %v", err)
-		return nil, fmt.Errorf("failed to make API request: %v", err)
+		return nil, &connectionError{err: err}
 	}
 	defer resp.Body.Close()
 
 	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	body, err := c.readResponseBody(resp)
 	if err != nil {
-		log.Printf("=== QUAY API RESPONSE READ FAILED ===")
-		log.Printf("Error reading body: %v", err)
+		// A body cut short by the connection is as transient as a failed connection
+		var netErr net.Error
+		if errors.Is(err, io.ErrUnexpectedEOF) || errors.As(err, &netErr) {
+			return nil, &connectionError{err: fmt.Errorf("failed to read response body: %w", err)}
+		}
 		return nil, fmt.Errorf("failed to read response body: %v", err)
 	}
 
-	// Log the response
-	log.Printf("=== QUAY API RESPONSE ===")
-	log.Printf("Status: %d %s", resp.StatusCode, resp.Status)
-	log.Printf("Headers:")
-	for name, values := range resp.Header {
-		for _, value := range values {
-			log.Printf("  %s: %s", name, value)
+	if c.retryStatuses[resp.StatusCode] {
+		return &APIResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}, nil
+	}
+
+	// Log the response, truncating very long bodies
+	attrs := []any{"status", resp.StatusCode, "bytes", len(body)}
+	if resp.ContentLength < 0 {
+		attrs = append(attrs, "content_length", "unknown", "transfer_encoding", resp.TransferEncoding)
+	}
+	if len(c.traceHeaders) == 0 {
+		attrs = append(attrs, headerAttr("headers", resp.Header))
+	}
+	attrs = append(attrs, "body", logPreview(body, 1000))
+	slog.Debug("Quay API response", attrs...)
+	c.logTraceHeaders(resp.Header)
+
+	return &APIResponse{StatusCode: resp.StatusCode, Header: resp.Header, Body: body}, nil
+}
+
+// logTraceHeaders logs the configured trace headers of a response at info level, so they stay
+// visible when the full response dump is not (without trace headers every header is logged with
+// the response at debug level)
+func (c *QuayClient) logTraceHeaders(header http.Header) {
+	if len(c.traceHeaders) == 0 {
+		return
+	}
+
+	traced := make(http.Header)
+	for _, name := range c.traceHeaders {
+		for _, value := range header.Values(name) {
+			traced.Add(name, value)
 		}
 	}
+	slog.Info("Quay API response headers", headerAttr("headers", traced))
+}
 
-	// Log response body (truncate if very long)
-	bodyStr := string(body)
-	if len(bodyStr) > 1000 {
-		log.Printf("Response Body (%d bytes, truncated to 1000): %s...", len(bodyStr), bodyStr[:1000])
-	} else {
-		log.Printf("Response Body (%d bytes): %s", len(bodyStr), bodyStr)
+// headerAttr groups HTTP headers into a log attribute, one key per header in sorted order
+func headerAttr(key string, header http.Header) slog.Attr {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attrs := make([]any, 0, len(names))
+	for _, name := range names {
+		attrs = append(attrs, slog.String(name, strings.Join(header[name], ", ")))
+	}
+	return slog.Group(key, attrs...)
+}
+
+// logPreview returns data as a string for logging, truncated to limit bytes
+func logPreview(data []byte, limit int) string {
+	if len(data) <= limit {
+		return string(data)
+	}
+	return string(data[:limit]) + "..."
+}
+
+// cloneRequest copies a request so it can be sent again, rewinding its body if it has one
+func cloneRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+// readResponseBody reads the response body, enforcing the configured maximum size. The limit is
+// applied while reading, so it also holds for chunked responses that have no Content-Length.
+func (c *QuayClient) readResponseBody(resp *http.Response) ([]byte, error) {
+	if c.maxResponseBytes <= 0 {
+		return io.ReadAll(resp.Body)
 	}
-	log.Printf("========================")
 
-	// Check for HTTP errors
-	if resp.StatusCode >= 400 {
-		log.Printf("API request failed with status %d", resp.StatusCode)
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	// Fail fast when the server announces an oversized body
+	if resp.ContentLength > c.maxResponseBytes {
+		return nil, fmt.Errorf("response of %d bytes exceeds the maximum of %d bytes", resp.ContentLength, c.maxResponseBytes)
 	}
 
-	log.Printf("API request completed successfully")
+	// Read one byte past the limit to detect bodies that are too large
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.maxResponseBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > c.maxResponseBytes {
+		return nil, fmt.Errorf("response exceeds the maximum of %d bytes", c.maxResponseBytes)
+	}
 	return body, nil
 }
 
 // GenerateTools creates MCP tools from Quay API endpoints
 func (c *QuayClient) GenerateTools() []mcp.Tool {
-	model := c.GetModel()
-	if model == nil {
-		return nil
-	}
+	return c.walkSpec(true).tools
+}
+
+// operationTool creates the MCP tool of an operation, reporting false when the operation gets none
+func (c *QuayClient) operationTool(method, path string, operation *v2high.Operation) (mcp.Tool, bool) {
+	toolName := c.ToolName(method, operation.OperationId, path, operation.Tags)
 
-	// Define allowed tags
-	allowedTags := map[string]bool{
-		"manifest":     true,
-		"organization": true,
-		"repository":   true,
-		"robot":        true,
-		"tag":          true,
+	// Create description
+	description := operation.Summary
+	if description == "" {
+		description = operation.Description
+	}
+	if description == "" {
+		// A generated "METHOD {path}" description tells the model nothing, so optionally drop the tool
+		if c.noFallbackDescription {
+			slog.Debug("Skipping operation without a summary or description", "method", method, "path", path)
+			return mcp.Tool{}, false
+		}
+		description = fmt.Sprintf("%s %s", method, path)
 	}
 
-	var tools []mcp.Tool
+	// Add additional context to description
+	fullDescription := fmt.Sprintf("%s\nEndpoint: %s %s", description, method, path)
+	if method != http.MethodGet {
+		fullDescription += "\nArguments other than path and query parameters are sent as the JSON request body."
+	}
+	if len(operation.Tags) > 0 {
+		fullDescription += fmt.Sprintf("\nTags: %s", strings.Join(operation.Tags, ", "))
+		fullDescription += c.tagDescriptionText(operation.Tags)
+	}
+	fullDescription += c.linkDescriptionText(operation)
 
-	// Iterate through all paths using the ordered map API
-	for pathPair := model.Model.Paths.PathItems.First(); pathPair != nil; pathPair = pathPair.Next() {
-		path := pathPair.Key()
-		pathItem := pathPair.Value()
+	// Create tool options
+	toolOptions := []mcp.ToolOption{
+		mcp.WithDescription(fullDescription),
+	}
 
-		// Only process GET operations
-		if pathItem.Get == nil {
-			continue
+	// Add path parameters to input schema
+	if c.HasPathParameters(path) {
+		// Extract parameter names from path
+		pathParams := extractPathParameterNames(path)
+		for _, paramName := range pathParams {
+			toolOptions = append(toolOptions,
+				c.paramOption(paramName,
+					mcp.Required(),
+					mcp.Description(fmt.Sprintf("Path parameter: %s", paramName)),
+				),
+			)
 		}
+	}
 
-		operation := pathItem.Get
+	// Add query parameters from the operation
+	taken := make(map[string]bool)
+	for _, paramName := range extractPathParameterNames(path) {
+		taken[paramName] = true
+	}
+	if operation.Parameters != nil {
+		for _, param := range operation.Parameters {
+			if param != nil && param.In == "query" {
+				taken[param.Name] = true
+				paramName := param.Name
+				paramDescription := param.Description
+				if paramDescription == "" {
+					paramDescription = fmt.Sprintf("Query parameter: %s", paramName)
+				}
 
-		// Check if the operation has any of the allowed tags
-		hasAllowedTag := false
-		if operation.Tags != nil {
-			for _, tag := range operation.Tags {
-				if allowedTags[tag] {
-					hasAllowedTag = true
-					break
+				// Query parameters are optional by default and keep the type and enum the spec declares
+				opts := []mcp.PropertyOption{mcp.Description(paramDescription)}
+				if enum := enumOption(param.Type, param.Enum); enum != nil {
+					opts = append(opts, enum)
 				}
+				if param.Type == "array" {
+					opts = append(opts, mcp.Items(arrayItemsSchema(param.Items)))
+				}
+				toolOptions = append(toolOptions, c.typedParamOption(paramName, param.Type, opts...))
 			}
 		}
+	}
 
-		// Skip if no allowed tags found
-		if !hasAllowedTag {
-			continue
+	// Add header parameters, prefixed when a path or query parameter has the same name
+	for _, header := range headerArguments(operation.Parameters, taken) {
+		taken[header.name] = true
+		opts := []mcp.PropertyOption{mcp.Description(headerParamDescription(header.param))}
+		if enum := enumOption(header.param.Type, header.param.Enum); enum != nil {
+			opts = append(opts, enum)
 		}
+		toolOptions = append(toolOptions, c.typedParamOption(header.name, header.param.Type, opts...))
+	}
 
-		// Create tool name from operation ID or path
-		toolName := operation.OperationId
-		if toolName == "" {
-			// Create a clean tool name from the path
-			toolName = strings.ReplaceAll(strings.Trim(path, "/"), "/", "_")
-			toolName = strings.ReplaceAll(toolName, "{", "")
-			toolName = strings.ReplaceAll(toolName, "}", "")
-			if toolName == "" {
-				toolName = "root"
-			}
-		}
-		toolName = "quay_" + toolName
+	// Add the fields of the request body, if the operation takes one
+	toolOptions = append(toolOptions, c.bodyToolOptions(bodyParameter(operation.Parameters), taken)...)
 
-		// Create description
-		description := operation.Summary
-		if description == "" {
-			description = operation.Description
-		}
-		if description == "" {
-			description = fmt.Sprintf("GET %s", path)
-		}
+	// Add a special "resource_uri" parameter for all tools to maintain compatibility
+	toolOptions = append(toolOptions,
+		mcp.WithString("resource_uri",
+			mcp.Description("Optional: Custom resource URI (e.g., quay://repository/myorg/myrepo). If not provided, will be constructed from path parameters."),
+		),
+	)
 
-		// Add additional context to description
-		fullDescription := fmt.Sprintf("%s\nEndpoint: GET %s", description, path)
-		if len(operation.Tags) > 0 {
-			fullDescription += fmt.Sprintf("\nTags: %s", strings.Join(operation.Tags, ", "))
-		}
+	// Create the tool, exposing mapped parameters under their tool names
+	return c.renameToolParams(mcp.NewTool(toolName, toolOptions...)), true
+}
 
-		// Create tool options
-		toolOptions := []mcp.ToolOption{
-			mcp.WithDescription(fullDescription),
-		}
+// SetTagPrefixes maps tags to prefixes inserted into tool names, e.g. repository -> repo turns
+// quay_listRepos into quay_repo_listRepos
+func (c *QuayClient) SetTagPrefixes(prefixes map[string]string) {
+	c.tagPrefixes = prefixes
+}
 
-		// Add path parameters to input schema
-		if c.HasPathParameters(path) {
-			// Extract parameter names from path
-			pathParams := extractPathParameterNames(path)
-			for _, paramName := range pathParams {
-				toolOptions = append(toolOptions,
-					mcp.WithString(paramName,
-						mcp.Required(),
-						mcp.Description(fmt.Sprintf("Path parameter: %s", paramName)),
-					),
-				)
-			}
-		}
+// DefaultToolPrefix is the prefix of every tool name unless SetToolPrefix changes it
+const DefaultToolPrefix = "quay_"
 
-		// Add query parameters from the operation
-		if operation.Parameters != nil {
-			for _, param := range operation.Parameters {
-				if param != nil && param.In == "query" {
-					paramName := param.Name
-					paramDescription := param.Description
-					if paramDescription == "" {
-						paramDescription = fmt.Sprintf("Query parameter: %s", paramName)
-					}
+// toolPrefixPattern matches the characters MCP clients accept in tool names
+var toolPrefixPattern = regexp.MustCompile(`^[A-Za-z0-9_-]*$`)
 
-					// Query parameters are optional by default
-					toolOptions = append(toolOptions,
-						mcp.WithString(paramName,
-							mcp.Description(paramDescription),
-						),
-					)
-				}
-			}
-		}
+// SetToolPrefix replaces the quay_ prefix of every tool name, so several servers can namespace
+// their tools per registry, e.g. prod_ and staging_
+func (c *QuayClient) SetToolPrefix(prefix string) error {
+	if !toolPrefixPattern.MatchString(prefix) {
+		return fmt.Errorf("tool prefix %q may only contain letters, digits, _ and -", prefix)
+	}
+	c.toolPrefix = prefix
+	return nil
+}
 
-		// Add a special "resource_uri" parameter for all tools to maintain compatibility
-		toolOptions = append(toolOptions,
-			mcp.WithString("resource_uri",
-				mcp.Description("Optional: Custom resource URI (e.g., quay://repository/myorg/myrepo). If not provided, will be constructed from path parameters."),
-			),
-		)
+// ToolPrefix returns the prefix of every tool name
+func (c *QuayClient) ToolPrefix() string {
+	return c.toolPrefix
+}
 
-		// Create the tool
-		tool := mcp.NewTool(toolName, toolOptions...)
+// Tool name sources accepted by SetToolNameSource
+const (
+	ToolNameFromOperationID = "operationId"
+	ToolNameFromPath        = "path"
+)
 
-		tools = append(tools, tool)
+// SetToolNameSource selects whether tool names come from operation IDs (the default) or from
+// the cleaned path of every endpoint
+func (c *QuayClient) SetToolNameSource(source string) error {
+	switch source {
+	case "", ToolNameFromOperationID, ToolNameFromPath:
+		c.toolNameFromPath = source == ToolNameFromPath
+		return nil
 	}
+	return fmt.Errorf("unknown tool name source %q (expected %s or %s)", source, ToolNameFromOperationID, ToolNameFromPath)
+}
 
-	return tools
+// ToolName returns the tool name for an operation: the tool prefix, then the prefix of its first mapped tag,
+// then the operation ID or, when it has none or path naming is selected, the path identifier. Path
+// identifiers of non-GET operations end in the lowercase method so they don't collide with the GET.
+func (c *QuayClient) ToolName(method, operationID, path string, tags []string) string {
+	identifier := operationID
+	if identifier == "" || c.toolNameFromPath {
+		// Create a clean tool name from the path
+		identifier = PathIdentifier(path)
+		if method != "" && method != http.MethodGet {
+			identifier += "_" + strings.ToLower(method)
+		}
+	}
+
+	for _, tag := range tags {
+		if prefix, ok := c.tagPrefixes[tag]; ok && prefix != "" {
+			return c.toolPrefix + prefix + "_" + identifier
+		}
+	}
+	return c.toolPrefix + identifier
 }
 
-// extractPathParameterNames extracts parameter names from a path template
+// hasPaths reports whether a built model has a usable paths map
+func hasPaths(model *libopenapi.DocumentModel[v2high.Swagger]) bool {
+	return model.Model.Paths != nil && model.Model.Paths.PathItems != nil
+}
+
+// pathParamPattern matches {param} placeholders in a path template, including typed or
+// regex-constrained ones such as {repopath:.*}; the constraint is captured separately
+var pathParamPattern = regexp.MustCompile(`\{([^}:]+)(?::([^}]*))?\}`)
+
+// extractPathParameterNames extracts parameter names from a path template, dropping any :constraint
 func extractPathParameterNames(path string) []string {
 	var paramNames []string
 
 	// Find all {param} patterns
-	start := 0
-	for {
-		startIdx := strings.Index(path[start:], "{")
-		if startIdx == -1 {
-			break
-		}
-		startIdx += start
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		paramNames = append(paramNames, match[1])
+	}
 
-		endIdx := strings.Index(path[startIdx:], "}")
-		if endIdx == -1 {
-			break
+	return paramNames
+}
+
+// PathParameterNames returns the names of the placeholders in a path template
+func PathParameterNames(path string) []string {
+	return extractPathParameterNames(path)
+}
+
+// ResourceURI returns the quay:// URI of a path template, with placeholder constraints such as
+// {repopath:.*} reduced to {repopath} so the URI is also a valid RFC 6570 template
+func ResourceURI(path string) string {
+	return "quay://" + pathParamPattern.ReplaceAllString(strings.TrimPrefix(path, "/"), "{$1}")
+}
+
+// substitutePathParameters replaces each placeholder in path with the value returned by lookup,
+// leaving placeholders without a value untouched
+func substitutePathParameters(path string, lookup func(name string) (string, bool)) string {
+	return pathParamPattern.ReplaceAllStringFunc(path, func(placeholder string) string {
+		name := pathParamPattern.FindStringSubmatch(placeholder)[1]
+		if value, ok := lookup(name); ok {
+			return value
 		}
-		endIdx += startIdx
+		return placeholder
+	})
+}
 
-		paramName := path[startIdx+1 : endIdx]
-		paramNames = append(paramNames, paramName)
-		start = endIdx + 1
+// PathIdentifier derives the path-based tool identifier used when an operation has no ID,
+// e.g. /api/v1/repository/{repopath:.*}/tag becomes api_v1_repository_repopath_tag
+func PathIdentifier(path string) string {
+	identifier := pathParamPattern.ReplaceAllString(path, "{$1}")
+	identifier = strings.ReplaceAll(strings.Trim(identifier, "/"), "/", "_")
+	identifier = strings.ReplaceAll(identifier, "{", "")
+	identifier = strings.ReplaceAll(identifier, "}", "")
+	if identifier == "" {
+		identifier = "root"
 	}
-
-	return paramNames
+	return identifier
 }
@@ -0,0 +1,1720 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	v2high "github.com/pb33f/libopenapi/datamodel/high/v2"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// newTestClient serves spec from a mock discovery endpoint and returns a client that has loaded it
+func newTestClient(t *testing.T, spec string) *QuayClient {
+	t.Helper()
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/discovery" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(spec))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(mockServer.Close)
+
+	client := NewQuayClient(mockServer.URL, "")
+	if err := client.FetchSwaggerSpec(); err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	client.DiscoverEndpoints()
+	return client
+}
+
+// captureLogs sends log records of every level to a buffer for the rest of the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() {
+		slog.SetDefault(previous)
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+	})
+	return &logs
+}
+
+func TestResponseExample(t *testing.T) {
+	client := newTestClient(t, `{
+		"swagger": "2.0",
+		"basePath": "/api/v1",
+		"paths": {
+			"/api/v1/repository": {
+				"get": {
+					"operationId": "listRepos",
+					"tags": ["repository"],
+					"responses": {
+						"200": {
+							"description": "Successful invocation",
+							"examples": {
+								"application/json": {"repositories": [{"namespace": "redhat", "name": "ubi8"}]}
+							}
+						}
+					}
+				}
+			},
+			"/api/v1/repository/{repository}/tag/": {
+				"get": {
+					"operationId": "listRepoTags",
+					"tags": ["tag"],
+					"responses": {"200": {"description": "Successful invocation"}}
+				}
+			}
+		}
+	}`)
+
+	endpoint := client.GetEndpoints()["quay://api/v1/repository"]
+	if endpoint == nil {
+		t.Fatal("Expected listRepos endpoint to be discovered")
+	}
+	if endpoint.ResponseExample == "" {
+		t.Fatal("Expected a response example for listRepos")
+	}
+	if !strings.Contains(endpoint.ResponseExample, `"ubi8"`) {
+		t.Errorf("Expected example to contain the documented repository, got %s", endpoint.ResponseExample)
+	}
+
+	if example := client.GetEndpoints()["quay://api/v1/repository/{repository}/tag/"].ResponseExample; example != "" {
+		t.Errorf("Expected no example for listRepoTags, got %s", example)
+	}
+}
+
+func TestStrictParams(t *testing.T) {
+	var requests int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"repositories": []}`))
+	}))
+	defer mockServer.Close()
+
+	endpoint := &types.EndpointInfo{
+		Method: "GET",
+		Path:   "/api/v1/repository/{repository}",
+		Parameters: []interface{}{
+			&v2high.Parameter{Name: "repository", In: "path"},
+			&v2high.Parameter{Name: "public", In: "query"},
+		},
+	}
+	params := map[string]interface{}{
+		"repository": "redhat/ubi8",
+		"public":     "true",
+		"bogus":      "hallucinated",
+	}
+
+	client := NewQuayClient(mockServer.URL, "")
+
+	// Lenient mode passes the unknown argument through
+	if _, err := client.MakeAPICallWithParams(endpoint, params); err != nil {
+		t.Fatalf("Expected no error in lenient mode, got %v", err)
+	}
+	if requests != 1 {
+		t.Fatalf("Expected 1 request in lenient mode, got %d", requests)
+	}
+
+	// Strict mode rejects it before making a request
+	client.SetStrictParams(true)
+	_, err := client.MakeAPICallWithParams(endpoint, params)
+	if err == nil {
+		t.Fatal("Expected an error for an unknown parameter in strict mode")
+	}
+	if !strings.Contains(err.Error(), "bogus") {
+		t.Errorf("Expected error to name the unknown parameter, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected no request in strict mode, got %d total", requests)
+	}
+
+	// Known parameters still work in strict mode
+	delete(params, "bogus")
+	if _, err := client.MakeAPICallWithParams(endpoint, params); err != nil {
+		t.Errorf("Expected no error with known parameters, got %v", err)
+	}
+}
+
+func TestSpecURLSeparateFromAPI(t *testing.T) {
+	specServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/discovery" {
+			t.Errorf("Unexpected request to spec server: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"swagger": "2.0",
+			"paths": {
+				"/api/v1/repository": {
+					"get": {"operationId": "listRepos", "tags": ["repository"]}
+				}
+			}
+		}`))
+	}))
+	defer specServer.Close()
+
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/discovery" {
+			t.Error("Discovery should not be fetched from the API server")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"repositories": []}`))
+	}))
+	defer apiServer.Close()
+
+	client := NewQuayClient(apiServer.URL, "")
+	client.SetSpecURL(specServer.URL)
+
+	if err := client.FetchSwaggerSpec(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	client.DiscoverEndpoints()
+
+	endpoint := client.GetEndpoints()["quay://api/v1/repository"]
+	if endpoint == nil {
+		t.Fatal("Expected listRepos endpoint to be discovered from the spec server")
+	}
+
+	apiURL, err := client.BuildAPIURLWithParams(endpoint, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(apiURL, apiServer.URL) {
+		t.Errorf("Expected API URL on %s, got %s", apiServer.URL, apiURL)
+	}
+
+	data, err := client.MakeAPICallWithParams(endpoint, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(data) != `{"repositories": []}` {
+		t.Errorf("Unexpected response %s", data)
+	}
+}
+
+func TestConstrainedPathParameters(t *testing.T) {
+	path := "/api/v1/repository/{repopath:.*}/tag/{tag}"
+
+	names := extractPathParameterNames(path)
+	if len(names) != 2 || names[0] != "repopath" || names[1] != "tag" {
+		t.Fatalf("Expected [repopath tag], got %v", names)
+	}
+
+	client := NewQuayClient("https://quay.io", "")
+	endpoint := &types.EndpointInfo{Method: "GET", Path: path}
+
+	apiURL, err := client.BuildAPIURLWithParams(endpoint, map[string]interface{}{
+		"repopath": "redhat/ubi8",
+		"tag":      "latest",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if expected := "https://quay.io/api/v1/repository/redhat/ubi8/tag/latest"; apiURL != expected {
+		t.Errorf("Expected URL '%s', got '%s'", expected, apiURL)
+	}
+
+	// Placeholders are never left in the URL
+	_, err = client.BuildAPIURLWithParams(endpoint, map[string]interface{}{"tag": "latest"})
+	var missingErr *MissingPathParametersError
+	if !errors.As(err, &missingErr) || len(missingErr.Names) != 1 || missingErr.Names[0] != "repopath" {
+		t.Errorf("Expected a missing repopath error, got %v", err)
+	}
+
+	params := client.extractPathParameters("quay://api/v1/repository/redhat/ubi8/tag/latest", path)
+	if params["repopath"] != "redhat/ubi8" || params["tag"] != "latest" {
+		t.Errorf("Expected repopath=redhat/ubi8 and tag=latest, got %v", params)
+	}
+
+	if identifier := PathIdentifier(path); identifier != "api_v1_repository_repopath_tag_tag" {
+		t.Errorf("Expected identifier 'api_v1_repository_repopath_tag_tag', got '%s'", identifier)
+	}
+}
+
+func TestChunkedResponseLimit(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// Flushing between writes forces a chunked response without Content-Length
+		for _, chunk := range []string{`{"tags": [`, `"a", "b", `, `"c"]}`} {
+			w.Write([]byte(chunk))
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer mockServer.Close()
+
+	logs := captureLogs(t)
+
+	endpoint := &types.EndpointInfo{Method: "GET", Path: "/tags"}
+	client := NewQuayClient(mockServer.URL, "")
+	client.SetMaxResponseBytes(1024)
+
+	data, err := client.MakeAPICallWithParams(endpoint, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(data) != `{"tags": ["a", "b", "c"]}` {
+		t.Errorf("Unexpected response %s", data)
+	}
+	if !strings.Contains(logs.String(), fmt.Sprintf("bytes=%d", len(data))) {
+		t.Errorf("Expected the logged size to match the %d bytes read, got %q", len(data), logs.String())
+	}
+	if !strings.Contains(logs.String(), "content_length=unknown") {
+		t.Errorf("Expected the unknown content length to be logged")
+	}
+
+	client.SetMaxResponseBytes(10)
+	if _, err := client.MakeAPICallWithParams(endpoint, nil); err == nil || !strings.Contains(err.Error(), "exceeds the maximum of 10 bytes") {
+		t.Errorf("Expected a size limit error for the chunked response, got %v", err)
+	}
+}
+
+func TestJSONOnlyFilter(t *testing.T) {
+	spec := `{
+		"swagger": "2.0",
+		"produces": ["application/json"],
+		"paths": {
+			"/api/v1/repository": {
+				"get": {"operationId": "listRepos", "tags": ["repository"]}
+			},
+			"/api/v1/repository/{repository}/manifest/{manifestref}/raw": {
+				"get": {"operationId": "getRawManifest", "tags": ["manifest"], "produces": ["application/octet-stream"]}
+			},
+			"/api/v1/repository/{repository}/tag/": {
+				"get": {"operationId": "listRepoTags", "tags": ["tag"], "produces": ["application/vnd.quay+json; charset=utf-8"]}
+			}
+		}
+	}`
+
+	client := newTestClient(t, spec)
+	if len(client.GetEndpoints()) != 3 || len(client.GenerateTools()) != 3 {
+		t.Fatalf("Expected all 3 endpoints without filtering, got %d", len(client.GetEndpoints()))
+	}
+
+	client.SetJSONOnly(true)
+	client.DiscoverEndpoints()
+
+	if _, exists := client.GetEndpoints()["quay://api/v1/repository/{repository}/manifest/{manifestref}/raw"]; exists {
+		t.Error("Expected the non-JSON endpoint to be excluded from discovery")
+	}
+	if len(client.GetEndpoints()) != 2 {
+		t.Errorf("Expected 2 JSON endpoints, got %d", len(client.GetEndpoints()))
+	}
+
+	for _, tool := range client.GenerateTools() {
+		if tool.Name == "quay_getRawManifest" {
+			t.Error("Expected no tool for the non-JSON endpoint")
+		}
+	}
+}
+
+func TestMaxURLLength(t *testing.T) {
+	var requests int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewQuayClient(mockServer.URL, "")
+	client.SetMaxURLLength(100)
+	endpoint := &types.EndpointInfo{Method: "GET", Path: "/api/v1/find/repositories"}
+
+	_, err := client.MakeAPICallWithParams(endpoint, map[string]interface{}{"query": strings.Repeat("x", 200)})
+	if err == nil || !strings.Contains(err.Error(), "exceeding the maximum of 100") {
+		t.Fatalf("Expected a URL length error, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request for an over-long URL, got %d", requests)
+	}
+
+	if _, err := client.MakeAPICallWithParams(endpoint, map[string]interface{}{"query": "ubi"}); err != nil {
+		t.Errorf("Expected a short URL to succeed, got %v", err)
+	}
+}
+
+func TestDisabledTags(t *testing.T) {
+	client := newTestClient(t, `{
+		"swagger": "2.0",
+		"paths": {
+			"/api/v1/repository": {
+				"get": {"operationId": "listRepos", "tags": ["repository"]}
+			},
+			"/api/v1/repository/{repository}/manifest/{manifestref}": {
+				"get": {"operationId": "getRepoManifest", "tags": ["manifest"]}
+			},
+			"/api/v1/organization/{orgname}": {
+				"get": {"operationId": "getOrganization", "tags": ["organization"]}
+			}
+		}
+	}`)
+
+	client.SetDisabledTags([]string{"manifest"})
+	client.DiscoverEndpoints()
+
+	if len(client.GetEndpoints()) != 2 {
+		t.Errorf("Expected 2 endpoints with manifest disabled, got %d", len(client.GetEndpoints()))
+	}
+	if _, exists := client.GetEndpoints()["quay://api/v1/repository/{repository}/manifest/{manifestref}"]; exists {
+		t.Error("Expected the manifest endpoint to be excluded")
+	}
+
+	names := make(map[string]bool)
+	for _, tool := range client.GenerateTools() {
+		names[tool.Name] = true
+	}
+	if names["quay_getRepoManifest"] || !names["quay_listRepos"] || !names["quay_getOrganization"] {
+		t.Errorf("Expected only repository and organization tools, got %v", names)
+	}
+}
+
+func TestRetryEmpty200(t *testing.T) {
+	var requests int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		if requests%2 == 1 {
+			return // empty 200
+		}
+		w.Write([]byte(`{"tags": []}`))
+	}))
+	defer mockServer.Close()
+
+	endpoint := &types.EndpointInfo{Method: "GET", Path: "/tags"}
+	client := NewQuayClient(mockServer.URL, "")
+
+	// Without the option the empty body is passed through
+	data, err := client.MakeAPICallWithParams(endpoint, nil)
+	if err != nil || len(data) != 0 || requests != 1 {
+		t.Fatalf("Expected an empty pass-through after 1 request, got %q, %v after %d requests", data, err, requests)
+	}
+
+	requests = 0
+	client.SetRetryEmpty200(true)
+	data, err = client.MakeAPICallWithParams(endpoint, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(data) != `{"tags": []}` {
+		t.Errorf("Expected data from the retry, got %q", data)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestRetryStatuses(t *testing.T) {
+	var requests int
+	status := http.StatusServiceUnavailable
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"tags": []}`))
+	}))
+	defer mockServer.Close()
+
+	endpoint := &types.EndpointInfo{Method: "GET", Path: "/tags"}
+	client := NewQuayClient(mockServer.URL, "")
+	client.retryBackoff = time.Millisecond
+	client.SetRetryStatuses([]int{http.StatusTooManyRequests})
+
+	// A status outside the configured list fails without a retry
+	if _, err := client.MakeAPICallWithParams(endpoint, nil); err == nil {
+		t.Fatal("Expected an error for an unconfigured status")
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+
+	// A configured status is retried
+	requests = 0
+	status = http.StatusTooManyRequests
+	data, err := client.MakeAPICallWithParams(endpoint, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(data) != `{"tags": []}` {
+		t.Errorf("Expected data from the retry, got %q", data)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestRetryLogVolume(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"message": "down for maintenance"}`))
+	}))
+	defer mockServer.Close()
+
+	const retries = 5
+	client := NewQuayClient(mockServer.URL, "")
+	client.retryBackoff = time.Millisecond
+	client.SetMaxRetries(retries)
+	logs := captureLogs(t)
+
+	if _, err := client.MakeAPICallWithParams(&types.EndpointInfo{Method: "GET", Path: "/tags"}, nil); err == nil {
+		t.Fatal("Expected an error once the retries run out")
+	}
+
+	output := logs.String()
+	if got := strings.Count(output, "Request failed, retrying"); got != retries {
+		t.Errorf("Expected one line per retry, got %d:\n%s", got, output)
+	}
+	if got := strings.Count(output, "Quay API request failed"); got != 1 {
+		t.Errorf("Expected one outcome line, got %d:\n%s", got, output)
+	}
+	if !strings.Contains(output, "attempts=6") {
+		t.Errorf("Expected the outcome to count the attempts, got:\n%s", output)
+	}
+	// The request dump, the retries and the outcome, however many attempts failed
+	if lines := strings.Count(output, "\n"); lines > retries+2 {
+		t.Errorf("Expected at most %d log lines, got %d:\n%s", retries+2, lines, output)
+	}
+}
+
+func TestParamTypeHints(t *testing.T) {
+	var query string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/discovery" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{
+				"swagger": "2.0",
+				"paths": {
+					"/api/v1/repository": {
+						"get": {
+							"operationId": "listRepos",
+							"tags": ["repository"],
+							"parameters": [{"name": "limit", "in": "query"}]
+						}
+					}
+				}
+			}`))
+			return
+		}
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"repositories": []}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewQuayClient(mockServer.URL, "")
+	if err := client.FetchSwaggerSpec(); err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	client.DiscoverEndpoints()
+
+	hintsFile := filepath.Join(t.TempDir(), "hints.json")
+	if err := os.WriteFile(hintsFile, []byte(`{"limit": "int"}`), 0o600); err != nil {
+		t.Fatalf("Failed to write hints file: %v", err)
+	}
+	if err := client.LoadParamTypeHints(hintsFile); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tools := client.GenerateTools()
+	if len(tools) != 1 {
+		t.Fatalf("Expected 1 tool, got %d", len(tools))
+	}
+	limit, _ := tools[0].InputSchema.Properties["limit"].(map[string]any)
+	if limit["type"] != "integer" {
+		t.Errorf("Expected limit to be an integer, got %v", limit["type"])
+	}
+
+	// The typed argument is coerced back into the query string
+	endpoint := client.GetEndpoints()["quay://api/v1/repository"]
+	if _, err := client.MakeAPICallWithParams(endpoint, map[string]interface{}{"limit": float64(25)}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if query != "limit=25" {
+		t.Errorf("Expected query 'limit=25', got %q", query)
+	}
+
+	if err := client.SetParamTypeHints(map[string]string{"limit": "uuid"}); err == nil {
+		t.Error("Expected an error for an unsupported type")
+	}
+}
+
+func TestTagDescriptions(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"swagger": "2.0",
+			"paths": {
+				"/api/v1/organization/{orgname}/robots": {
+					"get": {"operationId": "getOrgRobots", "summary": "List robots", "tags": ["robot"]}
+				},
+				"/api/v1/repository": {
+					"get": {"operationId": "listRepos", "summary": "List repositories", "tags": ["repository"]}
+				}
+			}
+		}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewQuayClient(mockServer.URL, "")
+	if err := client.FetchSwaggerSpec(); err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	client.DiscoverEndpoints()
+
+	mapFile := filepath.Join(t.TempDir(), "tags.json")
+	if err := os.WriteFile(mapFile, []byte(`{"robot": "Robot accounts are non-human credentials for automation."}`), 0o600); err != nil {
+		t.Fatalf("Failed to write tag description map: %v", err)
+	}
+	if err := client.LoadTagDescriptions(mapFile); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	descriptions := map[string]string{}
+	for _, tool := range client.GenerateTools() {
+		descriptions[tool.Name] = tool.Description
+	}
+	if !strings.Contains(descriptions["quay_getOrgRobots"], "Tags: robot\nrobot: Robot accounts are non-human credentials for automation.") {
+		t.Errorf("Expected the robot tag description, got %q", descriptions["quay_getOrgRobots"])
+	}
+	if strings.Contains(descriptions["quay_listRepos"], "Robot accounts") {
+		t.Errorf("Expected no tag description for other tags, got %q", descriptions["quay_listRepos"])
+	}
+
+	if err := os.WriteFile(mapFile, []byte(`["robot"]`), 0o600); err != nil {
+		t.Fatalf("Failed to write tag description map: %v", err)
+	}
+	if err := client.LoadTagDescriptions(mapFile); err == nil {
+		t.Error("Expected an error for a map that is not a JSON object")
+	}
+}
+
+func TestSlowCallWarning(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("slow") == "true" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"tags": []}`))
+	}))
+	defer mockServer.Close()
+
+	logs := captureLogs(t)
+
+	endpoint := &types.EndpointInfo{Method: "GET", Path: "/tags"}
+	client := NewQuayClient(mockServer.URL, "")
+	client.SetSlowCallThreshold(20 * time.Millisecond)
+
+	if _, err := client.MakeAPICallWithParams(endpoint, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Contains(logs.String(), "Slow call") {
+		t.Errorf("Expected no warning for a fast call, got %q", logs.String())
+	}
+
+	if _, err := client.MakeAPICallWithParams(endpoint, map[string]interface{}{"slow": "true"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(logs.String(), "level=WARN msg=\"Slow call\" method=GET path=/tags") {
+		t.Errorf("Expected a slow call warning naming the endpoint, got %q", logs.String())
+	}
+}
+
+func TestModelWithoutPaths(t *testing.T) {
+	client := newTestClient(t, `{"swagger": "2.0", "info": {"title": "Quay", "version": "v1"}}`)
+
+	client.DiscoverEndpoints()
+	if len(client.GetEndpoints()) != 0 {
+		t.Errorf("Expected no endpoints, got %d", len(client.GetEndpoints()))
+	}
+	if tools := client.GenerateTools(); len(tools) != 0 {
+		t.Errorf("Expected no tools, got %d", len(tools))
+	}
+
+	// A model that was built without a paths map must not panic either
+	client.GetModel().Model.Paths = nil
+	client.DiscoverEndpoints()
+	if tools := client.GenerateTools(); len(tools) != 0 {
+		t.Errorf("Expected no tools, got %d", len(tools))
+	}
+}
+
+func TestEndpointAllowlist(t *testing.T) {
+	client := newTestClient(t, `{
+		"swagger": "2.0",
+		"paths": {
+			"/api/v1/repository": {
+				"get": {"operationId": "listRepos", "tags": ["repository"]}
+			},
+			"/api/v1/organization/{orgname}": {
+				"get": {"operationId": "getOrganization", "tags": ["organization"]}
+			},
+			"/api/v1/superuser/users/": {
+				"get": {"operationId": "listAllUsers", "tags": ["superuser"]}
+			}
+		}
+	}`)
+
+	allowlistFile := filepath.Join(t.TempDir(), "allowlist")
+	content := "# reviewed endpoints\nGET /api/v1/repository\n\nget /api/v1/superuser/users/\n"
+	if err := os.WriteFile(allowlistFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LoadEndpointAllowlist(allowlistFile); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	client.DiscoverEndpoints()
+
+	if len(client.GetEndpoints()) != 2 {
+		t.Errorf("Expected 2 endpoints, got %d", len(client.GetEndpoints()))
+	}
+
+	names := make(map[string]bool)
+	for _, tool := range client.GenerateTools() {
+		names[tool.Name] = true
+	}
+	// The listed superuser endpoint is exposed even though its tag isn't allowed by default
+	if len(names) != 2 || !names["quay_listRepos"] || !names["quay_listAllUsers"] {
+		t.Errorf("Expected only the listed endpoints as tools, got %v", names)
+	}
+
+	if err := os.WriteFile(allowlistFile, []byte("/api/v1/repository\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LoadEndpointAllowlist(allowlistFile); err == nil {
+		t.Error("Expected an error for an entry without a method")
+	}
+}
+
+func TestTraceHeaders(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.Header().Set("ETag", `"abc123"`)
+		w.Header().Set("X-Internal-Node", "node-7")
+		w.Write([]byte(`{"tags": []}`))
+	}))
+	defer mockServer.Close()
+
+	logs := captureLogs(t)
+
+	endpoint := &types.EndpointInfo{Method: "GET", Path: "/tags"}
+	client := NewQuayClient(mockServer.URL, "")
+	client.SetTraceHeaders([]string{"x-ratelimit-remaining", "ETag"})
+
+	if _, err := client.MakeAPICallWithParams(endpoint, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	output := logs.String()
+	for _, want := range []string{"headers.X-Ratelimit-Remaining=42", `headers.Etag="\"abc123\""`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q to be logged, got %q", want, output)
+		}
+	}
+	for _, unwanted := range []string{"X-Internal-Node", "Content-Type"} {
+		if strings.Contains(output, unwanted) {
+			t.Errorf("Expected %s not to be logged, got %q", unwanted, output)
+		}
+	}
+}
+
+func TestNoFallbackDescription(t *testing.T) {
+	client := newTestClient(t, `{
+		"swagger": "2.0",
+		"paths": {
+			"/api/v1/repository": {
+				"get": {"operationId": "listRepos", "summary": "List repositories", "tags": ["repository"]}
+			},
+			"/api/v1/organization/{orgname}": {
+				"get": {"operationId": "getOrganization", "description": "Get an organization", "tags": ["organization"]}
+			},
+			"/api/v1/repository/{repository}/tag/": {
+				"get": {"operationId": "listRepoTags", "tags": ["tag"]}
+			}
+		}
+	}`)
+	client.DiscoverEndpoints()
+
+	if tools := client.GenerateTools(); len(tools) != 3 {
+		t.Fatalf("Expected 3 tools by default, got %d", len(tools))
+	}
+
+	client.SetNoFallbackDescription(true)
+	names := make(map[string]bool)
+	for _, tool := range client.GenerateTools() {
+		names[tool.Name] = true
+	}
+	if len(names) != 2 || names["quay_listRepoTags"] {
+		t.Errorf("Expected the description-less tool to be skipped, got %v", names)
+	}
+}
+
+func TestManifestAcceptHeader(t *testing.T) {
+	accepts := make(map[string]string)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accepts[r.URL.Path] = r.Header.Get("Accept")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer mockServer.Close()
+
+	manifest := &types.EndpointInfo{Method: "GET", Path: "/repository/{repository}/manifest/{manifestref}", Tags: []string{"manifest"}}
+	tags := &types.EndpointInfo{Method: "GET", Path: "/repository/{repository}/tag/", Tags: []string{"tag"}}
+	params := map[string]interface{}{"repository": "redhat/ubi8", "manifestref": "sha256:abc"}
+
+	client := NewQuayClient(mockServer.URL, "")
+	for _, endpoint := range []*types.EndpointInfo{manifest, tags} {
+		if _, err := client.MakeAPICallWithParams(endpoint, params); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if got := accepts["/repository/redhat/ubi8/manifest/sha256:abc"]; got != strings.Join(DefaultManifestAccept, ", ") {
+		t.Errorf("Expected the manifest media types, got %q", got)
+	}
+	if got := accepts["/repository/redhat/ubi8/tag/"]; got != "application/json" {
+		t.Errorf("Expected application/json for a non-manifest endpoint, got %q", got)
+	}
+
+	client.SetManifestAccept([]string{"application/vnd.oci.image.manifest.v1+json"})
+	if _, err := client.MakeAPICallWithParams(manifest, params); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := accepts["/repository/redhat/ubi8/manifest/sha256:abc"]; got != "application/vnd.oci.image.manifest.v1+json" {
+		t.Errorf("Expected the configured media type, got %q", got)
+	}
+}
+
+func TestWriteMethods(t *testing.T) {
+	client := newTestClient(t, `{
+		"swagger": "2.0",
+		"paths": {
+			"/api/v1/repository": {
+				"get": {"operationId": "listRepos", "tags": ["repository"]},
+				"post": {"operationId": "createRepo", "tags": ["repository"]}
+			},
+			"/api/v1/repository/{repository}": {
+				"get": {"tags": ["repository"]},
+				"delete": {"tags": ["repository"]}
+			}
+		}
+	}`)
+
+	if len(client.GetEndpoints()) != 2 {
+		t.Errorf("Expected only the 2 GET endpoints by default, got %d", len(client.GetEndpoints()))
+	}
+
+	if err := client.SetMethods([]string{"get", "POST", "DELETE"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	client.DiscoverEndpoints()
+
+	if len(client.GetEndpoints()) != 4 {
+		t.Errorf("Expected 4 endpoints, got %d", len(client.GetEndpoints()))
+	}
+	names := make(map[string]bool)
+	for _, tool := range client.GenerateTools() {
+		names[tool.Name] = true
+	}
+	for _, name := range []string{"quay_listRepos", "quay_createRepo", "quay_api_v1_repository_repository", "quay_api_v1_repository_repository_delete"} {
+		if !names[name] {
+			t.Errorf("Expected tool %s, got %v", name, names)
+		}
+	}
+
+	if err := client.SetMethods([]string{"GET", "TRACE"}); err == nil {
+		t.Error("Expected an error for an unsupported method")
+	}
+	if err := client.SetTagMethods(map[string][]string{"tag": {"GET", "TRACE"}}); err == nil {
+		t.Error("Expected an error for an unsupported per-tag method")
+	}
+
+	var method, query, contentType string
+	var body []byte
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, query, contentType = r.Method, r.URL.RawQuery, r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer mockServer.Close()
+
+	create := &types.EndpointInfo{
+		Method: "POST",
+		Path:   "/api/v1/repository",
+		Parameters: []interface{}{
+			&v2high.Parameter{Name: "dry_run", In: "query"},
+		},
+	}
+	caller := NewQuayClient(mockServer.URL, "")
+	if _, err := caller.MakeAPICallWithParams(create, map[string]interface{}{
+		"namespace":  "redhat",
+		"repository": "ubi8",
+		"dry_run":    true,
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if method != "POST" {
+		t.Errorf("Expected a POST request, got %s", method)
+	}
+	if query != "dry_run=true" {
+		t.Errorf("Expected only the declared query parameter in the URL, got %q", query)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Expected a JSON content type, got %q", contentType)
+	}
+	if string(body) != `{"namespace":"redhat","repository":"ubi8"}` {
+		t.Errorf("Expected the remaining arguments as the body, got %s", body)
+	}
+
+	remove := &types.EndpointInfo{Method: "DELETE", Path: "/api/v1/repository/{repository}"}
+	if _, err := caller.MakeAPICallWithParams(remove, map[string]interface{}{"repository": "redhat/ubi8"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if method != "DELETE" || string(body) != `{}` {
+		t.Errorf("Expected a DELETE with an empty JSON body, got %s %s", method, body)
+	}
+}
+
+func TestSuccessStatuses(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/created":
+			w.WriteHeader(http.StatusCreated)
+		case "/not-modified":
+			w.WriteHeader(http.StatusNotModified)
+		}
+	}))
+	defer mockServer.Close()
+
+	created := &types.EndpointInfo{Method: "GET", Path: "/created"}
+	notModified := &types.EndpointInfo{Method: "GET", Path: "/not-modified"}
+	client := NewQuayClient(mockServer.URL, "")
+
+	// By default any 2xx is success and a 3xx is an error
+	if _, err := client.MakeAPICallWithParams(created, nil); err != nil {
+		t.Errorf("Expected 201 to succeed by default, got %v", err)
+	}
+	var apiErr *APIError
+	if _, err := client.MakeAPICallWithParams(notModified, nil); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotModified {
+		t.Errorf("Expected an APIError with status 304, got %v", err)
+	}
+
+	client.SetSuccessStatuses([]StatusRange{{Min: 200, Max: 200}, {Min: 300, Max: 399}})
+	if _, err := client.MakeAPICallWithParams(notModified, nil); err != nil {
+		t.Errorf("Expected 304 to succeed in the custom range, got %v", err)
+	}
+	if _, err := client.MakeAPICallWithParams(created, nil); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusCreated {
+		t.Errorf("Expected an APIError with status 201, got %v", err)
+	}
+}
+
+func TestRequestBodyParameter(t *testing.T) {
+	var received []byte
+	var contentType string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/discovery" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{
+				"swagger": "2.0",
+				"paths": {
+					"/api/v1/repository": {
+						"post": {
+							"operationId": "createRepo",
+							"tags": ["repository"],
+							"parameters": [
+								{"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/NewRepo"}}
+							]
+						}
+					},
+					"/api/v1/organization/{orgname}/prototypes": {
+						"post": {
+							"operationId": "createOrganizationPrototypePermission",
+							"tags": ["organization"],
+							"parameters": [
+								{"name": "orgname", "in": "path", "required": true, "type": "string"},
+								{"name": "body", "in": "body", "required": true, "schema": {"type": "object"}}
+							]
+						}
+					}
+				},
+				"definitions": {
+					"NewRepo": {
+						"type": "object",
+						"required": ["repository", "visibility"],
+						"properties": {
+							"repository": {"type": "string", "description": "Repository name"},
+							"visibility": {"type": "string"},
+							"description": {"type": "string"},
+							"repo_kind": {"type": "string"},
+							"quota": {"type": "integer"}
+						}
+					}
+				}
+			}`))
+			return
+		}
+		contentType = r.Header.Get("Content-Type")
+		received, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewQuayClient(mockServer.URL, "")
+	if err := client.SetMethods([]string{"POST"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.FetchSwaggerSpec(); err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	client.DiscoverEndpoints()
+
+	tools := make(map[string]mcp.Tool)
+	for _, tool := range client.GenerateTools() {
+		tools[tool.Name] = tool
+	}
+
+	create := tools["quay_createRepo"]
+	for _, field := range []string{"repository", "visibility", "description", "repo_kind", "quota"} {
+		if _, ok := create.InputSchema.Properties[field]; !ok {
+			t.Errorf("Expected body field %s as a tool parameter", field)
+		}
+	}
+	if quota, _ := create.InputSchema.Properties["quota"].(map[string]any); quota["type"] != "integer" {
+		t.Errorf("Expected quota to keep its integer type, got %v", quota)
+	}
+	if strings.Join(create.InputSchema.Required, ",") != "repository,visibility" {
+		t.Errorf("Expected the schema's required fields, got %v", create.InputSchema.Required)
+	}
+	if raw, _ := tools["quay_createOrganizationPrototypePermission"].InputSchema.Properties["body"].(map[string]any); raw["type"] != "object" {
+		t.Errorf("Expected an object body argument for a schema without properties, got %v", raw)
+	}
+
+	var endpoint *types.EndpointInfo
+	for _, ep := range client.GetEndpoints() {
+		if ep.OperationID == "createRepo" {
+			endpoint = ep
+		}
+	}
+	if _, err := client.MakeAPICallWithParams(endpoint, map[string]interface{}{
+		"repository": "ubi8",
+		"visibility": "public",
+		"quota":      float64(10),
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if contentType != "application/json" {
+		t.Errorf("Expected a JSON content type, got %q", contentType)
+	}
+	if string(received) != `{"quota":10,"repository":"ubi8","visibility":"public"}` {
+		t.Errorf("Expected the body fields as JSON, got %s", received)
+	}
+}
+
+func TestAllowedTags(t *testing.T) {
+	client := newTestClient(t, `{
+		"swagger": "2.0",
+		"paths": {
+			"/api/v1/repository": {
+				"get": {"operationId": "listRepos", "tags": ["repository"]}
+			},
+			"/api/v1/organization/{orgname}/team/{teamname}": {
+				"get": {"operationId": "getOrganizationTeam", "tags": ["team"]}
+			},
+			"/api/v1/superuser/users/": {
+				"get": {"operationId": "listAllUsers", "tags": ["superuser"]}
+			}
+		}
+	}`)
+
+	toolNames := func() map[string]bool {
+		names := make(map[string]bool)
+		for _, tool := range client.GenerateTools() {
+			names[tool.Name] = true
+		}
+		return names
+	}
+
+	client.SetAllowedTags([]string{"team", "repository"})
+	client.DiscoverEndpoints()
+	if len(client.GetEndpoints()) != 2 {
+		t.Errorf("Expected 2 endpoints, got %d", len(client.GetEndpoints()))
+	}
+	if names := toolNames(); len(names) != 2 || !names["quay_listRepos"] || !names["quay_getOrganizationTeam"] {
+		t.Errorf("Expected the tools to match the discovered endpoints, got %v", names)
+	}
+
+	for _, tags := range [][]string{nil, {"all"}} {
+		client.SetAllowedTags(tags)
+		client.DiscoverEndpoints()
+		if len(client.GetEndpoints()) != 3 {
+			t.Errorf("Expected tag filtering disabled for %v, got %d endpoints", tags, len(client.GetEndpoints()))
+		}
+		if names := toolNames(); len(names) != 3 {
+			t.Errorf("Expected 3 tools for %v, got %v", tags, names)
+		}
+	}
+}
+
+func TestTimeoutAndMaxRetries(t *testing.T) {
+	var requests atomic.Int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		if r.URL.Path == "/hang" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		// The first request is dropped without a response
+		if n == 1 {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"tags": []}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewQuayClient(mockServer.URL, "")
+	client.retryBackoff = time.Millisecond
+	client.SetTimeout(50 * time.Millisecond)
+
+	// A dropped connection is retried
+	data, err := client.MakeAPICallWithParams(&types.EndpointInfo{Method: "GET", Path: "/tags"}, nil)
+	if err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if string(data) != `{"tags": []}` || requests.Load() != 2 {
+		t.Errorf("Expected data after 2 requests, got %q after %d", data, requests.Load())
+	}
+
+	// A hung request times out on every attempt
+	requests.Store(0)
+	client.SetConnectionRetries(1)
+	start := time.Now()
+	if _, err := client.MakeAPICallWithParams(&types.EndpointInfo{Method: "GET", Path: "/hang"}, nil); err == nil {
+		t.Fatal("Expected a timeout error")
+	}
+	if requests.Load() != 2 {
+		t.Errorf("Expected 2 attempts, got %d", requests.Load())
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("Expected the timeout to cut the hung requests short, took %s", elapsed)
+	}
+}
+
+func TestConnectionRetries(t *testing.T) {
+	var requests atomic.Int32
+	var mu sync.Mutex
+	var fail func(w http.ResponseWriter) // how the first request fails, guarded by mu
+	setFail := func(f func(w http.ResponseWriter)) {
+		mu.Lock()
+		defer mu.Unlock()
+		fail = f
+	}
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		failFirst := fail
+		mu.Unlock()
+		if requests.Add(1) == 1 && failFirst != nil {
+			failFirst(w)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"tags": []}`))
+	}))
+	defer mockServer.Close()
+
+	reset := func(w http.ResponseWriter) {
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.(*net.TCPConn).SetLinger(0)
+		conn.Close()
+	}
+	truncated := func(w http.ResponseWriter) {
+		conn, buf, _ := w.(http.Hijacker).Hijack()
+		buf.WriteString("HTTP/1.1 200 OK\r\nContent-Type: application/json\r\nContent-Length: 100\r\n\r\n{\"tags\"")
+		buf.Flush()
+		conn.Close()
+	}
+
+	client := NewQuayClient(mockServer.URL, "")
+	client.retryBackoff = time.Millisecond
+	// Status retries don't limit connection retries
+	client.SetMaxRetries(0)
+
+	for _, tc := range []struct {
+		name   string
+		method string
+		fail   func(w http.ResponseWriter)
+		want   int
+	}{
+		{"reset GET is retried", http.MethodGet, reset, 2},
+		{"truncated GET is retried", http.MethodGet, truncated, 2},
+		{"reset DELETE is retried", http.MethodDelete, reset, 2},
+		{"reset POST is not retried", http.MethodPost, reset, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			requests.Store(0)
+			setFail(tc.fail)
+			_, err := client.MakeAPICallWithParams(&types.EndpointInfo{Method: tc.method, Path: "/tags"}, nil)
+			if tc.want > 1 && err != nil {
+				t.Fatalf("Expected the retry to succeed, got %v", err)
+			}
+			if tc.want == 1 && err == nil {
+				t.Fatal("Expected the connection error")
+			}
+			if got := requests.Load(); got != int32(tc.want) {
+				t.Errorf("Expected %d requests, got %d", tc.want, got)
+			}
+		})
+	}
+
+	requests.Store(0)
+	setFail(reset)
+	client.SetConnectionRetries(0)
+	if _, err := client.MakeAPICallWithParams(&types.EndpointInfo{Method: http.MethodGet, Path: "/tags"}, nil); err == nil {
+		t.Error("Expected the connection error without retries")
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected 1 request, got %d", got)
+	}
+}
+
+func TestFetchSwaggerSpecRetries(t *testing.T) {
+	var requests int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"swagger": "2.0", "paths": {}}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewQuayClient(mockServer.URL, "")
+	client.retryBackoff = time.Millisecond
+	if err := client.FetchSwaggerSpec(); err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+
+	requests = 0
+	client.SetMaxRetries(0)
+	if err := client.FetchSwaggerSpec(); err == nil {
+		t.Error("Expected the 503 to fail without retries")
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
+
+func TestGetWithRetryExhausted(t *testing.T) {
+	var requests int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer mockServer.Close()
+
+	client := NewQuayClient(mockServer.URL, "")
+	client.retryBackoff = time.Millisecond
+	client.SetMaxRetries(1)
+
+	// The last response's body is closed before giving up, so only an error comes back
+	resp, err := client.getWithRetry(mockServer.URL)
+	if err == nil || !strings.Contains(err.Error(), "status code 503") {
+		t.Errorf("Expected an error naming the status, got %v", err)
+	}
+	if resp != nil {
+		t.Error("Expected no response once the retries run out")
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestTypedQueryParameters(t *testing.T) {
+	var query url.Values
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/discovery" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{
+				"swagger": "2.0",
+				"paths": {
+					"/api/v1/repository": {
+						"get": {
+							"operationId": "listRepos",
+							"tags": ["repository"],
+							"parameters": [
+								{"name": "limit", "in": "query", "type": "integer"},
+								{"name": "public", "in": "query", "type": "boolean"},
+								{"name": "namespace", "in": "query", "type": "string"}
+							]
+						}
+					}
+				}
+			}`))
+			return
+		}
+		query = r.URL.Query()
+		w.Write([]byte(`{"repositories": []}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewQuayClient(mockServer.URL, "")
+	if err := client.FetchSwaggerSpec(); err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	client.DiscoverEndpoints()
+
+	tools := client.GenerateTools()
+	if len(tools) != 1 {
+		t.Fatalf("Expected 1 tool, got %d", len(tools))
+	}
+	for name, want := range map[string]string{"limit": "integer", "public": "boolean", "namespace": "string"} {
+		property, _ := tools[0].InputSchema.Properties[name].(map[string]any)
+		if property["type"] != want {
+			t.Errorf("Expected %s to be %s, got %v", name, want, property["type"])
+		}
+	}
+
+	endpoint := client.GetEndpoints()["quay://api/v1/repository"]
+	if _, err := client.MakeAPICallWithParams(endpoint, map[string]interface{}{
+		"limit":     float64(50),
+		"public":    false,
+		"namespace": "redhat",
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if query.Get("limit") != "50" || query.Get("public") != "false" || query.Get("namespace") != "redhat" {
+		t.Errorf("Expected the typed values in the query string, got %v", query)
+	}
+}
+
+func TestMaxParamValueLength(t *testing.T) {
+	var requests int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewQuayClient(mockServer.URL, "")
+	client.SetMaxParamValueLength(64)
+	endpoint := &types.EndpointInfo{Method: "GET", Path: "/api/v1/repository/{repository}"}
+
+	_, err := client.MakeAPICallWithParams(endpoint, map[string]interface{}{"repository": strings.Repeat("x", 65)})
+	if err == nil || !strings.Contains(err.Error(), `parameter "repository" is 65 characters, exceeding the maximum of 64`) {
+		t.Fatalf("Expected a parameter length error, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request for an over-long value, got %d", requests)
+	}
+
+	if _, err := client.MakeAPICallWithParams(endpoint, map[string]interface{}{"repository": "redhat/ubi8"}); err != nil {
+		t.Errorf("Expected a short value to succeed, got %v", err)
+	}
+}
+
+func TestBasePathNotDuplicated(t *testing.T) {
+	client := newTestClient(t, `{
+		"swagger": "2.0",
+		"basePath": "/api/v1",
+		"paths": {
+			"/api/v1/repository/{repository}": {
+				"get": {"operationId": "getRepo", "tags": ["repository"]}
+			}
+		}
+	}`)
+	base := client.GetRegistryURL()
+
+	byURI := &types.EndpointInfo{Method: "GET", Path: "/api/v1/repository/{namespace}/{repository}"}
+	apiURL, err := client.BuildAPIURL(byURI, "quay://api/v1/repository/myorg/myrepo")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := base + "/api/v1/repository/myorg/myrepo"; apiURL != want {
+		t.Errorf("Expected %s, got %s", want, apiURL)
+	}
+
+	endpoint := &types.EndpointInfo{Method: "GET", Path: "/api/v1/repository/{repository}"}
+	apiURL, err = client.BuildAPIURLWithParams(endpoint, map[string]interface{}{"repository": "myorg/myrepo"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := base + "/api/v1/repository/myorg/myrepo"; apiURL != want {
+		t.Errorf("Expected %s, got %s", want, apiURL)
+	}
+
+	// Paths relative to the base path still get it prepended
+	relative := &types.EndpointInfo{Method: "GET", Path: "/repository/{repository}"}
+	apiURL, err = client.BuildAPIURLWithParams(relative, map[string]interface{}{"repository": "myorg/myrepo"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := base + "/api/v1/repository/myorg/myrepo"; apiURL != want {
+		t.Errorf("Expected %s, got %s", want, apiURL)
+	}
+}
+
+func TestTagMethods(t *testing.T) {
+	client := newTestClient(t, `{
+		"swagger": "2.0",
+		"paths": {
+			"/api/v1/repository/{repository}/tag/{tag}": {
+				"get": {"operationId": "getTag", "tags": ["tag"]},
+				"put": {"operationId": "changeTag", "tags": ["tag"]},
+				"delete": {"operationId": "deleteTag", "tags": ["tag"]}
+			},
+			"/api/v1/organization/{orgname}": {
+				"get": {"operationId": "getOrganization", "tags": ["organization"]},
+				"delete": {"operationId": "deleteOrganization", "tags": ["organization"]}
+			},
+			"/api/v1/organization/{orgname}/tag/{tag}": {
+				"delete": {"operationId": "deleteOrganizationTag", "tags": ["organization", "tag"]}
+			},
+			"/api/v1/repository": {
+				"get": {"operationId": "listRepos", "tags": ["repository"]},
+				"post": {"operationId": "createRepo", "tags": ["repository"]}
+			}
+		}
+	}`)
+
+	if err := client.SetTagMethods(map[string][]string{
+		"tag":          {"GET", "delete"},
+		"organization": {"GET"},
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	client.DiscoverEndpoints()
+
+	names := make(map[string]bool)
+	for _, tool := range client.GenerateTools() {
+		names[tool.Name] = true
+	}
+	for _, name := range []string{"quay_getTag", "quay_deleteTag", "quay_getOrganization", "quay_listRepos"} {
+		if !names[name] {
+			t.Errorf("Expected tool %s, got %v", name, names)
+		}
+	}
+	// PUT isn't enabled for tag, organization stays read-only even alongside tag, and repository
+	// has no rule so it falls back to the global GET-only methods
+	for _, name := range []string{"quay_changeTag", "quay_deleteOrganization", "quay_deleteOrganizationTag", "quay_createRepo"} {
+		if names[name] {
+			t.Errorf("Expected no tool %s, got %v", name, names)
+		}
+	}
+}
+
+func TestRequestLogRedactsAuthorization(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"tags": []}`))
+	}))
+	defer mockServer.Close()
+
+	logs := captureLogs(t)
+
+	endpoint := &types.EndpointInfo{Method: "GET", Path: "/tags"}
+	client := NewQuayClient(mockServer.URL, "secret-token")
+	if _, err := client.MakeAPICallWithParams(endpoint, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	output := logs.String()
+	if !strings.Contains(output, `headers.Authorization="Bearer [REDACTED]"`) {
+		t.Errorf("Expected the redacted Authorization header in the request dump, got %q", output)
+	}
+	if strings.Contains(output, "secret-token") {
+		t.Errorf("Expected the token not to be logged, got %q", output)
+	}
+}
+
+func TestValidateWrites(t *testing.T) {
+	spec := newTestClient(t, `{
+		"swagger": "2.0",
+		"paths": {
+			"/api/v1/repository": {
+				"post": {"operationId": "createRepo", "tags": ["repository"], "parameters": [
+					{"name": "dry_run", "in": "query", "type": "boolean"}
+				]}
+			},
+			"/api/v1/repository/{repository}": {
+				"get": {"operationId": "getRepo", "tags": ["repository"]},
+				"delete": {"operationId": "deleteRepo", "tags": ["repository"]}
+			}
+		}
+	}`)
+	if err := spec.SetMethods([]string{"GET", "POST", "DELETE"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	spec.DiscoverEndpoints()
+
+	var requests []string
+	rejectProbes := false
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+		probe := r.URL.Query().Get("dry_run") == "true" || r.Method == http.MethodGet
+		if probe && rejectProbes {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_message": "Not Found"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer mockServer.Close()
+
+	client := spec.ForRegistry(mockServer.URL, "")
+	client.SetValidateWrites(true)
+	create := client.GetEndpoints()["POST quay://api/v1/repository"]
+	remove := client.GetEndpoints()["DELETE quay://api/v1/repository/{repository}"]
+
+	// The probe is made before the actual write
+	if _, err := client.MakeAPICallWithParams(create, map[string]interface{}{"repository": "ubi9"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.MakeAPICallWithParams(remove, map[string]interface{}{"repository": "ubi9"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := []string{
+		"POST /api/v1/repository?dry_run=true",
+		"POST /api/v1/repository",
+		"GET /api/v1/repository/ubi9",
+		"DELETE /api/v1/repository/ubi9",
+	}
+	if strings.Join(requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected requests %v, got %v", want, requests)
+	}
+
+	// A failing probe stops the write
+	requests = nil
+	rejectProbes = true
+	_, err := client.MakeAPICallWithParams(remove, map[string]interface{}{"repository": "missing"})
+	if err == nil || !strings.Contains(err.Error(), "was not sent") {
+		t.Errorf("Expected the write to be abandoned, got %v", err)
+	}
+	if len(requests) != 1 || requests[0] != "GET /api/v1/repository/missing" {
+		t.Errorf("Expected only the probe to be sent, got %v", requests)
+	}
+}
+
+func TestHeaderParameters(t *testing.T) {
+	var request *http.Request
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/discovery" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{
+				"swagger": "2.0",
+				"paths": {
+					"/api/v1/repository": {
+						"get": {
+							"operationId": "listRepos",
+							"tags": ["repository"],
+							"parameters": [
+								{"name": "page", "in": "query", "type": "integer"},
+								{"name": "page", "in": "header", "type": "string"},
+								{"name": "X-Quay-Trace", "in": "header", "type": "string", "description": "Trace tag"},
+								{"name": "Authorization", "in": "header", "type": "string"}
+							]
+						}
+					}
+				}
+			}`))
+			return
+		}
+		request = r
+		w.Write([]byte(`{"repositories": []}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewQuayClient(mockServer.URL, "secret-token")
+	if err := client.FetchSwaggerSpec(); err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	client.DiscoverEndpoints()
+	client.SetStrictParams(true)
+
+	tools := client.GenerateTools()
+	if len(tools) != 1 {
+		t.Fatalf("Expected 1 tool, got %d", len(tools))
+	}
+	properties := tools[0].InputSchema.Properties
+	for _, name := range []string{"page", "header_page", "X-Quay-Trace"} {
+		if _, ok := properties[name]; !ok {
+			t.Errorf("Expected a %s argument, got %v", name, properties)
+		}
+	}
+	if _, ok := properties["Authorization"]; ok {
+		t.Error("Expected the Authorization header not to be exposed")
+	}
+
+	endpoint := client.GetEndpoints()["quay://api/v1/repository"]
+	if _, err := client.MakeAPICallWithParams(endpoint, map[string]interface{}{
+		"page":         float64(2),
+		"header_page":  "cursor",
+		"X-Quay-Trace": "abc",
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := request.URL.RawQuery; got != "page=2" {
+		t.Errorf("Expected only the query parameter in the query string, got %q", got)
+	}
+	if got := request.Header.Get("page"); got != "cursor" {
+		t.Errorf("Expected the page header to be cursor, got %q", got)
+	}
+	if got := request.Header.Get("X-Quay-Trace"); got != "abc" {
+		t.Errorf("Expected the X-Quay-Trace header to be abc, got %q", got)
+	}
+	if got := request.Header.Get("Authorization"); got != "Bearer secret-token" {
+		t.Errorf("Expected the configured token, got %q", got)
+	}
+}
+
+func TestQueryParameterEnum(t *testing.T) {
+	client := newTestClient(t, `{
+		"swagger": "2.0",
+		"paths": {
+			"/api/v1/repository": {
+				"get": {
+					"operationId": "listRepos",
+					"tags": ["repository"],
+					"parameters": [
+						{"name": "sort", "in": "query", "type": "string", "enum": ["asc", "desc"]},
+						{"name": "limit", "in": "query", "type": "integer", "enum": [10, 50]},
+						{"name": "namespace", "in": "query", "type": "string"}
+					]
+				}
+			}
+		}
+	}`)
+
+	tools := client.GenerateTools()
+	if len(tools) != 1 {
+		t.Fatalf("Expected 1 tool, got %d", len(tools))
+	}
+	properties := tools[0].InputSchema.Properties
+
+	sort, _ := properties["sort"].(map[string]any)
+	if enum, _ := sort["enum"].([]string); fmt.Sprint(enum) != "[asc desc]" {
+		t.Errorf("Expected sort to allow asc and desc, got %v", sort["enum"])
+	}
+	limit, _ := properties["limit"].(map[string]any)
+	if enum, _ := limit["enum"].([]any); fmt.Sprint(enum) != "[10 50]" || limit["type"] != "integer" {
+		t.Errorf("Expected limit to be an integer allowing 10 and 50, got %v", limit)
+	}
+	namespace, _ := properties["namespace"].(map[string]any)
+	if _, ok := namespace["enum"]; ok {
+		t.Errorf("Expected namespace to have no enum, got %v", namespace["enum"])
+	}
+}
+
+func TestArrayQueryParameters(t *testing.T) {
+	var query string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/discovery" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{
+				"swagger": "2.0",
+				"paths": {
+					"/api/v1/repository/{repository}/tag/": {
+						"get": {
+							"operationId": "listRepoTags",
+							"tags": ["tag"],
+							"parameters": [
+								{"name": "repository", "in": "path", "type": "string", "required": true},
+								{"name": "tag", "in": "query", "type": "array", "items": {"type": "string"}, "collectionFormat": "multi"},
+								{"name": "digest", "in": "query", "type": "array", "items": {"type": "string"}},
+								{"name": "size", "in": "query", "type": "array", "items": {"type": "integer"}, "collectionFormat": "pipes"}
+							]
+						}
+					}
+				}
+			}`))
+			return
+		}
+		query = r.URL.RawQuery
+		w.Write([]byte(`{"tags": []}`))
+	}))
+	defer mockServer.Close()
+
+	client := NewQuayClient(mockServer.URL, "")
+	if err := client.FetchSwaggerSpec(); err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	client.DiscoverEndpoints()
+
+	tools := client.GenerateTools()
+	if len(tools) != 1 {
+		t.Fatalf("Expected 1 tool, got %d", len(tools))
+	}
+	for name, itemType := range map[string]string{"tag": "string", "digest": "string", "size": "integer"} {
+		property, _ := tools[0].InputSchema.Properties[name].(map[string]any)
+		items, _ := property["items"].(map[string]any)
+		if property["type"] != "array" || items["type"] != itemType {
+			t.Errorf("Expected %s to be an array of %s, got %v", name, itemType, property)
+		}
+	}
+
+	endpoint := client.GetEndpoints()["quay://api/v1/repository/{repository}/tag/"]
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want url.Values
+	}{
+		{"multi repeats the key", map[string]interface{}{"tag": []interface{}{"latest", "v1"}}, url.Values{"tag": {"latest", "v1"}}},
+		{"csv is the default", map[string]interface{}{"digest": []string{"sha256:a", "sha256:b"}}, url.Values{"digest": {"sha256:a,sha256:b"}}},
+		{"pipes joins typed items", map[string]interface{}{"size": []interface{}{float64(1), float64(2)}}, url.Values{"size": {"1|2"}}},
+		{"empty arrays are dropped", map[string]interface{}{"tag": []interface{}{}}, url.Values{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args["repository"] = "redhat/ubi8"
+			if _, err := client.MakeAPICallWithParams(endpoint, tt.args); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			got, err := url.ParseQuery(query)
+			if err != nil {
+				t.Fatalf("Failed to parse query %q: %v", query, err)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("Expected query %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
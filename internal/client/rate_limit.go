@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRetryAfter is the longest Retry-After a call waits for; a status asking for a longer wait
+// is returned instead of blocking the tool call
+const maxRetryAfter = time.Minute
+
+// SetRateLimit limits requests to Quay, including retries and discovery, to perSecond requests
+// per second across all tool calls, with bursts of up to one second's worth (0 disables the limit)
+func (c *QuayClient) SetRateLimit(perSecond float64) {
+	if perSecond <= 0 {
+		c.rateLimiter = nil
+		return
+	}
+	c.rateLimiter = newRateLimiter(perSecond)
+}
+
+// waitForRateLimit blocks until the rate limit allows another request or ctx is done
+func (c *QuayClient) waitForRateLimit(ctx context.Context) error {
+	if c.rateLimiter == nil {
+		return nil
+	}
+	return c.rateLimiter.wait(ctx)
+}
+
+// rateLimiter is a token bucket refilled at rate tokens per second up to burst tokens. Waiting
+// callers reserve a token up front, driving the balance negative, so they are served in order.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64) *rateLimiter {
+	burst := math.Max(1, math.Floor(rate))
+	return &rateLimiter{rate: rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// wait takes a token, sleeping until it is available; the token is returned if ctx ends first
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// retryAfter returns the wait a response's Retry-After header asks for, given in seconds or as
+// an HTTP date, or 0 when the header is missing, invalid or in the past
+func retryAfter(header http.Header, now time.Time) time.Duration {
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		// Cap absurd values at a day so the duration can't overflow
+		return time.Duration(min(seconds, 24*60*60)) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"-1", 0},
+		{"Mon, 01 Jan 2024 12:00:30 GMT", 30 * time.Second},
+		{"Mon, 01 Jan 2024 11:59:00 GMT", 0},
+		{"soon", 0},
+	} {
+		header := http.Header{}
+		if tc.value != "" {
+			header.Set("Retry-After", tc.value)
+		}
+		if got := retryAfter(header, now); got != tc.want {
+			t.Errorf("Expected %s for Retry-After %q, got %s", tc.want, tc.value, got)
+		}
+	}
+}
+
+func TestTooManyRequestsRetryAfter(t *testing.T) {
+	var requests int
+	var wait string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", wait)
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"tags": []}`))
+	}))
+	defer mockServer.Close()
+
+	endpoint := &types.EndpointInfo{Method: "GET", Path: "/tags"}
+	client := NewQuayClient(mockServer.URL, "")
+	client.retryBackoff = time.Millisecond
+
+	// The retry waits for Retry-After rather than the backoff
+	wait = "1"
+	start := time.Now()
+	if _, err := client.MakeAPICallWithParams(endpoint, nil); err != nil {
+		t.Fatalf("Expected the retry to succeed, got %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("Expected the retry to wait for Retry-After, took %s", elapsed)
+	}
+
+	// A wait beyond the maximum returns the 429 without retrying
+	requests = 0
+	wait = "3600"
+	_, err := client.MakeAPICallWithParams(endpoint, nil)
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected the 429 error, got %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	limiter := newRateLimiter(2)
+
+	// The burst is served immediately and the next request waits for a token
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.wait(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("Expected the third request to wait for a token, took %s", elapsed)
+	}
+
+	// A cancelled wait gives its token back
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := limiter.wait(ctx); err == nil {
+		t.Error("Expected the cancelled context's error")
+	}
+	limiter.mu.Lock()
+	tokens := limiter.tokens
+	limiter.mu.Unlock()
+	if tokens < -0.5 {
+		t.Errorf("Expected the reserved token to be returned, got a balance of %f", tokens)
+	}
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// repositoryListFilters are the arguments Quay requires at least one of when listing repositories
+var repositoryListFilters = []string{"namespace", "public", "starred"}
+
+// MissingFilterError is returned when a call leaves out every filter an endpoint requires at least
+// one of, so no request is made that Quay would reject with a terse 400
+type MissingFilterError struct {
+	Method string
+	Path   string
+	Names  []string
+	Hint   string
+}
+
+func (e *MissingFilterError) Error() string {
+	return fmt.Sprintf("%s %s requires at least one of %s; %s", e.Method, e.Path, strings.Join(e.Names, ", "), e.Hint)
+}
+
+// isRepositoryList reports whether an endpoint lists repositories, with or without the API base
+// path, and declares the filters; a spec without them leaves callers no way to pass one
+func isRepositoryList(endpoint *types.EndpointInfo) bool {
+	if endpoint.Method != http.MethodGet {
+		return false
+	}
+	if path := strings.TrimSuffix(endpoint.Path, "/"); path != "/api/v1/repository" && path != "/repository" {
+		return false
+	}
+	declared := queryParamNames(endpoint)
+	for _, name := range repositoryListFilters {
+		if declared[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRequiredFilters rejects a repository listing without a namespace, public=true or
+// starred=true, mirroring Quay's own check
+func checkRequiredFilters(endpoint *types.EndpointInfo, params map[string]interface{}) error {
+	if !isRepositoryList(endpoint) {
+		return nil
+	}
+	if namespace, ok := formatParamValue(params["namespace"]); ok && namespace != "" {
+		return nil
+	}
+	for _, name := range []string{"public", "starred"} {
+		if value, ok := formatParamValue(params[name]); ok {
+			if enabled, err := strconv.ParseBool(value); err == nil && enabled {
+				return nil
+			}
+		}
+	}
+	return &MissingFilterError{
+		Method: endpoint.Method,
+		Path:   endpoint.Path,
+		Names:  repositoryListFilters,
+		Hint:   "pass namespace to list an organization's or user's repositories, public=true for all public repositories or starred=true for your starred ones",
+	}
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gzipMagic are the leading bytes of a gzip stream
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// SetSpecFile makes FetchSwaggerSpec load the spec from a local file, file:// URL or http(s)://
+// URL instead of the registry's discovery endpoint
+func (c *QuayClient) SetSpecFile(path string) {
+	c.specFile = path
+}
+
+// LoadSpecFromFile loads the Swagger specification from a location: a local path, a file:// URL
+// or an http(s):// URL serving the spec directly, such as an export hosted outside the registry
+func (c *QuayClient) LoadSpecFromFile(location string) error {
+	parsed, err := url.Parse(location)
+	if err != nil || parsed.Scheme == "" || len(parsed.Scheme) == 1 {
+		// A plain path, including Windows drive letters parsed as one-letter schemes
+		return c.LoadSwaggerSpecFromFile(location)
+	}
+
+	switch parsed.Scheme {
+	case "file":
+		return c.LoadSwaggerSpecFromFile(parsed.Path)
+	case "http", "https":
+		return c.loadSwaggerSpecFromURL(location, parsed.Path)
+	}
+	return fmt.Errorf("unsupported spec location scheme %q (expected a path, file://, http:// or https://)", parsed.Scheme)
+}
+
+// loadSwaggerSpecFromURL fetches a spec document from a URL, decompressing it like a spec file
+func (c *QuayClient) loadSwaggerSpecFromURL(specURL, path string) error {
+	slog.Debug("Loading Swagger spec from URL", "url", specURL)
+
+	resp, err := c.getWithRetry(specURL)
+	if err != nil {
+		return fmt.Errorf("failed to fetch swagger spec: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch swagger spec: status code %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read swagger spec: %w", err)
+	}
+
+	body, err := decompressSpec(path, data)
+	if err != nil {
+		return fmt.Errorf("failed to decompress swagger spec from %s: %w", specURL, err)
+	}
+	slog.Debug("Read Swagger spec", "url", specURL, "bytes", len(data), "decoded_bytes", len(body))
+
+	return c.loadSwaggerSpec(body)
+}
+
+// LoadSwaggerSpecFromFile loads the Swagger specification from a local JSON or YAML file. Gzip
+// files are detected by their magic bytes or a .gz extension and deflate (zlib) files by a
+// .deflate or .zz extension, and are decompressed before parsing.
+func (c *QuayClient) LoadSwaggerSpecFromFile(path string) error {
+	slog.Debug("Loading Swagger spec from file", "file", path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read swagger spec file: %w", err)
+	}
+
+	body, err := decompressSpec(path, data)
+	if err != nil {
+		return fmt.Errorf("failed to decompress swagger spec file %s: %w", path, err)
+	}
+	slog.Debug("Read Swagger spec", "file", path, "bytes", len(data), "decoded_bytes", len(body))
+
+	return c.loadSwaggerSpec(body)
+}
+
+// decompressSpec returns the decoded contents of a spec file, leaving uncompressed files as they are
+func decompressSpec(path string, data []byte) ([]byte, error) {
+	var reader io.ReadCloser
+	var err error
+
+	switch ext := strings.ToLower(filepath.Ext(path)); {
+	case bytes.HasPrefix(data, gzipMagic) || ext == ".gz":
+		reader, err = gzip.NewReader(bytes.NewReader(data))
+	case ext == ".deflate" || ext == ".zz":
+		reader, err = zlib.NewReader(bytes.NewReader(data))
+	default:
+		return data, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
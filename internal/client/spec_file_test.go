@@ -0,0 +1,115 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const specFileContent = `{
+	"swagger": "2.0",
+	"basePath": "/api/v1",
+	"paths": {
+		"/api/v1/repository": {
+			"get": {"operationId": "listRepos", "tags": ["repository"]}
+		},
+		"/api/v1/organization/{orgname}": {
+			"get": {"operationId": "getOrganization", "tags": ["organization"]}
+		}
+	}
+}`
+
+// toolNamesFromSpecFile loads a spec file into a new client and returns the generated tool names
+func toolNamesFromSpecFile(t *testing.T, path string) []string {
+	t.Helper()
+
+	client := NewQuayClient("https://quay.example", "")
+	client.SetSpecFile(path)
+	if err := client.FetchSwaggerSpec(); err != nil {
+		t.Fatalf("Failed to load %s: %v", path, err)
+	}
+	client.DiscoverEndpoints()
+
+	var names []string
+	for _, tool := range client.GenerateTools() {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func TestLoadCompressedSpecFile(t *testing.T) {
+	dir := t.TempDir()
+
+	plain := filepath.Join(dir, "swagger.json")
+	if err := os.WriteFile(plain, []byte(specFileContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	gz.Write([]byte(specFileContent))
+	gz.Close()
+
+	var deflated bytes.Buffer
+	zw := zlib.NewWriter(&deflated)
+	zw.Write([]byte(specFileContent))
+	zw.Close()
+
+	files := map[string][]byte{
+		"swagger.json.gz":   gzipped.Bytes(),
+		"swagger-gzip.json": gzipped.Bytes(), // detected by magic bytes alone
+		"swagger.json.zz":   deflated.Bytes(),
+	}
+
+	want := toolNamesFromSpecFile(t, plain)
+	if len(want) != 2 {
+		t.Fatalf("Expected 2 tools from the plain spec, got %v", want)
+	}
+
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if got := toolNamesFromSpecFile(t, path); !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected %s to parse like the plain spec %v, got %v", name, want, got)
+		}
+	}
+}
+
+func TestLoadSpecFromLocation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "swagger.json")
+	if err := os.WriteFile(path, []byte(specFileContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	want := toolNamesFromSpecFile(t, path)
+
+	if got := toolNamesFromSpecFile(t, "file://"+path); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the file:// URL to load like the path %v, got %v", want, got)
+	}
+
+	var requested string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		w.Write([]byte(specFileContent))
+	}))
+	defer mockServer.Close()
+
+	if got := toolNamesFromSpecFile(t, mockServer.URL+"/exports/quay-swagger.json"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the http:// URL to load like the path %v, got %v", want, got)
+	}
+	if requested != "/exports/quay-swagger.json" {
+		t.Errorf("Expected the spec URL to be fetched as is, got %s", requested)
+	}
+
+	client := NewQuayClient("https://quay.example", "")
+	if err := client.LoadSpecFromFile("ftp://specs.example/swagger.json"); err == nil {
+		t.Error("Expected an error for an unsupported scheme")
+	}
+}
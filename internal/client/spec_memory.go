@@ -0,0 +1,24 @@
+package client
+
+import (
+	"github.com/pb33f/libopenapi"
+)
+
+// releaseSpecCopies drops the copies of the raw spec libopenapi keeps after parsing: the original
+// bytes, the same bytes as JSON and a generic map decoded from them. The model is built from the
+// parsed YAML node tree, so only that tree stays in memory for the life of the process. The
+// document can no longer be rendered or compared with another afterwards, which the client never does.
+//
+// libopenapi needs the whole document to build its node tree and index, so parsing itself can't be
+// made incremental; this only lowers what a large spec keeps in memory once loaded.
+func releaseSpecCopies(document libopenapi.Document) {
+	info := document.GetSpecInfo()
+	if info == nil {
+		return
+	}
+	// Rolodex line counting dereferences SpecBytes, so leave it pointing at an empty slice
+	empty := []byte{}
+	info.SpecBytes = &empty
+	info.SpecJSONBytes = nil
+	info.SpecJSON = nil
+}
@@ -0,0 +1,131 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+)
+
+// largeSpec builds a Swagger document with paths operations, each reading a repository and
+// writing one through a body that references a shared definition
+func largeSpec(paths int) []byte {
+	var b strings.Builder
+	b.WriteString(`{"swagger": "2.0", "info": {"title": "Quay", "version": "v1"}, "paths": {`)
+	for i := 0; i < paths; i++ {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, `"/api/v1/repository%d/{repository}": {
+			"get": {
+				"operationId": "getRepo%d",
+				"summary": "Get repository %d with a description long enough to look like Quay's own",
+				"tags": ["repository"],
+				"parameters": [
+					{"name": "repository", "in": "path", "type": "string", "required": true},
+					{"name": "includeTags", "in": "query", "type": "boolean", "description": "Whether to include tags"}
+				]
+			},
+			"put": {
+				"operationId": "updateRepo%d",
+				"summary": "Update repository %d",
+				"tags": ["repository"],
+				"parameters": [
+					{"name": "repository", "in": "path", "type": "string", "required": true},
+					{"name": "body", "in": "body", "required": true, "schema": {"$ref": "#/definitions/RepoUpdate"}}
+				]
+			}
+		}`, i, i, i, i, i)
+	}
+	b.WriteString(`}, "definitions": {"RepoUpdate": {"type": "object", "required": ["description"],
+		"properties": {"description": {"type": "string", "description": "Markdown description"}}}}}`)
+	return []byte(b.String())
+}
+
+// TestReleaseSpecCopies checks the tradeoff of dropping the raw spec after parsing: the copies are
+// gone, yet endpoints, tools and lazily resolved body schemas still work from the node tree.
+func TestReleaseSpecCopies(t *testing.T) {
+	client := NewQuayClient("https://quay.example.com", "")
+	client.SetMethods([]string{"GET", "PUT"})
+	if err := client.loadSwaggerSpec(largeSpec(3)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	info := client.GetDocument().GetSpecInfo()
+	if len(*info.SpecBytes) != 0 || info.SpecJSONBytes != nil || info.SpecJSON != nil {
+		t.Error("Expected the raw spec copies to be released")
+	}
+
+	client.DiscoverEndpoints()
+	if got := len(client.GetEndpoints()); got != 6 {
+		t.Errorf("Expected 6 endpoints, got %d", got)
+	}
+	tools := client.GenerateTools()
+	if len(tools) != 6 {
+		t.Fatalf("Expected 6 tools, got %d", len(tools))
+	}
+	for _, tool := range tools {
+		if tool.Name == "quay_updateRepo1" {
+			if _, ok := tool.InputSchema.Properties["description"]; !ok {
+				t.Errorf("Expected the referenced body schema to resolve, got %v", tool.InputSchema.Properties)
+			}
+		}
+	}
+}
+
+// retainedHeap returns the live heap after a full collection
+func retainedHeap() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+// BenchmarkLoadLargeSpec compares the memory a large spec keeps once loaded, with libopenapi's
+// raw copies kept (as before) and released. The peak during parsing is the same either way, since
+// libopenapi parses the whole document at once, so allocations per op barely differ; the
+// retained-bytes metric is what drops, by the raw bytes and the map decoded from them (about 7MB
+// of 78MB for the 2000 paths here), as the node tree and models dominate what stays.
+func BenchmarkLoadLargeSpec(b *testing.B) {
+	spec := largeSpec(2000)
+
+	b.Run("keep-raw-spec", func(b *testing.B) {
+		b.ReportAllocs()
+		var retained uint64
+		for i := 0; i < b.N; i++ {
+			before := retainedHeap()
+			document, err := libopenapi.NewDocument(spec)
+			if err != nil {
+				b.Fatal(err)
+			}
+			model, _ := document.BuildV2Model()
+			retained += retainedHeap() - before
+			runtime.KeepAlive(model)
+			runtime.KeepAlive(document)
+		}
+		b.ReportMetric(float64(retained)/float64(b.N), "retained-B/op")
+	})
+
+	b.Run("release-raw-spec", func(b *testing.B) {
+		previous := slog.Default()
+		slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		defer slog.SetDefault(previous)
+
+		b.ReportAllocs()
+		var retained uint64
+		for i := 0; i < b.N; i++ {
+			before := retainedHeap()
+			client := NewQuayClient("https://quay.example.com", "")
+			if err := client.loadSwaggerSpec(spec); err != nil {
+				b.Fatal(err)
+			}
+			retained += retainedHeap() - before
+			runtime.KeepAlive(client)
+		}
+		b.ReportMetric(float64(retained)/float64(b.N), "retained-B/op")
+	})
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadSpecTransform reads a JSON merge patch (RFC 7386) from a JSON or YAML file. The patch is
+// applied to the raw spec before it is parsed, so broken specs (a wrong basePath, missing tags)
+// can be fixed without a proxy. A null value in the patch deletes the key it names.
+func (c *QuayClient) LoadSpecTransform(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read spec transform: %w", err)
+	}
+
+	patch, err := decodeSpecDocument(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse spec transform %s: %w", path, err)
+	}
+	if _, ok := patch.(map[string]interface{}); !ok {
+		return fmt.Errorf("spec transform %s must be an object", path)
+	}
+
+	c.specTransform = patch
+	return nil
+}
+
+// applySpecTransform merges the configured patch into the raw spec, returning it as JSON.
+// Without a patch the spec is returned unchanged.
+func (c *QuayClient) applySpecTransform(body []byte) ([]byte, error) {
+	if c.specTransform == nil {
+		return body, nil
+	}
+
+	spec, err := decodeSpecDocument(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode swagger spec for transform: %w", err)
+	}
+
+	patched, err := json.Marshal(mergePatch(spec, c.specTransform))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transformed swagger spec: %w", err)
+	}
+	slog.Info("Applied spec transform", "bytes_before", len(body), "bytes_after", len(patched))
+	return patched, nil
+}
+
+// decodeSpecDocument decodes a JSON or YAML document into JSON-compatible values
+func decodeSpecDocument(data []byte) (interface{}, error) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err == nil {
+		return doc, nil
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return jsonCompatible(doc), nil
+}
+
+// jsonCompatible converts the map[interface{}]interface{} values YAML produces for non-string
+// keys (such as response codes) into string-keyed maps
+func jsonCompatible(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = jsonCompatible(item)
+		}
+		return converted
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = jsonCompatible(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = jsonCompatible(item)
+		}
+		return v
+	}
+	return value
+}
+
+// mergePatch applies an RFC 7386 JSON merge patch to target: objects merge recursively, null
+// removes a key and any other value replaces the target
+func mergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = make(map[string]interface{})
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
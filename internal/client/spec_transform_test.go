@@ -0,0 +1,70 @@
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+func TestSpecTransform(t *testing.T) {
+	dir := t.TempDir()
+	specFile := filepath.Join(dir, "swagger.json")
+	if err := os.WriteFile(specFile, []byte(specFileContent), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// Serve the API under a proxy prefix and retag an operation
+	transformFile := filepath.Join(dir, "transform.yaml")
+	transform := "basePath: /quay\npaths:\n  /api/v1/organization/{orgname}:\n    get:\n      tags: [repository]\n"
+	if err := os.WriteFile(transformFile, []byte(transform), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewQuayClient("https://quay.example", "")
+	client.SetSpecFile(specFile)
+	if err := client.LoadSpecTransform(transformFile); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := client.FetchSwaggerSpec(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	client.DiscoverEndpoints()
+
+	apiURL, err := client.BuildAPIURLWithParams(&types.EndpointInfo{Method: "GET", Path: "/api/v1/repository"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if apiURL != "https://quay.example/quay/api/v1/repository" {
+		t.Errorf("Expected the patched basePath in the URL, got %s", apiURL)
+	}
+
+	endpoint := client.GetEndpoints()["quay://api/v1/organization/{orgname}"]
+	if endpoint == nil || !reflect.DeepEqual(endpoint.Tags, []string{"repository"}) {
+		t.Errorf("Expected the patched tags, got %+v", endpoint)
+	}
+	if endpoint != nil && endpoint.OperationID != "getOrganization" {
+		t.Errorf("Expected the rest of the operation to be kept, got %q", endpoint.OperationID)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	target := map[string]interface{}{
+		"basePath": "/api/v1",
+		"info":     map[string]interface{}{"title": "Quay", "version": "v1"},
+		"host":     "quay.io",
+	}
+	patch := map[string]interface{}{
+		"info": map[string]interface{}{"version": "v1.1"},
+		"host": nil,
+	}
+
+	want := map[string]interface{}{
+		"basePath": "/api/v1",
+		"info":     map[string]interface{}{"title": "Quay", "version": "v1.1"},
+	}
+	if got := mergePatch(target, patch); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
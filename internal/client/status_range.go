@@ -0,0 +1,43 @@
+package client
+
+import "fmt"
+
+// StatusRange is an inclusive range of HTTP response statuses
+type StatusRange struct {
+	Min int
+	Max int
+}
+
+// DefaultSuccessStatuses are the response statuses treated as success unless configured otherwise
+var DefaultSuccessStatuses = []StatusRange{{Min: 200, Max: 299}}
+
+// Contains reports whether status falls within the range
+func (r StatusRange) Contains(status int) bool {
+	return status >= r.Min && status <= r.Max
+}
+
+func (r StatusRange) String() string {
+	if r.Min == r.Max {
+		return fmt.Sprintf("%d", r.Min)
+	}
+	return fmt.Sprintf("%d-%d", r.Min, r.Max)
+}
+
+// SetSuccessStatuses sets the response statuses that count as success; any other status is
+// returned as an *APIError. An empty list restores the 200-299 default.
+func (c *QuayClient) SetSuccessStatuses(ranges []StatusRange) {
+	if len(ranges) == 0 {
+		ranges = DefaultSuccessStatuses
+	}
+	c.successStatuses = ranges
+}
+
+// isSuccess reports whether a response status counts as success
+func (c *QuayClient) isSuccess(status int) bool {
+	for _, r := range c.successStatuses {
+		if r.Contains(status) {
+			return true
+		}
+	}
+	return false
+}
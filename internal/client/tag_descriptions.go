@@ -0,0 +1,45 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadTagDescriptions reads a JSON file mapping tags to descriptions that are appended to the
+// descriptions of tools with that tag, e.g. {"robot": "Robot accounts are non-human credentials."}
+func (c *QuayClient) LoadTagDescriptions(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read tag description map: %v", err)
+	}
+
+	var descriptions map[string]string
+	if err := json.Unmarshal(data, &descriptions); err != nil {
+		return fmt.Errorf("invalid tag description map %s: %v", path, err)
+	}
+	c.SetTagDescriptions(descriptions)
+	return nil
+}
+
+// SetTagDescriptions sets the descriptions appended to tools of each tag
+func (c *QuayClient) SetTagDescriptions(descriptions map[string]string) {
+	c.tagDescriptions = make(map[string]string, len(descriptions))
+	for tag, description := range descriptions {
+		if description = strings.TrimSpace(description); description != "" {
+			c.tagDescriptions[tag] = description
+		}
+	}
+}
+
+// tagDescriptionText returns the configured descriptions for the tags, one line each, in tag order
+func (c *QuayClient) tagDescriptionText(tags []string) string {
+	var text string
+	for _, tag := range tags {
+		if description, ok := c.tagDescriptions[tag]; ok {
+			text += fmt.Sprintf("\n%s: %s", tag, description)
+		}
+	}
+	return text
+}
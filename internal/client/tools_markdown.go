@@ -0,0 +1,56 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// markdownEscaper backslash-escapes the characters that would end a table cell or start inline
+// formatting in a description
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "|", `\|`, "#", `\#`,
+)
+
+// WriteToolsMarkdown writes the generated tools as a Markdown table with their method, path,
+// required parameters and description, sorted by name, for pasting into documentation
+func (c *QuayClient) WriteToolsMarkdown(w io.Writer) error {
+	if c.GetModel() == nil {
+		return fmt.Errorf("no swagger spec loaded")
+	}
+
+	endpoints := make(map[string]*types.EndpointInfo)
+	for _, endpoint := range c.GetEndpoints() {
+		endpoints[c.ToolName(endpoint.Method, endpoint.OperationID, endpoint.Path, endpoint.Tags)] = endpoint
+	}
+
+	tools := c.GenerateTools()
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+
+	fmt.Fprintln(w, "| Tool | Method | Path | Required parameters | Description |")
+	fmt.Fprintln(w, "| --- | --- | --- | --- | --- |")
+	for _, tool := range tools {
+		method, path := "", ""
+		if endpoint, ok := endpoints[tool.Name]; ok {
+			method, path = endpoint.Method, "`"+endpoint.Path+"`"
+		}
+
+		required := make([]string, len(tool.InputSchema.Required))
+		for i, name := range tool.InputSchema.Required {
+			required[i] = "`" + name + "`"
+		}
+
+		// The first line is the operation's summary; the rest repeats the endpoint and tags
+		description, _, _ := strings.Cut(tool.Description, "\n")
+
+		if _, err := fmt.Fprintf(w, "| `%s` | %s | %s | %s | %s |\n", tool.Name, method, path,
+			strings.Join(required, ", "), markdownEscaper.Replace(strings.TrimSpace(description))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package client
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteToolsMarkdown(t *testing.T) {
+	client := newTestClient(t, `{
+		"swagger": "2.0",
+		"paths": {
+			"/api/v1/repository": {
+				"get": {"operationId": "listRepos", "summary": "List repositories | *starred* or <public>", "tags": ["repository"]}
+			},
+			"/api/v1/repository/{repository}/tag/{tag}": {
+				"get": {
+					"operationId": "getTag",
+					"summary": "Get a tag_name",
+					"tags": ["tag"],
+					"parameters": [
+						{"name": "repository", "in": "path", "type": "string", "required": true},
+						{"name": "tag", "in": "path", "type": "string", "required": true}
+					]
+				}
+			}
+		}
+	}`)
+
+	var out bytes.Buffer
+	if err := client.WriteToolsMarkdown(&out); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := "| Tool | Method | Path | Required parameters | Description |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| `quay_getTag` | GET | `/api/v1/repository/{repository}/tag/{tag}` | `repository`, `tag` | Get a tag\\_name |\n" +
+		"| `quay_listRepos` | GET | `/api/v1/repository` |  | List repositories \\| \\*starred\\* or \\<public\\> |\n"
+	if out.String() != want {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+)
+
+// SetTLSConfig trusts the certificates in the PEM bundle caCertFile in addition to the system
+// roots (empty keeps the system roots only) and optionally skips certificate verification
+// entirely, for registries behind an internal CA or self-signed development instances. Proxies
+// from HTTP_PROXY, HTTPS_PROXY and NO_PROXY are honored either way.
+func (c *QuayClient) SetTLSConfig(caCertFile string, insecureSkipVerify bool) error {
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecureSkipVerify}
+
+	if caCertFile != "" {
+		pem, err := os.ReadFile(caCertFile)
+		if err != nil {
+			return fmt.Errorf("failed to read CA certificate bundle: %w", err)
+		}
+
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			slog.Warn("System certificate pool unavailable, trusting only the CA bundle", "ca_cert", caCertFile, "error", err)
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no PEM certificates found in %s", caCertFile)
+		}
+		tlsConfig.RootCAs = roots
+	}
+	if insecureSkipVerify {
+		slog.Warn("TLS certificate verification is disabled")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	transport.TLSClientConfig = tlsConfig
+
+	client := *c.httpClient
+	client.Transport = transport
+	c.httpClient = &client
+
+	c.caCertFile = caCertFile
+	c.insecureSkipVerify = insecureSkipVerify
+	return nil
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+func TestTLSConfig(t *testing.T) {
+	mockServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer mockServer.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: mockServer.Certificate().Raw})
+	if err := os.WriteFile(caFile, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	endpoint := &types.EndpointInfo{Method: "GET", Path: "/api/v1/user/"}
+	newClient := func() *QuayClient {
+		client := NewQuayClient(mockServer.URL, "")
+		client.SetConnectionRetries(0)
+		return client
+	}
+
+	// The test server's certificate isn't trusted by default
+	if _, err := newClient().MakeAPICallWithParams(endpoint, nil); err == nil {
+		t.Error("Expected an untrusted certificate error")
+	}
+
+	trusting := newClient()
+	if err := trusting.SetTLSConfig(caFile, false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := trusting.MakeAPICallWithParams(endpoint, nil); err != nil {
+		t.Errorf("Expected the CA bundle to be trusted, got %v", err)
+	}
+
+	insecure := newClient()
+	if err := insecure.SetTLSConfig("", true); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := insecure.MakeAPICallWithParams(endpoint, nil); err != nil {
+		t.Errorf("Expected verification to be skipped, got %v", err)
+	}
+
+	notPEM := filepath.Join(dir, "not.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := newClient().SetTLSConfig(notPEM, false); err == nil {
+		t.Error("Expected an error for a bundle without certificates")
+	}
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// paramTypeAliases maps the type names accepted in a hints file to JSON schema types
+var paramTypeAliases = map[string]string{
+	"string":  "string",
+	"int":     "integer",
+	"integer": "integer",
+	"number":  "number",
+	"bool":    "boolean",
+	"boolean": "boolean",
+	"array":   "array",
+}
+
+// LoadParamTypeHints reads a JSON file mapping parameter names to types (string, int, number,
+// bool or array) that override the string default for parameters the spec leaves untyped
+func (c *QuayClient) LoadParamTypeHints(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read type hints file: %v", err)
+	}
+
+	var hints map[string]string
+	if err := json.Unmarshal(data, &hints); err != nil {
+		return fmt.Errorf("invalid type hints file %s: %v", path, err)
+	}
+	return c.SetParamTypeHints(hints)
+}
+
+// SetParamTypeHints sets the parameter types used when generating tool input schemas
+func (c *QuayClient) SetParamTypeHints(hints map[string]string) error {
+	resolved := make(map[string]string, len(hints))
+	for name, hint := range hints {
+		schemaType, ok := paramTypeAliases[strings.ToLower(hint)]
+		if !ok {
+			return fmt.Errorf("unsupported type %q for parameter %s", hint, name)
+		}
+		resolved[name] = schemaType
+	}
+	c.paramTypeHints = resolved
+	return nil
+}
+
+// paramOption returns the tool input for a parameter, typed by its hint and a string otherwise
+func (c *QuayClient) paramOption(name string, opts ...mcp.PropertyOption) mcp.ToolOption {
+	return c.typedParamOption(name, "", opts...)
+}
+
+// typedParamOption declares a parameter of the given JSON schema type, falling back to the type
+// hints and then to string when the type is empty
+func (c *QuayClient) typedParamOption(name, paramType string, opts ...mcp.PropertyOption) mcp.ToolOption {
+	if paramType == "" {
+		paramType = c.paramTypeHints[name]
+	}
+
+	switch paramType {
+	case "integer":
+		return mcp.WithNumber(name, append(opts, func(schema map[string]any) {
+			schema["type"] = "integer"
+		})...)
+	case "number":
+		return mcp.WithNumber(name, opts...)
+	case "boolean":
+		return mcp.WithBoolean(name, opts...)
+	case "array":
+		// Items are strings unless an option already declared them
+		return mcp.WithArray(name, append(opts, func(schema map[string]any) {
+			if _, ok := schema["items"]; !ok {
+				schema["items"] = map[string]any{"type": "string"}
+			}
+		})...)
+	case "object":
+		return mcp.WithObject(name, opts...)
+	default:
+		return mcp.WithString(name, opts...)
+	}
+}
+
+// formatParamValue converts a tool argument into its URL form, so typed arguments are sent the
+// same way as their string equivalents. Arrays are joined with commas (Swagger's csv format).
+func formatParamValue(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, v != ""
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(v), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case json.Number:
+		return v.String(), true
+	case []interface{}, []string:
+		items, _ := formatArrayItems(v)
+		return strings.Join(items, ","), len(items) > 0
+	}
+	return "", false
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// dryRunParameter is the query parameter a write operation declares when Quay can validate the
+// request without applying it
+const dryRunParameter = "dry_run"
+
+// SetValidateWrites makes every write call send a validation-only probe first and abandon the
+// write when the probe shows it would fail
+func (c *QuayClient) SetValidateWrites(validate bool) {
+	c.validateWrites = validate
+}
+
+// probeWrite checks what a write call would do before it is sent. Operations declaring dry_run
+// are sent once with dry_run=true; PUT, PATCH and DELETE on a path that also has a GET operation
+// first read the resource, so a write to a missing resource is never attempted. Other writes
+// have no probe and are sent as-is.
+func (c *QuayClient) probeWrite(ctx context.Context, endpoint *types.EndpointInfo, params map[string]interface{}) error {
+	var probe *types.EndpointInfo
+	probeParams := make(map[string]interface{}, len(params)+1)
+
+	switch {
+	case queryParamNames(endpoint)[dryRunParameter]:
+		probe = endpoint
+		for name, value := range params {
+			probeParams[name] = value
+		}
+		probeParams[dryRunParameter] = true
+	case endpoint.Method != http.MethodPost && c.hasGetOperation(endpoint.Path):
+		probe = &types.EndpointInfo{Method: http.MethodGet, Path: endpoint.Path}
+		for _, name := range extractPathParameterNames(endpoint.Path) {
+			if value, ok := params[name]; ok {
+				probeParams[name] = value
+			}
+		}
+	default:
+		slog.Debug("No validation probe available for write", "method", endpoint.Method, "path", endpoint.Path)
+		return nil
+	}
+
+	apiURL, err := c.BuildAPIURLWithParams(probe, probeParams)
+	if err != nil {
+		return fmt.Errorf("failed to build validation probe URL: %v", err)
+	}
+	var body []byte
+	if hasRequestBody(probe) {
+		if body, err = json.Marshal(requestBody(probe, probeParams)); err != nil {
+			return fmt.Errorf("failed to encode validation probe body: %v", err)
+		}
+	}
+	req, err := c.newEndpointRequest(ctx, probe, apiURL, body)
+	if err != nil {
+		return fmt.Errorf("failed to create validation probe: %v", err)
+	}
+
+	slog.Info("Probing write before sending it", "method", endpoint.Method, "path", endpoint.Path, "probe", probe.Method+" "+req.URL.String())
+	if _, err := c.executeRequest(req); err != nil {
+		return fmt.Errorf("%s %s was not sent: its validation probe %s %s failed: %w", endpoint.Method, endpoint.Path, probe.Method, probe.Path, err)
+	}
+	return nil
+}
+
+// hasGetOperation reports whether the spec, or the endpoint cache it was loaded from, has a GET
+// operation for a path
+func (c *QuayClient) hasGetOperation(path string) bool {
+	if _, ok := c.endpoints[endpointKey(http.MethodGet, path)]; ok {
+		return true
+	}
+	if c.model == nil || !hasPaths(c.model) {
+		return false
+	}
+	pathItem := c.model.Model.Paths.PathItems.GetOrZero(path)
+	return pathItem != nil && pathItem.Get != nil
+}
@@ -0,0 +1,47 @@
+// Package logging builds the leveled logger shared by the server and the Quay client
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Levels lists the accepted -log-level names, most verbose first
+var Levels = []string{"debug", "info", "warn", "error"}
+
+// Formats lists the accepted -log-format names
+var Formats = []string{"text", "json"}
+
+// ParseLevel converts a level name such as "debug" or "warn" to a slog level
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (expected one of %s)", name, strings.Join(Levels, ", "))
+}
+
+// New returns a logger writing records at or above level to w, as logfmt-style text or as JSON
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	minLevel, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &slog.HandlerOptions{Level: minLevel}
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "text", "":
+		return slog.New(slog.NewTextHandler(w, options)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, options)), nil
+	}
+	return nil, fmt.Errorf("unknown log format %q (expected one of %s)", format, strings.Join(Formats, ", "))
+}
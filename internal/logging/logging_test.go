@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "warn", "json")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	logger.Info("quiet")
+	logger.Warn("slow call", "path", "/tags")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected only the warning to be logged, got %q", buf.String())
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("Expected a JSON record, got %q: %v", lines[0], err)
+	}
+	if record["level"] != "WARN" || record["msg"] != "slow call" || record["path"] != "/tags" {
+		t.Errorf("Unexpected record %v", record)
+	}
+
+	buf.Reset()
+	logger, _ = New(&buf, "DEBUG", "text")
+	logger.Debug("request", "method", "GET")
+	if !strings.Contains(buf.String(), "level=DEBUG msg=request method=GET") {
+		t.Errorf("Expected a debug text record, got %q", buf.String())
+	}
+
+	if _, err := New(&buf, "verbose", "text"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+	if _, err := New(&buf, "info", "xml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,127 @@
+// Package retry runs operations with a bounded number of attempts and a backoff between them.
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Clock waits for backoff delays; tests inject a fake one to avoid sleeping
+type Clock interface {
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock waits on the system clock
+type realClock struct{}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Strategy returns the delay before retry number attempt (starting at 0)
+type Strategy func(attempt int) time.Duration
+
+// Constant waits the same delay before every retry
+func Constant(delay time.Duration) Strategy {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+// Exponential doubles the delay for each retry, starting at base and capped at max (0 means no cap)
+func Exponential(base, max time.Duration) Strategy {
+	return func(attempt int) time.Duration {
+		delay := base
+		for i := 0; i < attempt; i++ {
+			delay *= 2
+			if max > 0 && delay >= max {
+				return max
+			}
+		}
+		if max > 0 && delay > max {
+			return max
+		}
+		return delay
+	}
+}
+
+// Policy describes how an operation is retried
+type Policy struct {
+	// Attempts is the total number of attempts, including the first (values below 1 mean 1)
+	Attempts int
+	// Backoff returns the delay before each retry (nil retries immediately)
+	Backoff Strategy
+	// Jitter randomizes each delay by up to this fraction in either direction (0 disables)
+	Jitter float64
+	// Retryable reports whether an error is worth retrying (nil retries every error)
+	Retryable func(error) bool
+	// RetryAfter returns a delay requested by a failed attempt, such as a server's Retry-After,
+	// which replaces the backoff when positive and is not jittered (nil always uses the backoff)
+	RetryAfter func(error) time.Duration
+	// OnRetry is called before waiting for each retry
+	OnRetry func(attempt int, delay time.Duration, err error)
+	// Clock waits for delays (nil uses the system clock)
+	Clock Clock
+	// Rand returns a number in [0, 1) for jitter (nil uses math/rand)
+	Rand func() float64
+}
+
+// Do runs fn until it succeeds, returns an error that isn't retryable or runs out of attempts,
+// returning its last error. Waiting for a retry stops when ctx is done, returning ctx's error.
+func (p Policy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	attempts := p.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if attempt == attempts-1 || (p.Retryable != nil && !p.Retryable(err)) {
+			return err
+		}
+
+		delay := p.delay(attempt)
+		if p.RetryAfter != nil {
+			if requested := p.RetryAfter(err); requested > 0 {
+				delay = requested
+			}
+		}
+		if p.OnRetry != nil {
+			p.OnRetry(attempt, delay, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.clock().After(delay):
+		}
+	}
+	return err
+}
+
+// delay returns the jittered backoff before retry number attempt
+func (p Policy) delay(attempt int) time.Duration {
+	if p.Backoff == nil {
+		return 0
+	}
+	delay := p.Backoff(attempt)
+	if p.Jitter > 0 && delay > 0 {
+		random := rand.Float64
+		if p.Rand != nil {
+			random = p.Rand
+		}
+		delay += time.Duration(float64(delay) * p.Jitter * (2*random() - 1))
+	}
+	return delay
+}
+
+func (p Policy) clock() Clock {
+	if p.Clock == nil {
+		return realClock{}
+	}
+	return p.Clock
+}
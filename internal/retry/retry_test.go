@@ -0,0 +1,196 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock records requested delays and fires immediately
+type fakeClock struct {
+	delays []time.Duration
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.delays = append(c.delays, d)
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
+
+var errTransient = errors.New("transient")
+
+// failing returns an operation that fails failures times before succeeding, counting its calls
+func failing(failures int, calls *int) func(context.Context) error {
+	return func(context.Context) error {
+		*calls++
+		if *calls <= failures {
+			return errTransient
+		}
+		return nil
+	}
+}
+
+func TestDoAttemptCounts(t *testing.T) {
+	tests := []struct {
+		name      string
+		attempts  int
+		failures  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{"succeeds first time", 3, 0, 1, false},
+		{"succeeds on last attempt", 3, 2, 3, false},
+		{"runs out of attempts", 3, 5, 3, true},
+		{"zero attempts means one", 0, 5, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			clock := &fakeClock{}
+			err := Policy{Attempts: tt.attempts, Backoff: Constant(time.Second), Clock: clock}.Do(context.Background(), failing(tt.failures, &calls))
+
+			if calls != tt.wantCalls {
+				t.Errorf("Expected %d calls, got %d", tt.wantCalls, calls)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if len(clock.delays) != tt.wantCalls-1 {
+				t.Errorf("Expected %d waits, got %d", tt.wantCalls-1, len(clock.delays))
+			}
+		})
+	}
+}
+
+func TestDoExponentialTiming(t *testing.T) {
+	calls := 0
+	clock := &fakeClock{}
+	var retried []int
+	policy := Policy{
+		Attempts: 5,
+		Backoff:  Exponential(100*time.Millisecond, 500*time.Millisecond),
+		Clock:    clock,
+		OnRetry: func(attempt int, delay time.Duration, err error) {
+			retried = append(retried, attempt)
+		},
+	}
+	if err := policy.Do(context.Background(), failing(10, &calls)); !errors.Is(err, errTransient) {
+		t.Fatalf("Expected the last error, got %v", err)
+	}
+
+	want := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 500 * time.Millisecond}
+	if len(clock.delays) != len(want) {
+		t.Fatalf("Expected delays %v, got %v", want, clock.delays)
+	}
+	for i := range want {
+		if clock.delays[i] != want[i] {
+			t.Errorf("Expected delay %d to be %s, got %s", i, want[i], clock.delays[i])
+		}
+	}
+	if len(retried) != 4 || retried[0] != 0 || retried[3] != 3 {
+		t.Errorf("Expected OnRetry for attempts 0-3, got %v", retried)
+	}
+}
+
+func TestDoJitter(t *testing.T) {
+	for _, tt := range []struct {
+		random float64
+		want   time.Duration
+	}{
+		{0, 50 * time.Millisecond},
+		{0.5, 100 * time.Millisecond},
+		{0.75, 125 * time.Millisecond},
+	} {
+		calls := 0
+		clock := &fakeClock{}
+		policy := Policy{
+			Attempts: 2,
+			Backoff:  Constant(100 * time.Millisecond),
+			Jitter:   0.5,
+			Clock:    clock,
+			Rand:     func() float64 { return tt.random },
+		}
+		policy.Do(context.Background(), failing(1, &calls))
+
+		if len(clock.delays) != 1 || clock.delays[0] != tt.want {
+			t.Errorf("Expected a %s delay for random %v, got %v", tt.want, tt.random, clock.delays)
+		}
+	}
+}
+
+func TestDoRetryablePredicate(t *testing.T) {
+	permanent := errors.New("permanent")
+	calls := 0
+	policy := Policy{
+		Attempts:  5,
+		Clock:     &fakeClock{},
+		Retryable: func(err error) bool { return errors.Is(err, errTransient) },
+	}
+	err := policy.Do(context.Background(), func(context.Context) error {
+		calls++
+		if calls == 1 {
+			return errTransient
+		}
+		return permanent
+	})
+
+	if !errors.Is(err, permanent) {
+		t.Errorf("Expected the permanent error, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected to stop after the non-retryable error, got %d calls", calls)
+	}
+}
+
+func TestDoRetryAfter(t *testing.T) {
+	calls := 0
+	clock := &fakeClock{}
+	policy := Policy{
+		Attempts: 3,
+		Backoff:  Constant(time.Second),
+		Jitter:   0.5,
+		Rand:     func() float64 { return 0 },
+		Clock:    clock,
+		RetryAfter: func(error) time.Duration {
+			// Only the first failure asks for a delay
+			if calls == 1 {
+				return 5 * time.Second
+			}
+			return 0
+		},
+	}
+	if err := policy.Do(context.Background(), failing(2, &calls)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The requested delay is used as is; the second retry falls back to the jittered backoff
+	want := []time.Duration{5 * time.Second, 500 * time.Millisecond}
+	if len(clock.delays) != len(want) || clock.delays[0] != want[0] || clock.delays[1] != want[1] {
+		t.Errorf("Expected delays %v, got %v", want, clock.delays)
+	}
+}
+
+func TestDoContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	policy := Policy{
+		Attempts: 5,
+		Backoff:  Constant(time.Hour),
+		OnRetry:  func(int, time.Duration, error) { cancel() },
+	}
+
+	start := time.Now()
+	err := policy.Do(ctx, failing(10, &calls))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected no attempts after cancellation, got %d calls", calls)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("Expected cancellation to interrupt the wait, took %s", time.Since(start))
+	}
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// batchTool is the meta-tool that runs several tool calls in one request
+const batchTool = "quay_batch"
+
+// maxBatchConcurrency bounds how many calls of a batch run against Quay at the same time
+const maxBatchConcurrency = 4
+
+// batchItemResult is the outcome of one call in a batch
+type batchItemResult struct {
+	Tool    string `json:"tool"`
+	IsError bool   `json:"is_error"`
+	Result  string `json:"result"`
+}
+
+// newBatchTool describes the batch meta-tool, with an example tool name carrying the tool prefix
+func newBatchTool(toolPrefix string) mcp.Tool {
+	return mcp.NewTool(batchTool,
+		mcp.WithDescription("Runs several Quay tools in one request and returns their results in order. A failing call doesn't fail the batch; its error is reported in its own result."),
+		mcp.WithArray("calls",
+			mcp.Required(),
+			mcp.Description("Tool calls to run, each an object with the tool name and its params"),
+			mcp.Items(map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"tool":   map[string]any{"type": "string", "description": fmt.Sprintf("Name of the tool to run (e.g. %sgetRepo)", toolPrefix)},
+					"params": map[string]any{"type": "object", "description": "Arguments passed to the tool"},
+				},
+				"required": []string{"tool"},
+			}),
+		),
+	)
+}
+
+// createBatchHandler creates the handler for the batch meta-tool. Each call goes through the same
+// handler as a direct tool call, so namespace checks and validation apply per item.
+func (s *QuayMCPServer) createBatchHandler() func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	toolHandler := s.createToolHandler()
+
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls, ok := request.GetArguments()["calls"].([]interface{})
+		if !ok || len(calls) == 0 {
+			return mcp.NewToolResultError("calls must be a non-empty array of {tool, params} objects"), nil
+		}
+
+		slog.Info("Running a batch of tool calls", "calls", len(calls))
+
+		results := make([]batchItemResult, len(calls))
+		semaphore := make(chan struct{}, maxBatchConcurrency)
+		var wg sync.WaitGroup
+
+		for i, item := range calls {
+			call, _ := item.(map[string]interface{})
+			toolName, _ := call["tool"].(string)
+			params, _ := call["params"].(map[string]interface{})
+			results[i].Tool = toolName
+
+			if _, exists := s.tools[toolName]; !exists {
+				results[i].IsError = true
+				results[i].Result = fmt.Sprintf("unknown tool: %q", toolName)
+				continue
+			}
+
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				semaphore <- struct{}{}
+				defer func() { <-semaphore }()
+
+				itemRequest := mcp.CallToolRequest{}
+				itemRequest.Params.Name = toolName
+				itemRequest.Params.Arguments = params
+
+				result, err := toolHandler(ctx, itemRequest)
+				if err != nil {
+					results[i].IsError = true
+					results[i].Result = err.Error()
+					return
+				}
+				results[i].IsError = result.IsError
+				results[i].Result = ResultText(result)
+			}(i)
+		}
+		wg.Wait()
+
+		encoded, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to encode batch results: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(encoded)), nil
+	}
+}
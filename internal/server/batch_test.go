@@ -0,0 +1,59 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestBatchWithFailure(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error_message": "Not Found"}`))
+			return
+		}
+		w.Write([]byte(`{"repositories": [{"name": "ubi8"}]}`))
+	})
+	s.registerTools(s.quayClient.GenerateTools())
+
+	result := callTool(t, s.createBatchHandler(), batchTool, map[string]interface{}{
+		"calls": []interface{}{
+			map[string]interface{}{"tool": "quay_listRepos", "params": map[string]interface{}{"namespace": "redhat"}},
+			map[string]interface{}{"tool": "quay_getRepo", "params": map[string]interface{}{"repository": "missing"}},
+		},
+	})
+	if result.IsError {
+		t.Fatalf("Expected the batch itself to succeed, got %+v", result.Content)
+	}
+
+	text, _ := mcp.AsTextContent(result.Content[0])
+	var items []batchItemResult
+	if err := json.Unmarshal([]byte(text.Text), &items); err != nil {
+		t.Fatalf("Expected a JSON array of results, got %q: %v", text.Text, err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(items))
+	}
+
+	if items[0].Tool != "quay_listRepos" || items[0].IsError || !strings.Contains(items[0].Result, "ubi8") {
+		t.Errorf("Expected the first call to succeed, got %+v", items[0])
+	}
+	if items[1].Tool != "quay_getRepo" || !strings.Contains(items[1].Result, "404") {
+		t.Errorf("Expected the second call to report the 404, got %+v", items[1])
+	}
+}
+
+func TestBatchToolExamplePrefix(t *testing.T) {
+	encoded, err := json.Marshal(newBatchTool("prod_").InputSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(encoded), "e.g. prod_getRepo") || strings.Contains(string(encoded), "quay_") {
+		t.Errorf("Expected the example tool name to carry the prod_ prefix, got %s", encoded)
+	}
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// authTokenArgument is the meta-parameter carrying an OAuth token for a single call
+const authTokenArgument = "auth_token"
+
+// SetAllowPerCallToken lets tool calls pass an auth_token argument that replaces the configured
+// OAuth token for that call only, so one server can act on behalf of several users
+func (s *QuayMCPServer) SetAllowPerCallToken(enabled bool) {
+	s.allowPerCallToken = enabled
+}
+
+// withAuthTokenArgument adds the optional auth_token argument to a generated tool
+func withAuthTokenArgument(tool mcp.Tool) mcp.Tool {
+	properties := make(map[string]any, len(tool.InputSchema.Properties)+1)
+	for name, property := range tool.InputSchema.Properties {
+		properties[name] = property
+	}
+	properties[authTokenArgument] = map[string]any{
+		"type":        "string",
+		"description": "OAuth token to use for this call instead of the server's configured token",
+	}
+	tool.InputSchema.Properties = properties
+	return tool
+}
+
+// takeCallToken returns the arguments without auth_token, and the token it carried. The caller's
+// map is left untouched. Passing a token fails unless per-call tokens are allowed, rather than
+// silently calling Quay with the server's own token.
+func (s *QuayMCPServer) takeCallToken(arguments map[string]any) (map[string]any, string, error) {
+	value, ok := arguments[authTokenArgument]
+	if !ok {
+		return arguments, "", nil
+	}
+	if !s.allowPerCallToken {
+		return nil, "", fmt.Errorf("%s is not accepted: per-call tokens are disabled on this server", authTokenArgument)
+	}
+	token, ok := value.(string)
+	if !ok {
+		return nil, "", fmt.Errorf("%s must be a string", authTokenArgument)
+	}
+
+	rest := make(map[string]any, len(arguments)-1)
+	for name, value := range arguments {
+		if name != authTokenArgument {
+			rest[name] = value
+		}
+	}
+	return rest, token, nil
+}
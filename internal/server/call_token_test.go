@@ -0,0 +1,59 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPerCallToken(t *testing.T) {
+	var authorizations []string
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"repositories": []}`))
+	})
+	s.quayClient = s.quayClient.ForRegistry(s.quayClient.GetRegistryURL(), "static-token")
+	logs := captureLogs(t)
+
+	// Disabled, the argument is rejected instead of being ignored or sent to Quay
+	handler := s.createToolHandler()
+	result := callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "redhat", "auth_token": "per-call-token"})
+	if !result.IsError {
+		t.Fatalf("Expected auth_token to be rejected while disabled, got %s", resultText(t, result))
+	}
+	if len(authorizations) != 0 {
+		t.Fatalf("Expected no request to Quay, got %d", len(authorizations))
+	}
+
+	s.SetAllowPerCallToken(true)
+	s.registerTools(s.quayClient.GenerateTools())
+	if _, ok := s.tools["quay_listRepos"].InputSchema.Properties["auth_token"]; !ok {
+		t.Error("Expected the generated tools to accept auth_token")
+	}
+
+	args := map[string]interface{}{"namespace": "redhat", "auth_token": "per-call-token"}
+	if result := callTool(t, handler, "quay_listRepos", args); result.IsError {
+		t.Fatalf("Expected the call to succeed, got %s", resultText(t, result))
+	}
+	if _, ok := args["auth_token"]; !ok {
+		t.Error("Expected the caller's arguments to be left untouched")
+	}
+	if result := callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "redhat"}); result.IsError {
+		t.Fatalf("Expected the call to succeed, got %s", resultText(t, result))
+	}
+
+	want := []string{"Bearer per-call-token", "Bearer static-token"}
+	if len(authorizations) != len(want) {
+		t.Fatalf("Expected %d requests, got %d", len(want), len(authorizations))
+	}
+	for i := range want {
+		if authorizations[i] != want[i] {
+			t.Errorf("Expected request %d to send %q, got %q", i, want[i], authorizations[i])
+		}
+	}
+
+	if strings.Contains(logs.String(), "per-call-token") {
+		t.Errorf("Expected the per-call token to stay out of the logs, got:\n%s", logs.String())
+	}
+}
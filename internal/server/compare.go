@@ -0,0 +1,156 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/quay/quay-mcp-server/internal/client"
+)
+
+// compareTool is the meta-tool that runs a tool against both the primary and mirror registries
+const compareTool = "quay_compare"
+
+// difference is a single value that differs between the primary and mirror responses
+type difference struct {
+	Path    string      `json:"path"`
+	Primary interface{} `json:"primary,omitempty"`
+	Mirror  interface{} `json:"mirror,omitempty"`
+}
+
+// comparison is the result returned by the compare meta-tool
+type comparison struct {
+	Tool        string       `json:"tool"`
+	Primary     string       `json:"primary"`
+	Mirror      string       `json:"mirror"`
+	Identical   bool         `json:"identical"`
+	Differences []difference `json:"differences"`
+}
+
+// SetMirror configures a second registry that the compare meta-tool checks against the primary one
+func (s *QuayMCPServer) SetMirror(registryURL, oauthToken string) {
+	s.mirrorURL = registryURL
+	s.mirrorToken = oauthToken
+}
+
+// createCompareHandler creates the handler for the compare meta-tool
+func (s *QuayMCPServer) createCompareHandler() func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if s.mirrorURL == "" {
+			return mcp.NewToolResultError("No mirror registry is configured"), nil
+		}
+
+		toolName, err := request.RequireString("tool")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		params, _ := request.GetArguments()["params"].(map[string]interface{})
+
+		endpoint, err := s.findEndpoint(toolName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if err := s.checkNamespace(endpoint, params); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// The mirror shares the discovered spec, so the same endpoint applies to both registries
+		mirror := s.quayClient.ForRegistry(s.mirrorURL, s.mirrorToken)
+
+		slog.Info("Comparing registries", "tool", toolName, "primary", s.quayClient.GetRegistryURL(), "mirror", mirror.GetRegistryURL())
+
+		ctx = client.WithRequestID(ctx, requestIDFromMeta(request))
+		primaryData, err := s.quayClient.MakeAPICallWithParamsContext(ctx, endpoint, params)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Primary registry call failed: %s", err.Error())), nil
+		}
+		mirrorData, err := mirror.MakeAPICallWithParamsContext(ctx, endpoint, params)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Mirror registry call failed: %s", err.Error())), nil
+		}
+
+		differences, err := diffResponses(primaryData, mirrorData)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, err := json.MarshalIndent(comparison{
+			Tool:        toolName,
+			Primary:     s.quayClient.GetRegistryURL(),
+			Mirror:      mirror.GetRegistryURL(),
+			Identical:   len(differences) == 0,
+			Differences: differences,
+		}, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to encode comparison: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(result)), nil
+	}
+}
+
+// diffResponses parses two JSON responses and returns the values that differ between them
+func diffResponses(primary, mirror []byte) ([]difference, error) {
+	var a, b interface{}
+	if err := decodeJSON(primary, &a); err != nil {
+		return nil, fmt.Errorf("primary response is not JSON: %w", err)
+	}
+	if err := decodeJSON(mirror, &b); err != nil {
+		return nil, fmt.Errorf("mirror response is not JSON: %w", err)
+	}
+
+	differences := []difference{}
+	diffValues("$", a, b, &differences)
+	return differences, nil
+}
+
+// diffValues recursively compares two decoded JSON values, recording differences by JSON path
+func diffValues(path string, a, b interface{}, differences *[]difference) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make(map[string]bool)
+		for k := range av {
+			keys[k] = true
+		}
+		for k := range bv {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			diffValues(path+"."+k, av[k], bv[k], differences)
+		}
+		return
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(av) || i < len(bv); i++ {
+			var ai, bi interface{}
+			if i < len(av) {
+				ai = av[i]
+			}
+			if i < len(bv) {
+				bi = bv[i]
+			}
+			diffValues(fmt.Sprintf("%s[%d]", path, i), ai, bi, differences)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*differences = append(*differences, difference{Path: path, Primary: a, Mirror: b})
+	}
+}
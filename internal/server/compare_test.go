@@ -0,0 +1,100 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCompareTool(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name": "ubi8", "is_public": true, "tags": ["latest", "8.9"]}`))
+	})
+
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/discovery" {
+			t.Errorf("Unexpected mirror request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name": "ubi8", "is_public": false, "tags": ["latest"]}`))
+	}))
+	defer mirror.Close()
+
+	s.SetMirror(mirror.URL, "mirror-token")
+
+	result := callTool(t, s.createCompareHandler(), compareTool, map[string]interface{}{
+		"tool":   "quay_getRepo",
+		"params": map[string]interface{}{"repository": "redhat/ubi8"},
+	})
+	if result.IsError {
+		t.Fatalf("Expected success, got %s", resultText(t, result))
+	}
+
+	var got comparison
+	if err := json.Unmarshal([]byte(resultText(t, result)), &got); err != nil {
+		t.Fatalf("Expected JSON comparison, got %v", err)
+	}
+	if got.Identical {
+		t.Error("Expected responses to differ")
+	}
+
+	paths := make(map[string]bool)
+	for _, d := range got.Differences {
+		paths[d.Path] = true
+	}
+	if len(got.Differences) != 2 || !paths["$.is_public"] || !paths["$.tags[1]"] {
+		t.Errorf("Expected differences at $.is_public and $.tags[1], got %+v", got.Differences)
+	}
+}
+
+func TestDiffResponsesIdentical(t *testing.T) {
+	differences, err := diffResponses([]byte(`{"a": [1, {"b": 2}]}`), []byte(`{"a": [1, {"b": 2}]}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(differences) != 0 {
+		t.Errorf("Expected no differences, got %+v", differences)
+	}
+}
+
+// orgSpec has an endpoint taking the organization as an orgname path parameter
+const orgSpec = `{
+	"swagger": "2.0",
+	"paths": {
+		"/api/v1/organization/{orgname}": {
+			"get": {
+				"operationId": "getOrganization",
+				"tags": ["organization"],
+				"parameters": [
+					{"name": "orgname", "in": "path", "type": "string", "required": true}
+				]
+			}
+		}
+	}
+}`
+
+func TestCompareToolMappedNamespace(t *testing.T) {
+	var requests int
+	s := newTestServer(t, orgSpec, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{}`))
+	})
+	if err := s.quayClient.SetParamMapping(map[string]string{"orgname": "org"}); err != nil {
+		t.Fatalf("Expected a valid mapping, got %v", err)
+	}
+	s.SetAllowedNamespaces([]string{"redhat"})
+	s.SetMirror(s.quayClient.GetRegistryURL(), "")
+
+	result := callTool(t, s.createCompareHandler(), compareTool, map[string]interface{}{
+		"tool":   "quay_getOrganization",
+		"params": map[string]interface{}{"org": "acme"},
+	})
+	if !result.IsError {
+		t.Errorf("Expected a disallowed namespace under its mapped name to be rejected, got %s", resultText(t, result))
+	}
+	if requests != 0 {
+		t.Errorf("Expected no API requests for the rejected comparison, got %d", requests)
+	}
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// responseKind is how a response body is represented in a tool result
+type responseKind int
+
+const (
+	responseJSON   responseKind = iota // structured JSON, subject to the result transforms
+	responseText                       // other textual content, returned as text
+	responseBinary                     // anything else, returned base64-encoded
+)
+
+// textMediaTypes are non-text/* media types whose bodies are readable text
+var textMediaTypes = map[string]bool{
+	"application/xml":        true,
+	"application/yaml":       true,
+	"application/x-yaml":     true,
+	"application/javascript": true,
+}
+
+// classifyResponse decides how to represent a response from its Content-Type. JSON is also
+// recognized when mislabelled as text/plain, and the body is sniffed when the type is missing.
+func classifyResponse(contentType string, body []byte) responseKind {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "" {
+		switch {
+		case json.Valid(body):
+			return responseJSON
+		case utf8.Valid(body):
+			return responseText
+		}
+		return responseBinary
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return responseJSON
+	case mediaType == "text/plain" && json.Valid(body):
+		return responseJSON
+	case strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+xml") || textMediaTypes[mediaType]:
+		return responseText
+	}
+	return responseBinary
+}
+
+// newBinaryResult returns a binary response base64-encoded, as image content for images and as an
+// embedded blob resource otherwise
+func (s *QuayMCPServer) newBinaryResult(endpoint *types.EndpointInfo, arguments map[string]interface{}, contentType string, body []byte) *mcp.CallToolResult {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+	encoded := base64.StdEncoding.EncodeToString(body)
+	summary := fmt.Sprintf("%s %s returned %d bytes of %s", endpoint.Method, endpoint.Path, len(body), mediaType)
+
+	if strings.HasPrefix(mediaType, "image/") {
+		return mcp.NewToolResultImage(summary, encoded, mediaType)
+	}
+
+	resourceURI, err := s.quayClient.BuildAPIURLWithParams(endpoint, arguments)
+	if err != nil {
+		resourceURI = "quay://" + strings.TrimPrefix(endpoint.Path, "/")
+	}
+	return mcp.NewToolResultResource(summary, mcp.BlobResourceContents{
+		URI:      resourceURI,
+		MIMEType: mediaType,
+		Blob:     encoded,
+	})
+}
+
+// ResultText joins the text and embedded text resources of a tool result
+func ResultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			parts = append(parts, text.Text)
+			continue
+		}
+		if resource, ok := mcp.AsEmbeddedResource(content); ok {
+			if text, ok := mcp.AsTextResourceContents(resource.Resource); ok {
+				parts = append(parts, text.Text)
+			}
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
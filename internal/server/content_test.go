@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestClassifyResponse(t *testing.T) {
+	tests := []struct {
+		contentType string
+		body        string
+		want        responseKind
+	}{
+		{"application/json", `{"name": "ubi8"}`, responseJSON},
+		{"application/vnd.docker.distribution.manifest.v2+json", `{"schemaVersion": 2}`, responseJSON},
+		{"text/plain; charset=utf-8", `{"name": "ubi8"}`, responseJSON},
+		{"text/plain; charset=utf-8", "FROM ubi8\nRUN make", responseText},
+		{"application/x-yaml", "name: ubi8", responseText},
+		{"application/octet-stream", "\x1f\x8b\x08\x00", responseBinary},
+		{"", `[1, 2]`, responseJSON},
+		{"", "plain words", responseText},
+		{"", "\xff\xfe\x00", responseBinary},
+	}
+
+	for _, test := range tests {
+		if got := classifyResponse(test.contentType, []byte(test.body)); got != test.want {
+			t.Errorf("classifyResponse(%q, %q) = %d, want %d", test.contentType, test.body, got, test.want)
+		}
+	}
+}
+
+func TestContentTypeAwareResults(t *testing.T) {
+	binary := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff}
+	responses := map[string]struct {
+		contentType string
+		body        []byte
+	}{
+		"json":   {"application/json", []byte(`{"repositories": []}`)},
+		"text":   {"text/plain", []byte("Dockerfile contents")},
+		"binary": {"application/octet-stream", binary},
+		"image":  {"image/png", binary},
+	}
+
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		response := responses[r.URL.Query().Get("namespace")]
+		w.Header().Set("Content-Type", response.contentType)
+		w.Write(response.body)
+	})
+	handler := s.createToolHandler()
+
+	result := callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "json"})
+	if text, ok := mcp.AsTextContent(result.Content[0]); !ok || text.Text != `{"repositories": []}` {
+		t.Errorf("Expected the JSON as text, got %+v", result.Content)
+	}
+
+	result = callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "text"})
+	if text, ok := mcp.AsTextContent(result.Content[0]); !ok || text.Text != "Dockerfile contents" {
+		t.Errorf("Expected the text body, got %+v", result.Content)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(binary)
+
+	result = callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "binary"})
+	resource, ok := mcp.AsEmbeddedResource(result.Content[len(result.Content)-1])
+	if !ok {
+		t.Fatalf("Expected an embedded resource, got %+v", result.Content)
+	}
+	blob, ok := resource.Resource.(mcp.BlobResourceContents)
+	if !ok || blob.Blob != encoded || blob.MIMEType != "application/octet-stream" {
+		t.Errorf("Expected a base64 blob resource, got %+v", resource.Resource)
+	}
+
+	result = callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "image"})
+	image, ok := mcp.AsImageContent(result.Content[len(result.Content)-1])
+	if !ok || image.Data != encoded || image.MIMEType != "image/png" {
+		t.Errorf("Expected base64 image content, got %+v", result.Content)
+	}
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// continuationArgument is the meta-parameter that asks for the next chunk of a split response
+const continuationArgument = "continuation"
+
+// DefaultContinuationTTL is how long the rest of a split response is kept for continuation calls
+const DefaultContinuationTTL = 10 * time.Minute
+
+// continuation is the unread remainder of a split response
+type continuation struct {
+	tool    string
+	data    []byte
+	offset  int
+	expires time.Time
+}
+
+// continuationStore keeps split responses until they are read to the end or expire
+type continuationStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*continuation
+}
+
+// newContinuationStore creates a store whose entries expire ttl after their last read
+func newContinuationStore(ttl time.Duration) *continuationStore {
+	return &continuationStore{ttl: ttl, entries: make(map[string]*continuation)}
+}
+
+// SetChunkSize splits responses larger than size bytes into chunks read with the continuation
+// argument, keeping the remainder for ttl between reads (a size of 0 disables chunking)
+func (s *QuayMCPServer) SetChunkSize(size int, ttl time.Duration) {
+	s.chunkSize = size
+	s.continuations = newContinuationStore(ttl)
+}
+
+// withContinuationArgument adds the optional continuation argument to a generated tool
+func withContinuationArgument(tool mcp.Tool) mcp.Tool {
+	properties := make(map[string]any, len(tool.InputSchema.Properties)+1)
+	for name, property := range tool.InputSchema.Properties {
+		properties[name] = property
+	}
+	properties[continuationArgument] = map[string]any{
+		"type":        "string",
+		"description": "Token from a truncated result; pass it alone to read the next chunk of that response",
+	}
+	tool.InputSchema.Properties = properties
+	return tool
+}
+
+// chunkedResult returns the first chunk of a large response and keeps the rest for continuation calls
+func (s *QuayMCPServer) chunkedResult(toolName string, data []byte) *mcp.CallToolResult {
+	token, err := newContinuationToken()
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to create continuation token: %v", err))
+	}
+	entry := &continuation{tool: toolName, data: data}
+	s.continuations.put(token, entry)
+	return s.nextChunk(token, entry)
+}
+
+// continueResult returns the next chunk of a response split by an earlier call
+func (s *QuayMCPServer) continueResult(toolName, token string) *mcp.CallToolResult {
+	entry, ok := s.continuations.get(token)
+	if !ok || entry.tool != toolName {
+		return mcp.NewToolResultError(fmt.Sprintf("Unknown or expired continuation token %q; call %s again without it", token, toolName))
+	}
+	return s.nextChunk(token, entry)
+}
+
+// nextChunk reads the next chunk of an entry, ending on a UTF-8 boundary, and drops the entry
+// once it has been read to the end
+func (s *QuayMCPServer) nextChunk(token string, entry *continuation) *mcp.CallToolResult {
+	s.continuations.mu.Lock()
+	defer s.continuations.mu.Unlock()
+
+	start := entry.offset
+	end := start + s.chunkSize
+	if end >= len(entry.data) {
+		delete(s.continuations.entries, token)
+		return mcp.NewToolResultText(string(entry.data[start:]))
+	}
+	for end > start+1 && !utf8.RuneStart(entry.data[end]) {
+		end--
+	}
+	entry.offset = end
+	entry.expires = time.Now().Add(s.continuations.ttl)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			mcp.NewTextContent(string(entry.data[start:end])),
+			mcp.NewTextContent(fmt.Sprintf("[Truncated: bytes %d-%d of %d. Call %s with %s=%q for the next chunk.]",
+				start, end, len(entry.data), entry.tool, continuationArgument, token)),
+		},
+	}
+}
+
+// put stores an entry, dropping any that have expired
+func (c *continuationStore) put(token string, entry *continuation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for key, existing := range c.entries {
+		if now.After(existing.expires) {
+			delete(c.entries, key)
+		}
+	}
+	entry.expires = now.Add(c.ttl)
+	c.entries[token] = entry
+}
+
+// get returns an unexpired entry
+func (c *continuationStore) get(token string) (*continuation, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[token]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, token)
+		return nil, false
+	}
+	return entry, true
+}
+
+// newContinuationToken returns an opaque random token
+func newContinuationToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestChunkedResultContinuation(t *testing.T) {
+	body := `{"repositories": [{"name": "ubi8"}, {"name": "ubi9"}]}`
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	})
+	s.SetChunkSize(32, time.Minute)
+	handler := s.createToolHandler()
+
+	first := callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "redhat"})
+	if len(first.Content) != 2 {
+		t.Fatalf("Expected a chunk and a continuation note, got %+v", first)
+	}
+	chunk, _ := mcp.AsTextContent(first.Content[0])
+	note, _ := mcp.AsTextContent(first.Content[1])
+	match := regexp.MustCompile(`continuation="([0-9a-f]+)"`).FindStringSubmatch(note.Text)
+	if match == nil {
+		t.Fatalf("Expected a continuation token in %q", note.Text)
+	}
+
+	second := callTool(t, handler, "quay_listRepos", map[string]interface{}{"continuation": match[1]})
+	if len(second.Content) != 1 {
+		t.Fatalf("Expected the final chunk without a note, got %+v", second)
+	}
+	rest, _ := mcp.AsTextContent(second.Content[0])
+	if chunk.Text+rest.Text != body {
+		t.Errorf("Expected the chunks to rebuild the response, got %q + %q", chunk.Text, rest.Text)
+	}
+
+	again := callTool(t, handler, "quay_listRepos", map[string]interface{}{"continuation": match[1]})
+	text, _ := mcp.AsTextContent(again.Content[0])
+	if !again.IsError || !strings.Contains(text.Text, "Unknown or expired") {
+		t.Errorf("Expected a read-out token to be rejected, got %+v", again)
+	}
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// SetCallTimeout bounds each tool call, including retries and the response hook (0 disables)
+func (s *QuayMCPServer) SetCallTimeout(timeout time.Duration) {
+	s.callTimeout = timeout
+}
+
+// SetDeadlinePropagation controls whether a deadline set by the MCP client on the call context
+// bounds the whole operation alongside the server's own call timeout (the default)
+func (s *QuayMCPServer) SetDeadlinePropagation(enabled bool) {
+	s.deadlinePropagation = enabled
+}
+
+// callContext derives the context a call to endpoint runs under. Without deadline propagation
+// the client's deadline and cancellation are dropped and only the server's timeout for the
+// endpoint applies; with it, whichever of the two expires first ends the call.
+func (s *QuayMCPServer) callContext(ctx context.Context, endpoint *types.EndpointInfo) (context.Context, context.CancelFunc) {
+	if !s.deadlinePropagation {
+		ctx = context.WithoutCancel(ctx)
+	}
+	if timeout := s.endpointTimeout(endpoint); timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+func TestDeadlinePropagation(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(300 * time.Millisecond):
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"repositories": []}`))
+	})
+	s.SetCallTimeout(5 * time.Second)
+	handler := s.createToolHandler()
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "quay_listRepos"
+	request.Params.Arguments = map[string]interface{}{"namespace": "redhat"}
+
+	call := func() (*mcp.CallToolResult, time.Duration) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		result, err := handler(ctx, request)
+		if err != nil {
+			t.Fatalf("Expected no handler error, got %v", err)
+		}
+		return result, time.Since(start)
+	}
+
+	// The client's deadline is shorter than the server's timeout, so it ends the call
+	result, elapsed := call()
+	if text := resultText(t, result); !strings.Contains(text, "deadline exceeded") {
+		t.Errorf("Expected the client deadline to fail the call, got %s", text)
+	}
+	if elapsed >= 300*time.Millisecond {
+		t.Errorf("Expected the call to stop at the client deadline, took %s", elapsed)
+	}
+
+	// Without propagation only the server's timeout applies, so the slow call completes
+	s.SetDeadlinePropagation(false)
+	result, _ = call()
+	if text := resultText(t, result); text != `{"repositories": []}` {
+		t.Errorf("Expected the call to outlive the client deadline, got %s", text)
+	}
+
+	// The server's timeout still bounds the call
+	s.SetCallTimeout(50 * time.Millisecond)
+	result, _ = call()
+	if text := resultText(t, result); !strings.Contains(text, "deadline exceeded") {
+		t.Errorf("Expected the call timeout to fail the call, got %s", text)
+	}
+}
+
+func TestEndpointTimeouts(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(200 * time.Millisecond):
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"repositories": []}`))
+	})
+	s.SetCallTimeout(5 * time.Second)
+	if err := s.SetEndpointTimeouts(map[string]time.Duration{"getRepo": 20 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+	handler := s.createToolHandler()
+
+	// The mapped endpoint times out at its own timeout
+	result := callTool(t, handler, "quay_getRepo", map[string]interface{}{"repository": "redhat/ubi8"})
+	if text := resultText(t, result); !strings.Contains(text, "deadline exceeded") {
+		t.Errorf("Expected the endpoint timeout to fail the call, got %s", text)
+	}
+
+	// An unmapped endpoint keeps the call timeout, which the slow call fits in
+	result = callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "redhat"})
+	if text := resultText(t, result); text != `{"repositories": []}` {
+		t.Errorf("Expected the call timeout to apply, got %s", text)
+	}
+}
+
+func TestEndpointTimeoutPatterns(t *testing.T) {
+	s := &QuayMCPServer{callTimeout: time.Minute}
+	if err := s.SetEndpointTimeouts(map[string]time.Duration{
+		"/api/v1/repository/*":     time.Second,
+		"/api/v1/repository/*/tag": 2 * time.Second,
+		"getTag":                   3 * time.Second,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		endpoint types.EndpointInfo
+		want     time.Duration
+	}{
+		{types.EndpointInfo{Path: "/api/v1/repository/{repository}"}, time.Second},
+		{types.EndpointInfo{Path: "/api/v1/repository/{repository}/tag"}, 2 * time.Second},
+		{types.EndpointInfo{Path: "/api/v1/repository/{repository}/tag", OperationID: "getTag"}, 3 * time.Second},
+		{types.EndpointInfo{Path: "/api/v1/user"}, time.Minute},
+	} {
+		if got := s.endpointTimeout(&tc.endpoint); got != tc.want {
+			t.Errorf("%+v: expected %s, got %s", tc.endpoint, tc.want, got)
+		}
+	}
+
+	if err := s.SetEndpointTimeouts(map[string]time.Duration{"/api/[": time.Second}); err == nil {
+		t.Error("Expected an invalid pattern to be rejected")
+	}
+}
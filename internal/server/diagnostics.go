@@ -0,0 +1,78 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/quay/quay-mcp-server/internal/client"
+)
+
+// startupDiagnostics is the JSON snapshot written after startup for automated monitoring
+type startupDiagnostics struct {
+	client.Diagnostics
+	Tools    int             `json:"tools"`
+	Features map[string]bool `json:"features"`
+	Warnings []string        `json:"warnings"`
+}
+
+// SetDiagnosticsOutput makes Start write a JSON diagnostics snapshot to w once the tools are
+// registered, before serving (nil disables it)
+func (s *QuayMCPServer) SetDiagnosticsOutput(w io.Writer) {
+	s.diagnosticsOutput = w
+}
+
+// warn logs a startup warning and records it for the diagnostics snapshot
+func (s *QuayMCPServer) warn(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	slog.Warn(message)
+	s.warnings = append(s.warnings, message)
+}
+
+// diagnostics returns the startup snapshot of the server and its client
+func (s *QuayMCPServer) diagnostics() startupDiagnostics {
+	d := startupDiagnostics{
+		Diagnostics: s.quayClient.Diagnostics(),
+		Tools:       len(s.handlers),
+		Features: map[string]bool{
+			"result_as_resource":   s.resultAsResource,
+			"flatten_response":     s.flattenResponse,
+			"response_max_depth":   s.responseMaxDepth > 0,
+			"pagination_cursor":    s.paginationCursor,
+			"auto_paginate":        s.autoPaginate > 1,
+			"normalize_errors":     s.normalizeErrors,
+			"reconnect_on_eof":     s.reconnectOnEOF,
+			"summarize":            s.summarize,
+			"response_hook":        s.responseHook != "",
+			"validate_args":        s.validateArgs,
+			"meta_tools":           s.metaTools,
+			"allowed_namespaces":   s.allowedNamespaces != nil,
+			"mirror":               s.mirrorURL != "",
+			"call_timeout":         s.callTimeout > 0,
+			"deadline_propagation": s.deadlinePropagation,
+			"endpoint_timeouts":    len(s.endpointTimeouts) > 0,
+			"fail_closed":          s.failClosed,
+			"smoke_test":           s.smokeTestTool != "",
+			"per_call_token":       s.allowPerCallToken,
+			"dry_run":              s.dryRun,
+			"unwrap_response":      s.unwrapInfer || len(s.unwrapKeys) > 0,
+			"response_summarizer":  s.summarizer != (NoopSummarizer{}),
+		},
+		Warnings: append([]string{}, s.warnings...),
+	}
+	if d.Endpoints == 0 {
+		d.Warnings = append(d.Warnings, "no endpoints were discovered")
+	}
+	return d
+}
+
+// WriteDiagnostics writes the startup snapshot to w as a single line of JSON
+func (s *QuayMCPServer) WriteDiagnostics(w io.Writer) error {
+	encoded, err := json.Marshal(s.diagnostics())
+	if err != nil {
+		return fmt.Errorf("failed to encode diagnostics: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "%s\n", encoded)
+	return err
+}
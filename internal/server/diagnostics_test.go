@@ -0,0 +1,58 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestStartupDiagnostics(t *testing.T) {
+	s := newTestServer(t, testSpec, nil)
+	s.SetToolCountThreshold(1)
+	s.SetNormalizeErrors(true)
+	if err := s.Initialize(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := s.WriteDiagnostics(&buf); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Count(buf.String(), "\n") != 1 {
+		t.Errorf("Expected a single line of JSON, got %q", buf.String())
+	}
+
+	var diagnostics map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &diagnostics); err != nil {
+		t.Fatalf("Expected JSON diagnostics, got %s", buf.String())
+	}
+
+	if diagnostics["registry_url"] != s.quayClient.GetRegistryURL() {
+		t.Errorf("Expected the registry URL, got %v", diagnostics["registry_url"])
+	}
+	if diagnostics["spec_source"] != "discovery" {
+		t.Errorf("Expected the discovery spec source, got %v", diagnostics["spec_source"])
+	}
+	if diagnostics["endpoints"] != float64(2) {
+		t.Errorf("Expected 2 endpoints, got %v", diagnostics["endpoints"])
+	}
+	// The two generated tools plus the response example, batch, last response headers, metrics and
+	// tool list meta-tools
+	if diagnostics["tools"] != float64(7) {
+		t.Errorf("Expected 7 tools, got %v", diagnostics["tools"])
+	}
+	for _, section := range []string{"retries", "cache", "transport", "features"} {
+		if _, ok := diagnostics[section].(map[string]interface{}); !ok {
+			t.Errorf("Expected a %s section, got %v", section, diagnostics[section])
+		}
+	}
+	if features := diagnostics["features"].(map[string]interface{}); features["normalize_errors"] != true || features["flatten_response"] != false {
+		t.Errorf("Expected the enabled features, got %v", features)
+	}
+
+	warnings, _ := diagnostics["warnings"].([]interface{})
+	if len(warnings) != 1 || !strings.Contains(warnings[0].(string), "more than the threshold of 1") {
+		t.Errorf("Expected the tool count warning, got %v", diagnostics["warnings"])
+	}
+}
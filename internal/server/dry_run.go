@@ -0,0 +1,31 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// SetDryRun makes tool calls return the request they would send (method, URL, path and query
+// parameters) as JSON instead of calling Quay, for debugging base paths and substitution
+func (s *QuayMCPServer) SetDryRun(enabled bool) {
+	s.dryRun = enabled
+}
+
+// dryRunResult describes the request a tool call would send
+func (s *QuayMCPServer) dryRunResult(toolName string, endpoint *types.EndpointInfo, arguments map[string]interface{}) *mcp.CallToolResult {
+	dryRun, err := s.quayClient.DryRunCall(endpoint, arguments)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Dry run of %s failed: %v", toolName, err))
+	}
+	encoded, err := json.MarshalIndent(dryRun, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("failed to encode dry run: %v", err))
+	}
+	slog.Info("Dry run, not calling Quay", "tool", toolName, "method", dryRun.Method, "url", dryRun.URL)
+	return mcp.NewToolResultText(string(encoded))
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/quay/quay-mcp-server/internal/client"
+)
+
+func TestDryRun(t *testing.T) {
+	var requests int
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{}`))
+	})
+	s.SetDryRun(true)
+	handler := s.createToolHandler()
+
+	result := callTool(t, handler, "quay_getRepo", map[string]interface{}{"repository": "redhat/ubi8", "includeTags": true})
+	if result.IsError {
+		t.Fatalf("Expected a dry run result, got %s", resultText(t, result))
+	}
+	var dryRun client.DryRun
+	if err := json.Unmarshal([]byte(resultText(t, result)), &dryRun); err != nil {
+		t.Fatalf("Expected a JSON dry run, got %v", err)
+	}
+	wantURL := s.quayClient.GetRegistryURL() + "/api/v1/repository/redhat/ubi8?includeTags=true"
+	if dryRun.Method != http.MethodGet || dryRun.URL != wantURL {
+		t.Errorf("Expected GET %s, got %s %s", wantURL, dryRun.Method, dryRun.URL)
+	}
+	if dryRun.PathParams["repository"] != "redhat/ubi8" {
+		t.Errorf("Expected the resolved repository path parameter, got %v", dryRun.PathParams)
+	}
+	if got := dryRun.QueryParams["includeTags"]; len(got) != 1 || got[0] != "true" {
+		t.Errorf("Expected the includeTags query parameter, got %v", dryRun.QueryParams)
+	}
+
+	// Substitution errors are reported the same way a real call would hit them
+	if result := callTool(t, handler, "quay_getRepo", nil); !result.IsError {
+		t.Errorf("Expected a missing path parameter error, got %s", resultText(t, result))
+	}
+	if requests != 0 {
+		t.Errorf("Expected no requests to Quay, got %d", requests)
+	}
+}
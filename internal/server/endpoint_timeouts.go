@@ -0,0 +1,72 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// LoadEndpointTimeouts reads a JSON file mapping operationIds or path patterns to call timeouts,
+// e.g. {"getRepo": "5s", "/api/v1/repository/*/tag/*": "2m"}
+func (s *QuayMCPServer) LoadEndpointTimeouts(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("failed to read endpoint timeout map: %v", err)
+	}
+
+	var entries map[string]string
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("invalid endpoint timeout map %s: %v", file, err)
+	}
+
+	timeouts := make(map[string]time.Duration, len(entries))
+	for key, value := range entries {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid timeout for %s in %s: %v", key, file, err)
+		}
+		timeouts[key] = timeout
+	}
+	return s.SetEndpointTimeouts(timeouts)
+}
+
+// SetEndpointTimeouts replaces the call timeout of the endpoints a key matches: an operationId, or
+// a pattern for path.Match when it starts with "/". An operationId wins over patterns and a longer
+// pattern over a shorter one; unlisted endpoints keep the call timeout (0 disables the timeout).
+func (s *QuayMCPServer) SetEndpointTimeouts(timeouts map[string]time.Duration) error {
+	for key, timeout := range timeouts {
+		if timeout < 0 {
+			return fmt.Errorf("negative timeout %s for %s", timeout, key)
+		}
+		if strings.HasPrefix(key, "/") {
+			if _, err := path.Match(key, ""); err != nil {
+				return fmt.Errorf("invalid path pattern %q: %v", key, err)
+			}
+		}
+	}
+	s.endpointTimeouts = timeouts
+	return nil
+}
+
+// endpointTimeout returns the timeout of calls to endpoint
+func (s *QuayMCPServer) endpointTimeout(endpoint *types.EndpointInfo) time.Duration {
+	if timeout, ok := s.endpointTimeouts[endpoint.OperationID]; ok && endpoint.OperationID != "" {
+		return timeout
+	}
+
+	timeout, longest := s.callTimeout, -1
+	for pattern, patternTimeout := range s.endpointTimeouts {
+		if !strings.HasPrefix(pattern, "/") || len(pattern) <= longest {
+			continue
+		}
+		if matched, _ := path.Match(pattern, endpoint.Path); matched {
+			timeout, longest = patternTimeout, len(pattern)
+		}
+	}
+	return timeout
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/quay/quay-mcp-server/internal/client"
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// SetNormalizeErrors enables returning every failed call as the same {"error": {...}} envelope
+func (s *QuayMCPServer) SetNormalizeErrors(enabled bool) {
+	s.normalizeErrors = enabled
+}
+
+// normalizedError is the consistent error shape returned when -normalize-errors is set
+type normalizedError struct {
+	Error errorDetail `json:"error"`
+}
+
+// errorDetail describes a failed call; status is 0 when Quay wasn't reached
+type errorDetail struct {
+	Status  int    `json:"status"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// normalizeError converts a call error into the consistent envelope, pulling the type and message
+// out of whichever shape of error body Quay returned
+func normalizeError(err error) []byte {
+	detail := errorDetail{Type: "request_failed", Message: err.Error()}
+
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		detail.Status = apiErr.StatusCode
+		detail.Type, detail.Message = apiErrorDetail(apiErr)
+	}
+
+	encoded, _ := json.Marshal(normalizedError{Error: detail})
+	return encoded
+}
+
+// apiErrorDetail returns the error type and message of a Quay error response, pulled out of
+// whichever shape of error body Quay returned and falling back to the status text
+func apiErrorDetail(apiErr *client.APIError) (errorType, message string) {
+	errorType = strings.ToLower(strings.ReplaceAll(http.StatusText(apiErr.StatusCode), " ", "_"))
+	message = strings.TrimSpace(string(apiErr.Body))
+
+	var body map[string]interface{}
+	if json.Unmarshal(apiErr.Body, &body) == nil {
+		if bodyType := firstString(body, "error_type", "type", "title"); bodyType != "" {
+			errorType = bodyType
+		}
+		if bodyMessage := firstString(body, "detail", "error_message", "message", "error_description", "error"); bodyMessage != "" {
+			message = bodyMessage
+		}
+	}
+	if message == "" {
+		message = http.StatusText(apiErr.StatusCode)
+	}
+	return errorType, message
+}
+
+// callFailure is the machine-readable payload returned alongside the message of a failed call, so
+// a client can tell a 404 from a 403 without parsing prose; status is 0 when Quay wasn't reached
+type callFailure struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+}
+
+// callFailureResult returns an error result for a failed call: the message first, then the
+// callFailure payload as JSON. Without an API response the path is the endpoint's template.
+func callFailureResult(endpoint *types.EndpointInfo, err error, elapsed time.Duration) *mcp.CallToolResult {
+	failure := callFailure{Message: err.Error(), Method: endpoint.Method, Path: endpoint.Path}
+
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) {
+		failure.Status = apiErr.StatusCode
+		_, failure.Message = apiErrorDetail(apiErr)
+		if apiErr.Method != "" {
+			failure.Method = apiErr.Method
+		}
+		if apiErr.Path != "" {
+			failure.Path = apiErr.Path
+		}
+	}
+
+	payload, _ := json.Marshal(failure)
+	// Include the elapsed time so a fast rejection can be told apart from a slow timeout
+	result := mcp.NewToolResultError(fmt.Sprintf("API call failed after %s: %s", elapsed, err.Error()))
+	result.Content = append(result.Content, mcp.NewTextContent(string(payload)))
+	return result
+}
+
+// firstString returns the first non-empty string value among keys
+func firstString(values map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := values[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
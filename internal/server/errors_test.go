@@ -0,0 +1,96 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestNormalizeErrors(t *testing.T) {
+	errorBodies := map[string]struct {
+		status int
+		body   string
+	}{
+		"problem": {http.StatusForbidden, `{"status": 403, "error_message": "Unauthorized", "title": "insufficient_scope", "error_type": "insufficient_scope", "detail": "Requires repo:read scope"}`},
+		"legacy":  {http.StatusNotFound, `{"message": "Repository not found"}`},
+	}
+
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		response := errorBodies[r.URL.Query().Get("namespace")]
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(response.status)
+		w.Write([]byte(response.body))
+	})
+	s.SetNormalizeErrors(true)
+	handler := s.createToolHandler()
+
+	expected := map[string]errorDetail{
+		"problem": {Status: 403, Type: "insufficient_scope", Message: "Requires repo:read scope"},
+		"legacy":  {Status: 404, Type: "not_found", Message: "Repository not found"},
+	}
+	for namespace, want := range expected {
+		result := callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": namespace})
+		if !result.IsError {
+			t.Errorf("%s: expected an error result", namespace)
+		}
+
+		text, _ := mcp.AsTextContent(result.Content[0])
+		var got normalizedError
+		if err := json.Unmarshal([]byte(text.Text), &got); err != nil {
+			t.Fatalf("%s: expected the error envelope, got %q: %v", namespace, text.Text, err)
+		}
+		if got.Error != want {
+			t.Errorf("%s: expected %+v, got %+v", namespace, want, got.Error)
+		}
+	}
+}
+
+func TestNormalizeNonAPIError(t *testing.T) {
+	var got normalizedError
+	if err := json.Unmarshal(normalizeError(fmt.Errorf("connection refused")), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Error.Status != 0 || got.Error.Type != "request_failed" || got.Error.Message != "connection refused" {
+		t.Errorf("Unexpected envelope %+v", got.Error)
+	}
+}
+
+func TestCallFailurePayload(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/v1/repository/private" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error_message": "Unauthorized", "detail": "Requires repo:read scope"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "Repository not found"}`))
+	})
+	handler := s.createToolHandler()
+
+	expected := map[string]callFailure{
+		"private": {Status: 403, Message: "Requires repo:read scope", Method: "GET", Path: "/api/v1/repository/private"},
+		"missing": {Status: 404, Message: "Repository not found", Method: "GET", Path: "/api/v1/repository/missing"},
+	}
+	for repository, want := range expected {
+		result := callTool(t, handler, "quay_getRepo", map[string]interface{}{"repository": repository})
+		if !result.IsError {
+			t.Errorf("%s: expected an error result", repository)
+		}
+		if len(result.Content) != 2 {
+			t.Fatalf("%s: expected a message and a payload, got %d contents", repository, len(result.Content))
+		}
+
+		text, _ := mcp.AsTextContent(result.Content[1])
+		var got callFailure
+		if err := json.Unmarshal([]byte(text.Text), &got); err != nil {
+			t.Fatalf("%s: expected the failure payload, got %q: %v", repository, text.Text, err)
+		}
+		if got != want {
+			t.Errorf("%s: expected %+v, got %+v", repository, want, got)
+		}
+	}
+}
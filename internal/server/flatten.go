@@ -0,0 +1,51 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SetFlattenResponse enables flattening JSON responses into dot-notation key/value pairs
+func (s *QuayMCPServer) SetFlattenResponse(enabled bool) {
+	s.flattenResponse = enabled
+}
+
+// flattenJSON rewrites a nested JSON document as a flat object whose keys are the paths of its
+// leaf values, e.g. {"tags": [{"name": "latest"}]} becomes {"tags[0].name": "latest"}
+func flattenJSON(data []byte) ([]byte, error) {
+	var parsed interface{}
+	if err := decodeJSON(data, &parsed); err != nil {
+		return nil, fmt.Errorf("response is not valid JSON: %v", err)
+	}
+
+	flat := make(map[string]interface{})
+	flattenValue("", parsed, flat)
+	return json.Marshal(flat)
+}
+
+// flattenValue adds the leaves of value to flat, prefixing their paths with prefix. Empty objects
+// and arrays are kept as leaves so they don't disappear from the result.
+func flattenValue(prefix string, value interface{}, flat map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 && prefix != "" {
+			flat[prefix] = v
+		}
+		for key, field := range v {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flattenValue(path, field, flat)
+		}
+	case []interface{}:
+		if len(v) == 0 && prefix != "" {
+			flat[prefix] = v
+		}
+		for i, item := range v {
+			flattenValue(fmt.Sprintf("%s[%d]", prefix, i), item, flat)
+		}
+	default:
+		flat[prefix] = v
+	}
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFlattenJSON(t *testing.T) {
+	data := []byte(`{
+		"name": "ubi8",
+		"tags": [{"name": "latest", "size": 12345}, {"name": "8.9", "labels": {}}],
+		"namespace": {"name": "redhat", "public": true},
+		"description": null
+	}`)
+
+	flat, err := flattenJSON(data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(flat, &got); err != nil {
+		t.Fatalf("Flattened response is not valid JSON: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"name":             "ubi8",
+		"tags[0].name":     "latest",
+		"tags[0].size":     float64(12345),
+		"tags[1].name":     "8.9",
+		"tags[1].labels":   map[string]interface{}{},
+		"namespace.name":   "redhat",
+		"namespace.public": true,
+		"description":      nil,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// SetResponseHook sets a shell command that every successful response is piped through; its
+// stdout replaces the response (e.g. "jq .repositories")
+func (s *QuayMCPServer) SetResponseHook(command string) {
+	s.responseHook = command
+}
+
+// runResponseHook passes a response to the hook command on stdin and returns its stdout. A
+// non-zero exit is an error carrying the command's stderr.
+func runResponseHook(ctx context.Context, command string, responseData []byte) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(responseData)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("response hook failed: %v: %s", err, message)
+		}
+		return nil, fmt.Errorf("response hook failed: %v", err)
+	}
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,31 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestResponseHook(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"repositories": [{"name": "ubi8"}]}`))
+	})
+	s.SetResponseHook("tr a-z A-Z")
+	handler := s.createToolHandler()
+
+	result := callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "redhat"})
+	text, _ := mcp.AsTextContent(result.Content[0])
+	if text.Text != `{"REPOSITORIES": [{"NAME": "UBI8"}]}` {
+		t.Errorf("Expected the hook's uppercased output, got %q", text.Text)
+	}
+
+	s.SetResponseHook("echo 'bad input' >&2; exit 3")
+	result = callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "redhat"})
+	text, _ = mcp.AsTextContent(result.Content[0])
+	if !result.IsError || !strings.Contains(text.Text, "exit status 3") || !strings.Contains(text.Text, "bad input") {
+		t.Errorf("Expected a hook failure with its exit status and stderr, got %+v", result)
+	}
+}
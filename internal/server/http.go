@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Transports the MCP server can be served over. The HTTP transports also serve /healthz and /readyz.
+const (
+	TransportStdio = "stdio" // stdin/stdout, for a single client that launches the process
+	TransportSSE   = "sse"   // HTTP server-sent events, at /sse with messages posted to /message
+	TransportHTTP  = "http"  // streamable HTTP, at /mcp
+)
+
+// Transports lists the accepted -transport values
+var Transports = []string{TransportStdio, TransportSSE, TransportHTTP}
+
+// DefaultListenAddress is where the HTTP transports listen unless configured otherwise
+const DefaultListenAddress = "localhost:8080"
+
+// shutdownTimeout bounds how long in-flight HTTP requests get to finish after SIGINT or SIGTERM
+var shutdownTimeout = 10 * time.Second
+
+// SetTransport selects how Start serves MCP and, for the HTTP transports, the address to listen on
+func (s *QuayMCPServer) SetTransport(transport, listenAddress string) error {
+	switch transport {
+	case TransportStdio, TransportSSE, TransportHTTP:
+	default:
+		return fmt.Errorf("unknown transport %q (expected one of %v)", transport, Transports)
+	}
+	if transport != TransportStdio && listenAddress == "" {
+		return fmt.Errorf("the %s transport needs a listen address", transport)
+	}
+	s.transport = transport
+	s.listenAddress = listenAddress
+	return nil
+}
+
+// serve serves MCP over the configured transport
+func (s *QuayMCPServer) serve() error {
+	if s.transport == "" || s.transport == TransportStdio {
+		return s.serveStdio()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	listener, err := net.Listen("tcp", s.listenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.listenAddress, err)
+	}
+	return s.serveHTTP(ctx, listener)
+}
+
+// serveHTTP serves MCP over the configured HTTP transport on listener until ctx is done, then
+// shuts down gracefully, closing open SSE sessions and letting in-flight calls finish
+func (s *QuayMCPServer) serveHTTP(ctx context.Context, listener net.Listener) error {
+	httpServer := &http.Server{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", handleHealthz)
+	mux.HandleFunc("/readyz", s.handleReadyz)
+	httpServer.Handler = mux
+
+	var shutdown func(context.Context) error
+	switch s.transport {
+	case TransportSSE:
+		sse := server.NewSSEServer(s.mcpServer, server.WithHTTPServer(httpServer))
+		mux.Handle(sse.CompleteSsePath(), sse)
+		mux.Handle(sse.CompleteMessagePath(), sse)
+		shutdown = sse.Shutdown
+	default:
+		streamable := server.NewStreamableHTTPServer(s.mcpServer, server.WithStreamableHTTPServer(httpServer))
+		mux.Handle("/mcp", streamable)
+		shutdown = streamable.Shutdown
+	}
+
+	slog.Info("Serving MCP over HTTP", "transport", s.transport, "address", listener.Addr().String())
+
+	served := make(chan error, 1)
+	go func() {
+		served <- httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down the HTTP server", "timeout", shutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down the HTTP server: %w", err)
+	}
+	if err := <-served; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
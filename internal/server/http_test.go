@@ -0,0 +1,135 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startHTTP serves s over its HTTP transport on a free local port, returning the base URL and a
+// function that stops the server and returns serveHTTP's result
+func startHTTP(t *testing.T, s *QuayMCPServer) (string, func() error) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- s.serveHTTP(ctx, listener)
+	}()
+
+	stop := func() error {
+		cancel()
+		select {
+		case err := <-served:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected the HTTP server to shut down")
+			return nil
+		}
+	}
+	return "http://" + listener.Addr().String(), stop
+}
+
+func TestSetTransport(t *testing.T) {
+	s := NewQuayMCPServer("https://quay.io", "")
+	if err := s.SetTransport("websocket", DefaultListenAddress); err == nil {
+		t.Error("Expected an error for an unknown transport")
+	}
+	if err := s.SetTransport(TransportHTTP, ""); err == nil {
+		t.Error("Expected an error for an HTTP transport without a listen address")
+	}
+	if err := s.SetTransport(TransportStdio, ""); err != nil {
+		t.Errorf("Expected stdio to need no listen address, got %v", err)
+	}
+}
+
+func TestStreamableHTTPTransport(t *testing.T) {
+	s := NewQuayMCPServer("https://quay.io", "")
+	if err := s.SetTransport(TransportHTTP, DefaultListenAddress); err != nil {
+		t.Fatal(err)
+	}
+	baseURL, stop := startHTTP(t, s)
+
+	initialize := `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26", "capabilities": {}, "clientInfo": {"name": "test", "version": "1.0"}}}`
+	resp, err := http.Post(baseURL+"/mcp", "application/json", strings.NewReader(initialize))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `"name":"quay-mcp"`) {
+		t.Errorf("Expected the server to initialize, got status %d: %s", resp.StatusCode, body)
+	}
+
+	if err := stop(); err != nil {
+		t.Errorf("Expected a clean shutdown, got %v", err)
+	}
+}
+
+func TestSSETransportShutdown(t *testing.T) {
+	s := NewQuayMCPServer("https://quay.io", "")
+	if err := s.SetTransport(TransportSSE, DefaultListenAddress); err != nil {
+		t.Fatal(err)
+	}
+	baseURL, stop := startHTTP(t, s)
+
+	// Open an SSE session and wait for the endpoint to post messages to
+	resp, err := http.Get(baseURL + "/sse")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Expected the endpoint event, got %v", err)
+		}
+		if strings.HasPrefix(line, "data: ") {
+			if !strings.Contains(line, "/message?sessionId=") {
+				t.Errorf("Expected the message endpoint, got %q", line)
+			}
+			break
+		}
+	}
+
+	// The open session doesn't hold up shutdown
+	if err := stop(); err != nil {
+		t.Errorf("Expected a clean shutdown, got %v", err)
+	}
+}
+
+func TestHTTPTransportServesProbes(t *testing.T) {
+	s := NewQuayMCPServer("https://quay.io", "")
+	if err := s.SetTransport(TransportHTTP, DefaultListenAddress); err != nil {
+		t.Fatal(err)
+	}
+	baseURL, stop := startHTTP(t, s)
+
+	// A dedicated client whose keep-alive connections are closed before shutdown
+	httpClient := &http.Client{Transport: &http.Transport{}}
+	for _, path := range []string{"/healthz", "/readyz"} {
+		resp, err := httpClient.Get(baseURL + path)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("Expected %s to answer 200, got %d", path, resp.StatusCode)
+		}
+	}
+	httpClient.CloseIdleConnections()
+
+	if err := stop(); err != nil {
+		t.Errorf("Expected a clean shutdown, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// largeSize is beyond 2^53, so it doesn't survive a round-trip through float64
+const largeSize = "9007199254740993"
+
+func TestReparsedResponsesKeepLargeIntegers(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("next_page") == "" {
+			w.Write([]byte(`{"repositories": [{"name": "ubi8", "size": ` + largeSize + `}], "next_page": "page2"}`))
+			return
+		}
+		w.Write([]byte(`{"repositories": [{"name": "ubi9", "size": ` + largeSize + `}]}`))
+	})
+	s.SetAutoPaginate(2)
+	s.SetPaginationCursor(true)
+	s.SetResponseMaxDepth(5)
+	s.SetFlattenResponse(true)
+
+	text := resultText(t, callTool(t, s.createToolHandler(), "quay_listRepos", map[string]interface{}{"namespace": "redhat"}))
+	for _, want := range []string{`"items[0].size":` + largeSize, `"items[1].size":` + largeSize} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %s in %s", want, text)
+		}
+	}
+}
+
+func TestDiffResponsesLargeIntegers(t *testing.T) {
+	differences, err := diffResponses([]byte(`{"size": `+largeSize+`}`), []byte(`{"size": 9007199254740992}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(differences) != 1 || differences[0].Path != "$.size" {
+		t.Errorf("Expected sizes differing only past float64 precision to be reported, got %+v", differences)
+	}
+}
+
+func TestDecodeJSONRejectsTrailingData(t *testing.T) {
+	var parsed interface{}
+	if err := decodeJSON([]byte(`{"name": "ubi8"} {}`), &parsed); err == nil {
+		t.Error("Expected trailing data to be rejected")
+	}
+	if err := decodeJSON([]byte(` {"name": "ubi8"}`+"\n"), &parsed); err != nil {
+		t.Errorf("Expected surrounding whitespace to be accepted, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// lastHeadersTool is the meta-tool that returns the headers of the most recent Quay response
+const lastHeadersTool = "quay_last_response_headers"
+
+// lastResponse records the headers of the most recent successful Quay response
+type lastResponse struct {
+	mu         sync.Mutex
+	Tool       string      `json:"tool"`
+	StatusCode int         `json:"status"`
+	ReceivedAt time.Time   `json:"received_at"`
+	Header     http.Header `json:"headers"`
+}
+
+// recordResponse stores the headers of a response returned to a tool call
+func (s *QuayMCPServer) recordResponse(toolName string, statusCode int, header http.Header) {
+	s.lastResponse.mu.Lock()
+	defer s.lastResponse.mu.Unlock()
+
+	s.lastResponse.Tool = toolName
+	s.lastResponse.StatusCode = statusCode
+	s.lastResponse.ReceivedAt = time.Now().UTC()
+	s.lastResponse.Header = header.Clone()
+}
+
+// newLastHeadersTool describes the last response headers meta-tool
+func newLastHeadersTool() mcp.Tool {
+	return mcp.NewTool(lastHeadersTool,
+		mcp.WithDescription("Returns the HTTP headers of the most recent successful Quay API response, such as rate limits and ETags, with the tool and status that produced it"),
+	)
+}
+
+// createLastHeadersHandler creates the handler for the last response headers meta-tool
+func (s *QuayMCPServer) createLastHeadersHandler() func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.lastResponse.mu.Lock()
+		defer s.lastResponse.mu.Unlock()
+
+		if s.lastResponse.Header == nil {
+			return mcp.NewToolResultError("No Quay API call has completed yet; call a Quay tool first"), nil
+		}
+
+		encoded, err := json.MarshalIndent(&s.lastResponse, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to encode response headers: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(encoded)), nil
+	}
+}
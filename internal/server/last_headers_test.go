@@ -0,0 +1,52 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestLastResponseHeaders(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.Header().Set("ETag", `"abc123"`)
+		w.Write([]byte(`{"repositories": []}`))
+	})
+	headersHandler := s.createLastHeadersHandler()
+
+	// Before any call there are no headers to return
+	result := callTool(t, headersHandler, lastHeadersTool, nil)
+	text, _ := mcp.AsTextContent(result.Content[0])
+	if !result.IsError || !strings.Contains(text.Text, "No Quay API call") {
+		t.Errorf("Expected an error before any call, got %+v", result)
+	}
+
+	callTool(t, s.createToolHandler(), "quay_listRepos", map[string]interface{}{"namespace": "redhat"})
+
+	result = callTool(t, headersHandler, lastHeadersTool, nil)
+	if result.IsError {
+		t.Fatalf("Expected the last response headers, got %+v", result)
+	}
+	text, _ = mcp.AsTextContent(result.Content[0])
+	var last struct {
+		Tool    string              `json:"tool"`
+		Status  int                 `json:"status"`
+		Headers map[string][]string `json:"headers"`
+	}
+	if err := json.Unmarshal([]byte(text.Text), &last); err != nil {
+		t.Fatalf("Expected JSON, got %q: %v", text.Text, err)
+	}
+	if last.Tool != "quay_listRepos" || last.Status != http.StatusOK {
+		t.Errorf("Expected the listRepos call with status 200, got %+v", last)
+	}
+	if got := last.Headers["X-Ratelimit-Remaining"]; len(got) != 1 || got[0] != "42" {
+		t.Errorf("Expected the rate limit header, got %v", last.Headers)
+	}
+	if got := last.Headers["Etag"]; len(got) != 1 || got[0] != `"abc123"` {
+		t.Errorf("Expected the ETag header, got %v", last.Headers)
+	}
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// listToolsTool is the meta-tool that lists the tools generated from the Quay API spec
+const listToolsTool = "quay_list_tools"
+
+// toolSummary describes a generated tool in the listing
+type toolSummary struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Tags        []string `json:"tags,omitempty"`
+	Required    []string `json:"required,omitempty"`
+}
+
+// newListToolsTool describes the tool listing meta-tool
+func newListToolsTool() mcp.Tool {
+	return mcp.NewTool(listToolsTool,
+		mcp.WithDescription("Lists the tools generated from the Quay API as JSON, with their descriptions, tags and required parameters"),
+	)
+}
+
+// createListToolsHandler creates the handler for the tool listing meta-tool
+func (s *QuayMCPServer) createListToolsHandler() func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		summaries := make([]toolSummary, 0, len(s.tools))
+		for name, tool := range s.tools {
+			summary := toolSummary{
+				Name:        name,
+				Description: tool.Description,
+				Required:    tool.InputSchema.Required,
+			}
+			if endpoint, ok := s.toolEndpoints[name]; ok {
+				summary.Tags = endpoint.Tags
+			}
+			summaries = append(summaries, summary)
+		}
+		sort.Slice(summaries, func(i, j int) bool {
+			return summaries[i].Name < summaries[j].Name
+		})
+
+		encoded, err := json.MarshalIndent(summaries, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to encode the tool list: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(encoded)), nil
+	}
+}
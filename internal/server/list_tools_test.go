@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestListTools(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no API call, got %s", r.URL.Path)
+	})
+	if err := s.Initialize(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	result, err := s.CallTool(context.Background(), listToolsTool, nil)
+	if err != nil || result.IsError {
+		t.Fatalf("Expected the tool list, got %+v, %v", result, err)
+	}
+	var tools []toolSummary
+	if err := json.Unmarshal([]byte(resultText(t, result)), &tools); err != nil {
+		t.Fatalf("Expected JSON, got %q: %v", resultText(t, result), err)
+	}
+
+	// Only generated tools are listed, sorted by name
+	if len(tools) != 2 || tools[0].Name != "quay_getRepo" || tools[1].Name != "quay_listRepos" {
+		t.Fatalf("Expected quay_getRepo and quay_listRepos, got %+v", tools)
+	}
+	getRepo := tools[0]
+	if getRepo.Description == "" {
+		t.Errorf("Expected a description, got %+v", getRepo)
+	}
+	if len(getRepo.Tags) != 1 || getRepo.Tags[0] != "repository" {
+		t.Errorf("Expected the repository tag, got %v", getRepo.Tags)
+	}
+	if len(getRepo.Required) != 1 || getRepo.Required[0] != "repository" {
+		t.Errorf("Expected repository to be required, got %v", getRepo.Required)
+	}
+	if len(tools[1].Required) != 0 {
+		t.Errorf("Expected no required parameters for quay_listRepos, got %v", tools[1].Required)
+	}
+}
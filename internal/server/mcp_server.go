@@ -2,9 +2,13 @@ package server
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -13,10 +17,70 @@ import (
 	"github.com/quay/quay-mcp-server/internal/types"
 )
 
+// DefaultToolCountThreshold is the tool count above which a warning is logged, since many
+// MCP clients degrade with large tool lists
+const DefaultToolCountThreshold = 50
+
 // QuayMCPServer wraps the MCP server with Quay-specific functionality
 type QuayMCPServer struct {
 	quayClient *client.QuayClient
 	mcpServer  *server.MCPServer
+
+	resultAsResource   bool // wrap responses as embedded application/json resources
+	flattenResponse    bool // flatten JSON responses into dot-notation key/value pairs
+	paginationCursor   bool // reshape list responses into {"items": [...], "cursor": "..."}
+	normalizeErrors    bool // return failed calls as a consistent {"error": {...}} envelope
+	reconnectOnEOF     bool // serve stdin again after EOF instead of exiting
+	summarize          bool // prepend a short human-readable summary to results
+	failClosed         bool // refuse to start when Quay rejects the credentials
+	allowPerCallToken  bool // accept an auth_token argument that replaces the token for one call
+	dryRun             bool // return the request a call would send instead of calling Quay
+	toolCountThreshold int  // warn when more tools than this are generated (0 disables)
+	autoPaginate       int  // follow next_page up to this many pages per call (0 or 1 disables)
+	responseMaxDepth   int  // prune JSON responses nested deeper than this (0 disables)
+
+	responseHook string // shell command each successful response is piped through
+
+	smokeTestTool string                 // GET tool called once at startup to check the server end to end
+	smokeTestArgs map[string]interface{} // arguments of the smoke test call
+
+	unwrapInfer bool              // unwrap responses whose single top-level key holds an array
+	unwrapKeys  map[string]string // tool name -> top-level key whose value replaces its responses
+
+	summarizer         ResponseSummarizer // condenses responses larger than summarizeThreshold
+	summarizeThreshold int                // response size in bytes above which summarizer is used
+
+	transport     string // how Start serves MCP: stdio (the default), sse or http
+	listenAddress string // address the HTTP transports listen on
+
+	healthPollInterval time.Duration // how often Start polls the registry for readiness (0 disables)
+	readiness          readiness     // result of the latest registry health poll
+
+	chunkSize     int                // split responses larger than this many bytes (0 disables)
+	continuations *continuationStore // unread remainders of split responses
+
+	lastResponse lastResponse // headers of the most recent successful Quay response
+	metrics      callMetrics  // call counts and latencies per tool
+
+	callTimeout         time.Duration            // upper bound for each tool call (0 disables)
+	deadlinePropagation bool                     // also honor the deadline of the MCP client's call context
+	endpointTimeouts    map[string]time.Duration // call timeouts by operationId or path pattern, replacing callTimeout
+
+	allowedNamespaces map[string]bool // namespaces tool calls may target (nil allows all)
+
+	mirrorURL   string // second registry used by the compare meta-tool
+	mirrorToken string
+
+	tools        map[string]mcp.Tool // generated tools by name
+	validateArgs bool                // validate arguments against the tool schema before calling Quay
+	metaTools    bool                // register the batch, last headers, metrics and list tools meta-tools
+
+	handlers map[string]server.ToolHandlerFunc // handlers of every registered tool, for direct calls
+
+	toolEndpoints map[string]*types.EndpointInfo // endpoints of the generated tools by tool name
+
+	diagnosticsOutput io.Writer // where the startup diagnostics snapshot is written (nil disables it)
+	warnings          []string  // startup warnings reported in the diagnostics snapshot
 }
 
 // NewQuayMCPServer creates a new Quay MCP server
@@ -27,7 +91,15 @@ func NewQuayMCPServer(registryURL, oauthToken string) *QuayMCPServer {
 			"quay-mcp",
 			"1.0.0",
 			server.WithToolCapabilities(false), // Enable tools
+			server.WithResourceCapabilities(false, false),
 		),
+		toolCountThreshold:  DefaultToolCountThreshold,
+		summarizer:          NoopSummarizer{},
+		summarizeThreshold:  DefaultSummarizeThreshold,
+		deadlinePropagation: true,
+		metaTools:           true,
+		metrics:             callMetrics{since: time.Now().UTC()},
+		handlers:            make(map[string]server.ToolHandlerFunc),
 	}
 }
 
@@ -36,58 +108,142 @@ func (s *QuayMCPServer) GetQuayClient() *client.QuayClient {
 	return s.quayClient
 }
 
+// SetResultAsResource makes tool calls return responses as embedded MCP resources instead of plain text
+func (s *QuayMCPServer) SetResultAsResource(enabled bool) {
+	s.resultAsResource = enabled
+}
+
+// SetMetaTools controls whether the batch, last response headers, metrics and tool listing
+// meta-tools are registered alongside the generated tools (the default)
+func (s *QuayMCPServer) SetMetaTools(enabled bool) {
+	s.metaTools = enabled
+}
+
+// SetFailClosed makes Initialize check the credentials with a health check and fail when Quay
+// rejects them with 401 or 403, instead of serving tools that would all fail
+func (s *QuayMCPServer) SetFailClosed(enabled bool) {
+	s.failClosed = enabled
+}
+
+// SetSummarize makes tool calls return a short summary of list responses alongside the raw JSON
+func (s *QuayMCPServer) SetSummarize(enabled bool) {
+	s.summarize = enabled
+}
+
+// SetToolCountThreshold sets the tool count above which a warning is logged (0 disables the warning)
+func (s *QuayMCPServer) SetToolCountThreshold(threshold int) {
+	s.toolCountThreshold = threshold
+}
+
+// checkToolCount logs a prominent warning when count exceeds the configured threshold and reports whether it did
+func (s *QuayMCPServer) checkToolCount(count int) bool {
+	if s.toolCountThreshold <= 0 || count <= s.toolCountThreshold {
+		return false
+	}
+
+	s.warn("generated %d tools, more than the threshold of %d; many MCP clients degrade with large tool lists, consider filtering tags or operations", count, s.toolCountThreshold)
+	return true
+}
+
+// SetAllowedNamespaces restricts tool calls to the given namespaces (an empty list allows all)
+func (s *QuayMCPServer) SetAllowedNamespaces(namespaces []string) {
+	if len(namespaces) == 0 {
+		s.allowedNamespaces = nil
+		return
+	}
+
+	s.allowedNamespaces = make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		s.allowedNamespaces[ns] = true
+	}
+}
+
+// checkNamespace rejects tool arguments that target a namespace outside the allow-list.
+// Namespaces come from the endpoint's namespace/orgname parameters, with the default namespace
+// standing in for omitted ones, and the namespace part of a repository argument.
+func (s *QuayMCPServer) checkNamespace(endpoint *types.EndpointInfo, arguments map[string]interface{}) error {
+	if s.allowedNamespaces == nil {
+		return nil
+	}
+
+	// Check the parameters as they will be sent, under their API names and with defaults applied
+	params := s.quayClient.ApplyNamespaceDefault(endpoint, s.quayClient.APIParams(endpoint, arguments))
+
+	var namespaces []string
+	for _, name := range []string{"namespace", "orgname"} {
+		if value, ok := params[name].(string); ok && value != "" {
+			namespaces = append(namespaces, value)
+		}
+	}
+	if repository, ok := params["repository"].(string); ok {
+		if ns, _, found := strings.Cut(repository, "/"); found {
+			namespaces = append(namespaces, ns)
+		}
+	}
+
+	for _, ns := range namespaces {
+		if !s.allowedNamespaces[ns] {
+			return fmt.Errorf("namespace %q is not in the list of allowed namespaces", ns)
+		}
+	}
+	return nil
+}
+
+// findEndpoint resolves a generated tool name back to the endpoint it was created from
+func (s *QuayMCPServer) findEndpoint(toolName string) (*types.EndpointInfo, error) {
+	// Every generated tool carries the configured prefix
+	prefix := s.quayClient.ToolPrefix()
+	if !strings.HasPrefix(toolName, prefix) {
+		return nil, fmt.Errorf("Invalid tool name: must start with '%s'", prefix)
+	}
+
+	// Match the name each endpoint's tool was generated with, including any tag prefix
+	for _, ep := range s.quayClient.GetEndpoints() {
+		if s.quayClient.ToolName(ep.Method, ep.OperationID, ep.Path, ep.Tags) == toolName {
+			return ep, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Endpoint not found for tool: %s", toolName)
+}
+
 // createToolHandler creates a handler function for MCP tool calls
 func (s *QuayMCPServer) createToolHandler() func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Extract tool name and find corresponding endpoint
 		toolName := request.Params.Name
+		arguments := request.GetArguments()
 
-		// Remove "quay_" prefix to get the original identifier
-		if !strings.HasPrefix(toolName, "quay_") {
-			return mcp.NewToolResultError("Invalid tool name: must start with 'quay_'"), nil
+		endpoint, err := s.findEndpoint(toolName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		identifier := strings.TrimPrefix(toolName, "quay_")
-
-		// Find the endpoint by operation ID or path
-		var endpoint *types.EndpointInfo
-
-		endpoints := s.quayClient.GetEndpoints()
-		arguments := request.GetArguments()
+		// A per-call token never reaches Quay as an argument, nor the logs
+		arguments, callToken, err := s.takeCallToken(arguments)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 
-		// First try to find by operation ID
-		for _, ep := range endpoints {
-			if ep.OperationID == identifier {
-				endpoint = ep
-				break
-			}
+		// A continuation token reads the next chunk of an earlier response without calling Quay
+		if token, ok := arguments[continuationArgument].(string); ok && token != "" && s.chunkSize > 0 {
+			return s.continueResult(toolName, token), nil
 		}
 
-		// If not found by operation ID, try to find by path-based identifier
-		if endpoint == nil {
-			for _, ep := range endpoints {
-				pathIdentifier := strings.ReplaceAll(strings.Trim(ep.Path, "/"), "/", "_")
-				pathIdentifier = strings.ReplaceAll(pathIdentifier, "{", "")
-				pathIdentifier = strings.ReplaceAll(pathIdentifier, "}", "")
-				if pathIdentifier == "" {
-					pathIdentifier = "root"
-				}
+		if err := s.checkNamespace(endpoint, arguments); err != nil {
+			slog.Warn("Rejected call", "tool", toolName, "error", err)
+			return mcp.NewToolResultError(err.Error()), nil
+		}
 
-				if pathIdentifier == identifier {
-					endpoint = ep
-					break
+		if s.validateArgs {
+			if tool, ok := s.tools[toolName]; ok {
+				if problems := validateArguments(tool, arguments); len(problems) > 0 {
+					slog.Warn("Rejected call with invalid arguments", "tool", toolName, "problems", len(problems))
+					return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments for %s:\n- %s", toolName, strings.Join(problems, "\n- "))), nil
 				}
 			}
 		}
 
-		if endpoint == nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Endpoint not found for tool: %s", toolName)), nil
-		}
-
-		// Use the new method that handles both path and query parameters for all endpoints
-		log.Printf("Making API call to endpoint: %s %s", endpoint.Method, endpoint.Path)
-		log.Printf("With arguments: %+v", arguments)
-
 		// Handle custom resource_uri if provided - but only for path parameter construction
 		if customURI, exists := arguments["resource_uri"]; exists {
 			if customURIStr, ok := customURI.(string); ok && customURIStr != "" {
@@ -95,44 +251,407 @@ func (s *QuayMCPServer) createToolHandler() func(context.Context, mcp.CallToolRe
 				// if it's a complete custom URI that doesn't follow our parameter pattern
 				if s.quayClient.HasPathParameters(endpoint.Path) {
 					// Still use the new method but log the custom URI usage
-					log.Printf("Custom resource_uri provided but endpoint has path parameters, using new method")
+					slog.Debug("Custom resource_uri provided but endpoint has path parameters, using new method", "tool", toolName)
 				}
 			}
 		}
 
-		responseData, err := s.quayClient.MakeAPICallWithParams(endpoint, arguments)
+		if s.dryRun {
+			return s.dryRunResult(toolName, endpoint, arguments), nil
+		}
+
+		requestID := requestIDFromMeta(request)
+		ctx = client.WithRequestID(ctx, requestID)
+		if callToken != "" {
+			ctx = client.WithToken(ctx, callToken)
+		}
+
+		ctx, cancel := s.callContext(ctx, endpoint)
+		defer cancel()
+
+		// Log the call, with its arguments only at debug level
+		attrs := []any{"tool", toolName, "method", endpoint.Method, "path", endpoint.Path, "correlation_id", requestID}
+		if deadline, ok := ctx.Deadline(); ok {
+			attrs = append(attrs, "deadline", deadline.Format(time.RFC3339Nano))
+		}
+		slog.Info("Calling Quay API", attrs...)
+		slog.Debug("Tool call arguments", "tool", toolName, "arguments", arguments)
+
+		start := time.Now()
+		response, err := s.quayClient.CallAPI(ctx, endpoint, arguments)
+		// Arguments that can't fill the endpoint's path or lack a required filter are the caller's
+		// mistake, and no request was made
+		var missingErr *client.MissingPathParametersError
+		if errors.As(err, &missingErr) {
+			return mcp.NewToolResultError(fmt.Sprintf("Missing required path parameters for %s: %s", toolName, strings.Join(missingErr.Names, ", "))), nil
+		}
+		var filterErr *client.MissingFilterError
+		if errors.As(err, &filterErr) {
+			return mcp.NewToolResultError(fmt.Sprintf("Missing a filter for %s: %s", toolName, filterErr.Error())), nil
+		}
+		s.metrics.recordCall(toolName, time.Since(start), err != nil)
 		if err != nil {
-			return mcp.NewToolResultText(fmt.Sprintf("API call failed: %s", err.Error())), nil
+			if s.normalizeErrors {
+				return mcp.NewToolResultError(string(normalizeError(err))), nil
+			}
+			return callFailureResult(endpoint, err, time.Since(start).Round(time.Millisecond)), nil
+		}
+
+		s.recordResponse(toolName, response.StatusCode, response.Header)
+		responseData := response.Body
+		contentType := response.Header.Get("Content-Type")
+
+		if s.autoPaginate > 1 {
+			responseData = s.fetchRemainingPages(ctx, endpoint, arguments, responseData)
+		}
+
+		if s.responseHook != "" {
+			if responseData, err = runResponseHook(ctx, s.responseHook, responseData); err != nil {
+				slog.Warn("Response hook failed", "tool", toolName, "error", err)
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+		switch classifyResponse(contentType, responseData) {
+		case responseText:
+			responseData = s.summarizeLarge(ctx, toolName, responseData)
+			if s.chunkSize > 0 && len(responseData) > s.chunkSize {
+				return s.chunkedResult(toolName, responseData), nil
+			}
+			return mcp.NewToolResultText(string(responseData)), nil
+		case responseBinary:
+			return s.newBinaryResult(endpoint, arguments, contentType, responseData), nil
+		}
+
+		if s.paginationCursor {
+			responseData = cursorResponse(responseData)
+		}
+
+		if s.unwrapInfer || len(s.unwrapKeys) > 0 {
+			responseData = s.unwrapResponse(toolName, responseData)
+		}
+
+		if s.responseMaxDepth > 0 {
+			if pruned, err := pruneJSON(responseData, s.responseMaxDepth); err != nil {
+				slog.Warn("Returning response unpruned", "tool", toolName, "error", err)
+			} else {
+				responseData = pruned
+			}
+		}
+
+		if s.flattenResponse {
+			if flat, err := flattenJSON(responseData); err != nil {
+				slog.Warn("Returning response unflattened", "tool", toolName, "error", err)
+			} else {
+				responseData = flat
+			}
+		}
+
+		responseData = s.summarizeLarge(ctx, toolName, responseData)
+
+		if s.chunkSize > 0 && len(responseData) > s.chunkSize {
+			return s.chunkedResult(toolName, responseData), nil
+		}
+
+		return s.newToolResult(endpoint, arguments, responseData), nil
+	}
+}
+
+// requestIDFromMeta returns the request/trace ID supplied in the call's _meta, or a newly generated one
+func requestIDFromMeta(request mcp.CallToolRequest) string {
+	if request.Params.Meta != nil {
+		for _, key := range []string{"request_id", "requestId", "trace_id", "traceId"} {
+			if id, ok := request.Params.Meta.AdditionalFields[key].(string); ok && id != "" {
+				return id
+			}
+		}
+	}
+	return client.NewRequestID()
+}
+
+// newToolResult wraps a successful API response in a tool result, either as text (the default)
+// or as an embedded application/json resource
+func (s *QuayMCPServer) newToolResult(endpoint *types.EndpointInfo, arguments map[string]interface{}, responseData []byte) *mcp.CallToolResult {
+	summary := ""
+	if s.summarize {
+		summary = summarizeResponse(responseData, arguments)
+	}
+
+	if !s.resultAsResource {
+		if summary != "" {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{
+					mcp.NewTextContent(summary),
+					mcp.NewTextContent(string(responseData)),
+				},
+			}
 		}
 
 		// Return the JSON response as text
-		return mcp.NewToolResultText(string(responseData)), nil
+		return mcp.NewToolResultText(string(responseData))
+	}
+
+	resourceURI, err := s.quayClient.BuildAPIURLWithParams(endpoint, arguments)
+	if err != nil {
+		resourceURI = "quay://" + strings.TrimPrefix(endpoint.Path, "/")
 	}
+
+	if summary == "" {
+		summary = fmt.Sprintf("%s %s returned %d bytes of JSON", endpoint.Method, endpoint.Path, len(responseData))
+	}
+
+	return mcp.NewToolResultResource(
+		summary,
+		mcp.TextResourceContents{
+			URI:      resourceURI,
+			MIMEType: "application/json",
+			Text:     string(responseData),
+		},
+	)
 }
 
-// Start initializes and starts the MCP server
-func (s *QuayMCPServer) Start() error {
-	// Fetch swagger spec
-	if err := s.quayClient.FetchSwaggerSpec(); err != nil {
-		return fmt.Errorf("failed to fetch swagger spec: %v", err)
+// summarizeResponse derives a one-line summary from list-shaped responses, e.g.
+// "Found 12 repositories in redhat". It returns an empty string for other shapes.
+func summarizeResponse(responseData []byte, arguments map[string]interface{}) string {
+	var parsed interface{}
+	if err := json.Unmarshal(responseData, &parsed); err != nil {
+		return ""
 	}
 
-	// Discover endpoints
-	s.quayClient.DiscoverEndpoints()
+	var count int
+	var noun string
+	hasMore := false
 
-	// Generate and add tools
-	tools := s.quayClient.GenerateTools()
+	switch value := parsed.(type) {
+	case []interface{}:
+		count, noun = len(value), "items"
+	case map[string]interface{}:
+		key, items, found := itemArray(value)
+		if !found {
+			return ""
+		}
+		count, noun = len(items), key
+		if next, ok := value["next_page"].(string); ok && next != "" {
+			hasMore = true
+		}
+	default:
+		return ""
+	}
 
+	summary := fmt.Sprintf("Found %d %s", count, noun)
+	for _, name := range []string{"namespace", "orgname", "repository"} {
+		if scope, ok := arguments[name].(string); ok && scope != "" {
+			summary += " in " + scope
+			break
+		}
+	}
+	if hasMore {
+		summary += " (more results available via next_page)"
+	}
+	return summary
+}
+
+// itemArray returns the largest top-level array of a response object, which is the item list for
+// Quay's list responses
+func itemArray(value map[string]interface{}) (string, []interface{}, bool) {
+	var itemKey string
+	var items []interface{}
+	found := false
+	for key, field := range value {
+		if array, ok := field.([]interface{}); ok && (!found || len(array) > len(items) || (len(array) == len(items) && key < itemKey)) {
+			itemKey, items, found = key, array, true
+		}
+	}
+	return itemKey, items, found
+}
+
+// responseExampleTool is the meta-tool returning an endpoint's documented example response
+const responseExampleTool = "quay_get_response_example"
+
+// createResponseExampleHandler creates the handler for the response example meta-tool
+func (s *QuayMCPServer) createResponseExampleHandler() func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		toolName, err := request.RequireString("tool")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		endpoint, err := s.findEndpoint(toolName)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if endpoint.ResponseExample == "" {
+			return mcp.NewToolResultText(fmt.Sprintf("No example response is documented for %s", toolName)), nil
+		}
+
+		return mcp.NewToolResultText(endpoint.ResponseExample), nil
+	}
+}
+
+// hasResponseExamples reports whether any discovered endpoint documents an example response
+func (s *QuayMCPServer) hasResponseExamples() bool {
+	for _, ep := range s.quayClient.GetEndpoints() {
+		if ep.ResponseExample != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// registerTools adds the generated tools to the MCP server with a shared handler and remembers
+// them by name for argument validation
+func (s *QuayMCPServer) registerTools(tools []mcp.Tool) {
 	// Create a shared tool handler
 	toolHandler := s.createToolHandler()
 
+	s.tools = make(map[string]mcp.Tool, len(tools))
+
+	// Resolve every tool name to its endpoint once, rather than searching the endpoints per tool
+	endpoints := s.quayClient.GetEndpoints()
+	s.toolEndpoints = make(map[string]*types.EndpointInfo, len(endpoints))
+	for _, ep := range endpoints {
+		s.toolEndpoints[s.quayClient.ToolName(ep.Method, ep.OperationID, ep.Path, ep.Tags)] = ep
+	}
+
 	// Add all tools
 	for _, tool := range tools {
 		// Capture the tool in the closure
 		currentTool := tool
-		s.mcpServer.AddTool(currentTool, toolHandler)
+		if s.chunkSize > 0 {
+			currentTool = withContinuationArgument(currentTool)
+		}
+		if s.allowPerCallToken {
+			currentTool = withAuthTokenArgument(currentTool)
+		}
+		s.tools[currentTool.Name] = currentTool
+		s.addTool(currentTool, toolHandler)
+	}
+}
+
+// addTool registers a tool with the MCP server and remembers its handler for CallTool. The text
+// of every result is redacted, so a token echoed by a failed request never reaches the client.
+func (s *QuayMCPServer) addTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	handler = s.redactingHandler(handler)
+	s.handlers[tool.Name] = handler
+	s.mcpServer.AddTool(tool, handler)
+}
+
+// addMetaTool registers a meta-tool under its name with quay_ replaced by the configured tool
+// prefix, so the meta-tools of servers with different prefixes don't collide either
+func (s *QuayMCPServer) addMetaTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	tool.Name = s.quayClient.ToolPrefix() + strings.TrimPrefix(tool.Name, client.DefaultToolPrefix)
+	s.addTool(tool, handler)
+}
+
+// CallTool invokes a registered tool directly, without going through an MCP transport
+func (s *QuayMCPServer) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*mcp.CallToolResult, error) {
+	handler, ok := s.handlers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown tool: %s", name)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = arguments
+	return handler(ctx, request)
+}
+
+// Start initializes and starts the MCP server
+func (s *QuayMCPServer) Start() error {
+	if err := s.Initialize(); err != nil {
+		return err
+	}
+	if err := s.runSmokeTest(context.Background()); err != nil {
+		return err
+	}
+
+	if s.diagnosticsOutput != nil {
+		if err := s.WriteDiagnostics(s.diagnosticsOutput); err != nil {
+			slog.Error("Failed to write startup diagnostics", "error", err)
+		}
+	}
+
+	if s.healthPollInterval > 0 {
+		go s.pollRegistryHealth(context.Background(), s.healthPollInterval)
+	}
+
+	return s.serve()
+}
+
+// Initialize fetches the spec and registers all tools without starting a transport
+func (s *QuayMCPServer) Initialize() error {
+	if s.failClosed {
+		if err := s.quayClient.HealthCheck(context.Background()); err != nil {
+			var authErr *client.AuthError
+			if errors.As(err, &authErr) {
+				return fmt.Errorf("refusing to start with -fail-closed: %w", err)
+			}
+			s.warn("Startup health check failed: %v", err)
+		}
+	}
+
+	// Reuse the endpoints and tools of a fresh endpoint cache instead of rediscovering them
+	tools, cached := s.quayClient.LoadEndpointCache()
+	if !cached {
+		// Fetch swagger spec
+		if err := s.quayClient.FetchSwaggerSpec(); err != nil {
+			return fmt.Errorf("failed to fetch swagger spec: %v", err)
+		}
+
+		// Discover endpoints and generate their tools in a single walk of the spec
+		tools = s.quayClient.Discover()
+		if err := s.quayClient.SaveEndpointCache(tools); err != nil {
+			s.warn("%v", err)
+		}
+	}
+
+	// Add the tools
+	s.checkToolCount(len(tools))
+
+	s.registerTools(tools)
+
+	// Expose documented example responses when the spec provides any
+	if s.hasResponseExamples() {
+		s.addMetaTool(mcp.NewTool(responseExampleTool,
+			mcp.WithDescription("Returns the documented example response for a Quay tool without calling the API"),
+			mcp.WithString("tool",
+				mcp.Required(),
+				mcp.Description(fmt.Sprintf("Name of the tool to show an example response for (e.g. %slistRepos)", s.quayClient.ToolPrefix())),
+			),
+		), s.createResponseExampleHandler())
+	}
+
+	// Serve GET endpoints as resources too, for clients that browse rather than call tools
+	s.registerResources()
+
+	if s.metaTools {
+		// Let clients run several calls in one round trip
+		s.addMetaTool(newBatchTool(s.quayClient.ToolPrefix()), s.createBatchHandler())
+
+		// Let clients look up the headers of the last response without enveloping every result
+		s.addMetaTool(newLastHeadersTool(), s.createLastHeadersHandler())
+
+		// Report call counts and latencies for deployments without a metrics scraper
+		s.addMetaTool(newMetricsTool(), s.createMetricsHandler())
+
+		// Let clients enumerate the generated tools without relying on tools/list alone
+		s.addMetaTool(newListToolsTool(), s.createListToolsHandler())
+	}
+
+	// Allow comparing responses against a mirror registry when one is configured
+	if s.mirrorURL != "" {
+		s.addMetaTool(mcp.NewTool(compareTool,
+			mcp.WithDescription(fmt.Sprintf("Runs a Quay tool against both %s and the mirror %s and reports the differences between the JSON responses",
+				s.quayClient.GetRegistryURL(), s.mirrorURL)),
+			mcp.WithString("tool",
+				mcp.Required(),
+				mcp.Description(fmt.Sprintf("Name of the tool to run on both registries (e.g. %sgetRepo)", s.quayClient.ToolPrefix())),
+			),
+			mcp.WithObject("params",
+				mcp.Description("Arguments passed to the tool on both registries"),
+			),
+		), s.createCompareHandler())
 	}
 
-	// Start the server using stdio
-	return server.ServeStdio(s.mcpServer)
+	return nil
 }
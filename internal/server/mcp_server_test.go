@@ -0,0 +1,588 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/quay/quay-mcp-server/internal/client"
+)
+
+// testSpec is a minimal Swagger document used by the server tests
+const testSpec = `{
+	"swagger": "2.0",
+	"basePath": "/api/v1",
+	"paths": {
+		"/api/v1/repository": {
+			"get": {
+				"operationId": "listRepos",
+				"summary": "List repositories",
+				"tags": ["repository"],
+				"parameters": [
+					{"name": "namespace", "in": "query", "type": "string"}
+				],
+				"responses": {
+					"200": {
+						"description": "Successful invocation",
+						"examples": {"application/json": {"repositories": [{"name": "ubi8"}]}}
+					}
+				}
+			}
+		},
+		"/api/v1/repository/{repository}": {
+			"get": {
+				"operationId": "getRepo",
+				"summary": "Get repository",
+				"tags": ["repository"],
+				"parameters": [
+					{"name": "repository", "in": "path", "type": "string", "required": true}
+				]
+			}
+		}
+	}
+}`
+
+// newTestServer creates a server whose registry serves spec for discovery and delegates
+// all other requests to api
+func newTestServer(t *testing.T, spec string, api http.HandlerFunc) *QuayMCPServer {
+	t.Helper()
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/discovery" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(spec))
+			return
+		}
+		if api == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		api(w, r)
+	}))
+	t.Cleanup(mockServer.Close)
+
+	s := NewQuayMCPServer(mockServer.URL, "")
+	if err := s.quayClient.FetchSwaggerSpec(); err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	s.quayClient.DiscoverEndpoints()
+	return s
+}
+
+// callTool builds a tool call request and invokes handler with it
+func callTool(t *testing.T, handler func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error), name string, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+
+	result, err := handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("Expected no handler error, got %v", err)
+	}
+	return result
+}
+
+// captureLogs sends log records of every level to a buffer for the rest of the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var logs bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() {
+		slog.SetDefault(previous)
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+	})
+	return &logs
+}
+
+// resultText returns the text of the first content item of a tool result
+func resultText(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+
+	if len(result.Content) == 0 {
+		t.Fatal("Expected tool result content")
+	}
+	text, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		t.Fatalf("Expected text content, got %T", result.Content[0])
+	}
+	return text.Text
+}
+
+func TestResponseExampleTool(t *testing.T) {
+	s := newTestServer(t, testSpec, nil)
+
+	if !s.hasResponseExamples() {
+		t.Fatal("Expected the spec to provide response examples")
+	}
+
+	handler := s.createResponseExampleHandler()
+
+	result := callTool(t, handler, responseExampleTool, map[string]interface{}{"tool": "quay_listRepos"})
+	if result.IsError {
+		t.Fatalf("Expected success, got error: %s", resultText(t, result))
+	}
+	if text := resultText(t, result); !strings.Contains(text, `"ubi8"`) {
+		t.Errorf("Expected documented example in result, got %s", text)
+	}
+
+	result = callTool(t, handler, responseExampleTool, map[string]interface{}{"tool": "quay_getRepo"})
+	if text := resultText(t, result); !strings.Contains(text, "No example response") {
+		t.Errorf("Expected no-example message, got %s", text)
+	}
+}
+
+func TestResultAsResource(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"repositories": []}`))
+	})
+	handler := s.createToolHandler()
+
+	// Text remains the default
+	result := callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "redhat"})
+	if text := resultText(t, result); text != `{"repositories": []}` {
+		t.Errorf("Expected raw JSON text, got %s", text)
+	}
+
+	s.SetResultAsResource(true)
+	result = callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "redhat"})
+
+	var resource *mcp.EmbeddedResource
+	for _, content := range result.Content {
+		if r, ok := mcp.AsEmbeddedResource(content); ok {
+			resource = r
+		}
+	}
+	if resource == nil {
+		t.Fatal("Expected an embedded resource in the result")
+	}
+
+	contents, ok := mcp.AsTextResourceContents(resource.Resource)
+	if !ok {
+		t.Fatalf("Expected text resource contents, got %T", resource.Resource)
+	}
+	if contents.MIMEType != "application/json" {
+		t.Errorf("Expected MIME type 'application/json', got '%s'", contents.MIMEType)
+	}
+	if contents.Text != `{"repositories": []}` {
+		t.Errorf("Expected response body in resource, got %s", contents.Text)
+	}
+	if !strings.Contains(contents.URI, "/api/v1/repository?namespace=redhat") {
+		t.Errorf("Expected resource URI to be the request URL, got %s", contents.URI)
+	}
+}
+
+func TestToolCountWarning(t *testing.T) {
+	logs := captureLogs(t)
+
+	s := NewQuayMCPServer("https://quay.io", "")
+	s.SetToolCountThreshold(10)
+
+	if s.checkToolCount(10) {
+		t.Error("Expected no warning at the threshold")
+	}
+	if strings.Contains(logs.String(), "WARNING") {
+		t.Errorf("Expected no warning logged, got %q", logs.String())
+	}
+
+	if !s.checkToolCount(11) {
+		t.Error("Expected a warning above the threshold")
+	}
+	if !strings.Contains(logs.String(), "generated 11 tools") {
+		t.Errorf("Expected warning naming the tool count, got %q", logs.String())
+	}
+
+	s.SetToolCountThreshold(0)
+	if s.checkToolCount(1000) {
+		t.Error("Expected no warning when the threshold is disabled")
+	}
+}
+
+func TestAllowedNamespaces(t *testing.T) {
+	var requests int
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"repositories": []}`))
+	})
+	s.SetAllowedNamespaces([]string{"redhat"})
+	handler := s.createToolHandler()
+
+	result := callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "acme"})
+	if !result.IsError {
+		t.Errorf("Expected a disallowed namespace to be rejected, got %s", resultText(t, result))
+	}
+	result = callTool(t, handler, "quay_getRepo", map[string]interface{}{"repository": "acme/secret"})
+	if !result.IsError {
+		t.Errorf("Expected a repository in a disallowed namespace to be rejected, got %s", resultText(t, result))
+	}
+	if requests != 0 {
+		t.Fatalf("Expected no API requests for rejected calls, got %d", requests)
+	}
+
+	result = callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "redhat"})
+	if result.IsError {
+		t.Errorf("Expected an allowed namespace to pass, got %s", resultText(t, result))
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 API request for the allowed call, got %d", requests)
+	}
+}
+
+func TestAllowedNamespacesDefault(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+	s.quayClient.SetDefaultNamespace("acme")
+	s.SetAllowedNamespaces([]string{"redhat"})
+	handler := s.createToolHandler()
+
+	// The default namespace only stands in for a namespace parameter the endpoint declares
+	result := callTool(t, handler, "quay_listRepos", nil)
+	if !result.IsError {
+		t.Errorf("Expected the disallowed default namespace to be rejected, got %s", resultText(t, result))
+	}
+	result = callTool(t, handler, "quay_getRepo", map[string]interface{}{"repository": "redhat/ubi8"})
+	if result.IsError {
+		t.Errorf("Expected an endpoint without a namespace parameter to ignore the default, got %s", resultText(t, result))
+	}
+}
+
+func TestMissingPathParameters(t *testing.T) {
+	var requests int
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name": "ubi8"}`))
+	})
+	handler := s.createToolHandler()
+
+	for _, args := range []map[string]interface{}{nil, {"repository": ""}} {
+		result := callTool(t, handler, "quay_getRepo", args)
+		text, _ := mcp.AsTextContent(result.Content[0])
+		if !result.IsError || !strings.Contains(text.Text, "Missing required path parameters for quay_getRepo: repository") {
+			t.Errorf("Expected an error naming the missing parameter for %v, got %+v", args, result)
+		}
+	}
+	if requests != 0 {
+		t.Fatalf("Expected no API requests without the path parameter, got %d", requests)
+	}
+
+	if result := callTool(t, handler, "quay_getRepo", map[string]interface{}{"repository": "redhat/ubi8"}); result.IsError {
+		t.Errorf("Expected the call to succeed, got %s", resultText(t, result))
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 API request, got %d", requests)
+	}
+}
+
+func TestRepositoryListFilter(t *testing.T) {
+	var requests int
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"repositories": []}`))
+	})
+	handler := s.createToolHandler()
+
+	// Quay rejects a listing without namespace, public or starred, so no request is made
+	for _, args := range []map[string]interface{}{nil, {"namespace": ""}, {"public": false}} {
+		result := callTool(t, handler, "quay_listRepos", args)
+		if text := resultText(t, result); !result.IsError || !strings.Contains(text, "requires at least one of namespace, public, starred") {
+			t.Errorf("Expected an error naming the filters for %v, got %s", args, text)
+		}
+	}
+	if requests != 0 {
+		t.Fatalf("Expected no API requests without a filter, got %d", requests)
+	}
+
+	for _, args := range []map[string]interface{}{{"namespace": "redhat"}, {"public": true}, {"starred": "true"}} {
+		if result := callTool(t, handler, "quay_listRepos", args); result.IsError {
+			t.Errorf("Expected the call with %v to succeed, got %s", args, resultText(t, result))
+		}
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 API requests, got %d", requests)
+	}
+}
+
+func TestInitializeWithoutPaths(t *testing.T) {
+	s := newTestServer(t, `{"swagger": "2.0", "info": {"title": "Quay", "version": "v1"}}`, nil)
+	logs := captureLogs(t)
+
+	// A spec without a paths section serves only the meta-tools instead of panicking
+	if err := s.Initialize(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(s.tools) != 0 {
+		t.Errorf("Expected no generated tools, got %d", len(s.tools))
+	}
+	if !strings.Contains(logs.String(), "The Swagger model has no paths; no tools generated") {
+		t.Errorf("Expected a warning about the missing paths, got:\n%s", logs.String())
+	}
+}
+
+func TestFailClosed(t *testing.T) {
+	status := http.StatusUnauthorized
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/user/" {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"error": "invalid token"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	// Without -fail-closed an invalid token doesn't stop startup
+	if err := s.Initialize(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	s.SetFailClosed(true)
+	for _, rejected := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		status = rejected
+		err := s.Initialize()
+		var authErr *client.AuthError
+		if !errors.As(err, &authErr) || authErr.StatusCode != rejected {
+			t.Errorf("Expected startup to fail with status %d, got %v", rejected, err)
+		}
+	}
+
+	status = http.StatusOK
+	if err := s.Initialize(); err != nil {
+		t.Errorf("Expected accepted credentials to start, got %v", err)
+	}
+}
+
+func TestSummarizeListResponse(t *testing.T) {
+	body := `{"repositories": [{"name": "ubi8"}, {"name": "ubi9"}], "next_page": "abc"}`
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	})
+	s.SetSummarize(true)
+
+	result := callTool(t, s.createToolHandler(), "quay_listRepos", map[string]interface{}{"namespace": "redhat"})
+	if len(result.Content) != 2 {
+		t.Fatalf("Expected summary and JSON content, got %d items", len(result.Content))
+	}
+
+	summary, _ := mcp.AsTextContent(result.Content[0])
+	if summary == nil || !strings.HasPrefix(summary.Text, "Found 2 repositories in redhat") {
+		t.Errorf("Expected summary 'Found 2 repositories in redhat', got %+v", summary)
+	}
+	raw, _ := mcp.AsTextContent(result.Content[1])
+	if raw == nil || raw.Text != body {
+		t.Errorf("Expected raw JSON as second content, got %+v", raw)
+	}
+
+	if summary := summarizeResponse([]byte(`{"name": "ubi8"}`), nil); summary != "" {
+		t.Errorf("Expected no summary for a non-list response, got %q", summary)
+	}
+}
+
+func TestRequestIDHeader(t *testing.T) {
+	var received []string
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("X-Request-ID"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"repositories": []}`))
+	})
+	s.GetQuayClient().SetRequestIDHeader("X-Request-ID")
+	handler := s.createToolHandler()
+
+	logs := captureLogs(t)
+
+	// A generated ID is sent and matches the logged correlation ID
+	callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "redhat"})
+	if len(received) != 1 || received[0] == "" {
+		t.Fatalf("Expected a generated request ID header, got %v", received)
+	}
+	if !strings.Contains(logs.String(), "correlation_id="+received[0]) {
+		t.Errorf("Expected logged correlation ID to match header %q", received[0])
+	}
+
+	// A client-supplied ID is propagated as-is
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "quay_listRepos"
+	request.Params.Arguments = map[string]interface{}{"namespace": "redhat"}
+	request.Params.Meta = &mcp.Meta{AdditionalFields: map[string]any{"request_id": "trace-42"}}
+	if _, err := handler(context.Background(), request); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(received) != 2 || received[1] != "trace-42" {
+		t.Errorf("Expected client request ID 'trace-42', got %v", received)
+	}
+}
+
+func TestErrorIncludesDuration(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	})
+	handler := s.createToolHandler()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "quay_listRepos"
+	request.Params.Arguments = map[string]interface{}{"namespace": "redhat"}
+
+	result, err := handler(ctx, request)
+	if err != nil {
+		t.Fatalf("Expected no handler error, got %v", err)
+	}
+
+	text := resultText(t, result)
+	if !strings.Contains(text, "API call failed after ") || !strings.Contains(text, "ms") {
+		t.Errorf("Expected the elapsed duration in the error, got %s", text)
+	}
+	if !strings.Contains(text, "deadline exceeded") {
+		t.Errorf("Expected a timeout error, got %s", text)
+	}
+}
+
+func TestTagPrefixes(t *testing.T) {
+	s := newTestServer(t, `{
+		"swagger": "2.0",
+		"basePath": "/api/v1",
+		"paths": {
+			"/api/v1/repository": {
+				"get": {"operationId": "listRepos", "tags": ["repository"]}
+			},
+			"/api/v1/organization/{orgname}": {
+				"get": {"operationId": "getOrganization", "tags": ["organization"]}
+			},
+			"/api/v1/repository/{repository}/tag/": {
+				"get": {"operationId": "listRepoTags", "tags": ["tag"]}
+			}
+		}
+	}`, nil)
+	s.quayClient.SetTagPrefixes(map[string]string{"repository": "repo", "organization": "org"})
+
+	names := make(map[string]bool)
+	for _, tool := range s.quayClient.GenerateTools() {
+		names[tool.Name] = true
+	}
+	for _, want := range []string{"quay_repo_listRepos", "quay_org_getOrganization", "quay_listRepoTags"} {
+		if !names[want] {
+			t.Errorf("Expected tool %s, got %v", want, names)
+		}
+	}
+
+	for name, operationID := range map[string]string{
+		"quay_repo_listRepos":      "listRepos",
+		"quay_org_getOrganization": "getOrganization",
+		"quay_listRepoTags":        "listRepoTags",
+	} {
+		endpoint, err := s.findEndpoint(name)
+		if err != nil {
+			t.Errorf("Expected %s to resolve, got %v", name, err)
+			continue
+		}
+		if endpoint.OperationID != operationID {
+			t.Errorf("Expected %s to resolve to %s, got %s", name, operationID, endpoint.OperationID)
+		}
+	}
+
+	if _, err := s.findEndpoint("quay_listRepos"); err == nil {
+		t.Error("Expected the unprefixed name not to resolve once a prefix is configured")
+	}
+}
+
+func TestPathToolNames(t *testing.T) {
+	s := newTestServer(t, testSpec, nil)
+	if err := s.quayClient.SetToolNameSource("path"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	names := make(map[string]bool)
+	for _, tool := range s.quayClient.GenerateTools() {
+		names[tool.Name] = true
+	}
+	if len(names) != 2 || !names["quay_api_v1_repository"] || !names["quay_api_v1_repository_repository"] {
+		t.Errorf("Expected path-based tool names, got %v", names)
+	}
+
+	endpoint, err := s.findEndpoint("quay_api_v1_repository_repository")
+	if err != nil {
+		t.Fatalf("Expected the path-based name to resolve, got %v", err)
+	}
+	if endpoint.OperationID != "getRepo" {
+		t.Errorf("Expected getRepo, got %s", endpoint.OperationID)
+	}
+
+	if err := s.quayClient.SetToolNameSource("summary"); err == nil {
+		t.Error("Expected an error for an unknown source")
+	}
+}
+
+func TestToolPrefix(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name": "ubi8"}`))
+	})
+	if err := s.quayClient.SetToolPrefix("quay prod"); err == nil {
+		t.Error("Expected a prefix with a space to be rejected")
+	}
+	if err := s.quayClient.SetToolPrefix("prod_"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := s.Initialize(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, name := range []string{"prod_listRepos", "prod_getRepo", "prod_batch", "prod_list_tools"} {
+		if _, ok := s.handlers[name]; !ok {
+			t.Errorf("Expected tool %s to be registered", name)
+		}
+	}
+	if _, ok := s.handlers["quay_getRepo"]; ok {
+		t.Error("Expected no tool with the default prefix")
+	}
+
+	// The handler strips the configured prefix, not quay_
+	result, err := s.CallTool(context.Background(), "prod_getRepo", map[string]interface{}{"repository": "redhat/ubi8"})
+	if err != nil || result.IsError {
+		t.Fatalf("Expected the call to succeed, got %+v, %v", result, err)
+	}
+	if _, err := s.findEndpoint("quay_getRepo"); err == nil || !strings.Contains(err.Error(), "prod_") {
+		t.Errorf("Expected quay_getRepo to be rejected for lacking the prod_ prefix, got %v", err)
+	}
+}
+
+func TestMetaToolsDisabled(t *testing.T) {
+	s := newTestServer(t, testSpec, nil)
+	s.SetMetaTools(false)
+	if err := s.Initialize(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, name := range []string{batchTool, lastHeadersTool, metricsTool, listToolsTool} {
+		if _, ok := s.handlers[name]; ok {
+			t.Errorf("Expected meta-tool %s not to be registered", name)
+		}
+	}
+	if _, ok := s.handlers["quay_listRepos"]; !ok {
+		t.Error("Expected the generated tools to be registered")
+	}
+}
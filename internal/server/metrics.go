@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// metricsTool is the meta-tool that returns the collected call metrics as JSON
+const metricsTool = "quay_metrics"
+
+// latencySamples bounds how many recent latencies per tool the percentiles are computed from
+const latencySamples = 1024
+
+// callMetrics collects the count, failures and latency of the Quay API calls made by each tool
+type callMetrics struct {
+	mu    sync.Mutex
+	since time.Time
+	tools map[string]*toolMetrics
+}
+
+// toolMetrics are the metrics of one tool; latencies is a ring of the most recent call latencies
+type toolMetrics struct {
+	calls     int
+	errors    int
+	total     time.Duration
+	min, max  time.Duration
+	latencies []time.Duration
+	next      int
+}
+
+// MetricsSnapshot is the JSON form of the collected metrics
+type MetricsSnapshot struct {
+	Since  time.Time                      `json:"since"`
+	Calls  int                            `json:"calls"`
+	Errors int                            `json:"errors"`
+	Tools  map[string]ToolMetricsSnapshot `json:"tools"`
+}
+
+// ToolMetricsSnapshot summarizes the calls of one tool; latencies are in milliseconds, with the
+// percentiles taken over the most recent calls
+type ToolMetricsSnapshot struct {
+	Calls     int            `json:"calls"`
+	Errors    int            `json:"errors"`
+	LatencyMs LatencySummary `json:"latency_ms"`
+}
+
+// LatencySummary summarizes call latencies in milliseconds
+type LatencySummary struct {
+	Min  float64 `json:"min"`
+	Mean float64 `json:"mean"`
+	P50  float64 `json:"p50"`
+	P95  float64 `json:"p95"`
+	Max  float64 `json:"max"`
+}
+
+// recordCall adds a Quay API call made by a tool to the metrics
+func (m *callMetrics) recordCall(toolName string, elapsed time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.tools == nil {
+		m.tools = make(map[string]*toolMetrics)
+	}
+	tool, ok := m.tools[toolName]
+	if !ok {
+		tool = &toolMetrics{min: elapsed, max: elapsed}
+		m.tools[toolName] = tool
+	}
+
+	tool.calls++
+	if failed {
+		tool.errors++
+	}
+	tool.total += elapsed
+	tool.min = min(tool.min, elapsed)
+	tool.max = max(tool.max, elapsed)
+	if len(tool.latencies) < latencySamples {
+		tool.latencies = append(tool.latencies, elapsed)
+	} else {
+		tool.latencies[tool.next] = elapsed
+		tool.next = (tool.next + 1) % latencySamples
+	}
+}
+
+// Snapshot returns the metrics collected so far
+func (m *callMetrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := MetricsSnapshot{Since: m.since, Tools: make(map[string]ToolMetricsSnapshot, len(m.tools))}
+	for name, tool := range m.tools {
+		snapshot.Calls += tool.calls
+		snapshot.Errors += tool.errors
+		snapshot.Tools[name] = ToolMetricsSnapshot{
+			Calls:     tool.calls,
+			Errors:    tool.errors,
+			LatencyMs: tool.summary(),
+		}
+	}
+	return snapshot
+}
+
+// summary computes the latency summary of a tool
+func (t *toolMetrics) summary() LatencySummary {
+	sorted := append([]time.Duration(nil), t.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return LatencySummary{
+		Min:  milliseconds(t.min),
+		Mean: milliseconds(t.total / time.Duration(t.calls)),
+		P50:  milliseconds(percentile(sorted, 0.50)),
+		P95:  milliseconds(percentile(sorted, 0.95)),
+		Max:  milliseconds(t.max),
+	}
+}
+
+// percentile returns the nearest-rank percentile p of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
+
+// milliseconds converts a duration to milliseconds with microsecond precision
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// Metrics returns the call counts and latency summaries collected since the server started
+func (s *QuayMCPServer) Metrics() MetricsSnapshot {
+	return s.metrics.Snapshot()
+}
+
+// newMetricsTool describes the metrics meta-tool
+func newMetricsTool() mcp.Tool {
+	return mcp.NewTool(metricsTool,
+		mcp.WithDescription("Returns the number of Quay API calls made by each tool since the server started, how many failed, and their latency (min, mean, p50, p95 and max in milliseconds) as JSON"),
+	)
+}
+
+// createMetricsHandler creates the handler for the metrics meta-tool
+func (s *QuayMCPServer) createMetricsHandler() func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		encoded, err := json.MarshalIndent(s.Metrics(), "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to encode metrics: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(encoded)), nil
+	}
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.RawQuery, "namespace=broken") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"repositories": []}`))
+	})
+	handler := s.createToolHandler()
+
+	for _, namespace := range []string{"redhat", "quay", "broken"} {
+		callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": namespace})
+	}
+
+	var metrics MetricsSnapshot
+	text := resultText(t, callTool(t, s.createMetricsHandler(), metricsTool, nil))
+	if err := json.Unmarshal([]byte(text), &metrics); err != nil {
+		t.Fatalf("Expected JSON metrics, got %q: %v", text, err)
+	}
+
+	if metrics.Calls != 3 || metrics.Errors != 1 {
+		t.Errorf("Expected 3 calls with 1 error, got %d calls with %d errors", metrics.Calls, metrics.Errors)
+	}
+	listRepos, ok := metrics.Tools["quay_listRepos"]
+	if !ok || listRepos.Calls != 3 || listRepos.Errors != 1 {
+		t.Fatalf("Expected the listRepos calls, got %+v", metrics.Tools)
+	}
+	latency := listRepos.LatencyMs
+	if latency.Max <= 0 || latency.Min > latency.P50 || latency.P50 > latency.P95 || latency.P95 > latency.Max {
+		t.Errorf("Expected ordered latencies, got %+v", latency)
+	}
+	if metrics.Since.IsZero() {
+		t.Error("Expected the collection start time")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	if got := percentile(latencies, 0.50); got != 50*time.Millisecond {
+		t.Errorf("Expected a p50 of 50ms, got %s", got)
+	}
+	if got := percentile(latencies, 0.95); got != 95*time.Millisecond {
+		t.Errorf("Expected a p95 of 95ms, got %s", got)
+	}
+	if got := percentile(nil, 0.95); got != 0 {
+		t.Errorf("Expected 0 without samples, got %s", got)
+	}
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// paginationWarningKey is added to auto-paginated results when a later page failed
+const paginationWarningKey = "pagination_warning"
+
+// SetPaginationCursor enables reshaping list responses into {"items": [...], "cursor": "..."}
+func (s *QuayMCPServer) SetPaginationCursor(enabled bool) {
+	s.paginationCursor = enabled
+}
+
+// SetAutoPaginate makes list calls follow next_page, merging up to maxPages pages into a single
+// result (0 or 1 disables it)
+func (s *QuayMCPServer) SetAutoPaginate(maxPages int) {
+	s.autoPaginate = maxPages
+}
+
+// fetchRemainingPages follows next_page from a first list response, appending the items of each
+// further page to the first page's item array. When a page fails, the items gathered so far are
+// returned with a pagination_warning instead of discarding them. Responses that aren't paginated
+// lists are returned unchanged.
+func (s *QuayMCPServer) fetchRemainingPages(ctx context.Context, endpoint *types.EndpointInfo, arguments map[string]interface{}, first []byte) []byte {
+	var merged map[string]interface{}
+	if err := decodeJSON(first, &merged); err != nil {
+		return first
+	}
+	key, items, found := itemArray(merged)
+	next, _ := merged["next_page"].(string)
+	if !found || next == "" {
+		return first
+	}
+
+	page := 2
+	for ; next != "" && page <= s.autoPaginate; page++ {
+		pageArgs := make(map[string]interface{}, len(arguments)+1)
+		for name, value := range arguments {
+			pageArgs[name] = value
+		}
+		pageArgs["next_page"] = next
+
+		var parsed map[string]interface{}
+		response, err := s.quayClient.CallAPI(ctx, endpoint, pageArgs)
+		if err == nil {
+			err = decodeJSON(response.Body, &parsed)
+		}
+		if err != nil {
+			slog.Warn("Auto-pagination stopped", "method", endpoint.Method, "path", endpoint.Path, "page", page, "error", err)
+			merged[paginationWarningKey] = fmt.Sprintf("pagination incomplete: page %d failed, returning %d items from %d pages: %v", page, len(items), page-1, err)
+			break
+		}
+
+		pageItems, _ := parsed[key].([]interface{})
+		items = append(items, pageItems...)
+		next, _ = parsed["next_page"].(string)
+	}
+	slog.Info("Auto-paginated", "method", endpoint.Method, "path", endpoint.Path, "items", len(items), "pages", page-1)
+
+	merged[key] = items
+	if next == "" {
+		delete(merged, "next_page")
+	} else {
+		merged["next_page"] = next
+	}
+
+	encoded, err := json.Marshal(merged)
+	if err != nil {
+		return first
+	}
+	return encoded
+}
+
+// cursorPage is the uniform shape of a list response: its items and the token for the next page,
+// which is empty on the last page
+type cursorPage struct {
+	Items  []interface{} `json:"items"`
+	Cursor string        `json:"cursor"`
+}
+
+// cursorResponse reshapes a list response into a cursorPage, using the largest top-level array as
+// the items and next_page as the cursor. Responses that aren't list-shaped are returned unchanged.
+func cursorResponse(responseData []byte) []byte {
+	var parsed map[string]interface{}
+	if err := decodeJSON(responseData, &parsed); err != nil {
+		return responseData
+	}
+
+	_, items, found := itemArray(parsed)
+	if !found {
+		return responseData
+	}
+
+	page := cursorPage{Items: items}
+	if next, ok := parsed["next_page"].(string); ok {
+		page.Cursor = next
+	}
+
+	reshaped, err := json.Marshal(page)
+	if err != nil {
+		return responseData
+	}
+	return reshaped
+}
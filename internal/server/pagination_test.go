@@ -0,0 +1,59 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCursorResponse(t *testing.T) {
+	paginated := []byte(`{"repositories": [{"name": "ubi8"}, {"name": "ubi9"}], "next_page": "gAAAAABk"}`)
+	if got := string(cursorResponse(paginated)); got != `{"items":[{"name":"ubi8"},{"name":"ubi9"}],"cursor":"gAAAAABk"}` {
+		t.Errorf("Unexpected cursor page %s", got)
+	}
+
+	lastPage := []byte(`{"tags": [{"name": "latest"}], "page": 3, "has_additional": false}`)
+	if got := string(cursorResponse(lastPage)); got != `{"items":[{"name":"latest"}],"cursor":""}` {
+		t.Errorf("Unexpected cursor page %s", got)
+	}
+
+	single := []byte(`{"name": "ubi8", "is_public": true}`)
+	if got := string(cursorResponse(single)); got != string(single) {
+		t.Errorf("Expected a non-list response to be unchanged, got %s", got)
+	}
+}
+
+func TestAutoPaginatePartialResults(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("next_page") {
+		case "":
+			w.Write([]byte(`{"repositories": [{"name": "ubi8"}, {"name": "ubi9"}], "next_page": "page2"}`))
+		case "page2":
+			w.Write([]byte(`{"repositories": [{"name": "ubi10"}], "next_page": "page3"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error": "backend unavailable"}`))
+		}
+	})
+	s.SetAutoPaginate(5)
+	handler := s.createToolHandler()
+
+	text := resultText(t, callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "redhat"}))
+
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		t.Fatalf("Expected a JSON result, got %s", text)
+	}
+	if repos, _ := result["repositories"].([]interface{}); len(repos) != 3 {
+		t.Errorf("Expected the 3 repositories from the first 2 pages, got %v", result["repositories"])
+	}
+	warning, _ := result[paginationWarningKey].(string)
+	if !strings.Contains(warning, "page 3 failed") || !strings.Contains(warning, "500") {
+		t.Errorf("Expected a warning naming the failed page, got %q", warning)
+	}
+	if result["next_page"] != "page3" {
+		t.Errorf("Expected the failed page's token to resume from, got %v", result["next_page"])
+	}
+}
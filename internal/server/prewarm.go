@@ -0,0 +1,54 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/quay/quay-mcp-server/internal/client"
+)
+
+// CheckToolGeneration loads the spec, generates the tools and returns every violated invariant:
+// tool names must be unique, each tool must resolve back to the endpoint it was generated from and
+// every path parameter must be a required input. With a spec file set it runs entirely offline.
+func (s *QuayMCPServer) CheckToolGeneration() ([]string, error) {
+	if err := s.quayClient.FetchSwaggerSpec(); err != nil {
+		return nil, fmt.Errorf("failed to load swagger spec: %v", err)
+	}
+	tools := s.quayClient.Discover()
+
+	var problems []string
+	counts := make(map[string]int)
+	for _, tool := range tools {
+		counts[tool.Name]++
+	}
+	for name, count := range counts {
+		if count > 1 {
+			problems = append(problems, fmt.Sprintf("tool name %s is generated %d times", name, count))
+		}
+	}
+
+	for _, tool := range tools {
+		if counts[tool.Name] > 1 {
+			continue // resolution is ambiguous, already reported as a collision
+		}
+
+		endpoint, err := s.findEndpoint(tool.Name)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("tool %s does not resolve to an endpoint: %v", tool.Name, err))
+			continue
+		}
+
+		required := make(map[string]bool, len(tool.InputSchema.Required))
+		for _, name := range tool.InputSchema.Required {
+			required[name] = true
+		}
+		for _, param := range client.PathParameterNames(endpoint.Path) {
+			if _, declared := tool.InputSchema.Properties[param]; !declared || !required[param] {
+				problems = append(problems, fmt.Sprintf("tool %s does not require path parameter %s", tool.Name, param))
+			}
+		}
+	}
+
+	sort.Strings(problems)
+	return problems, nil
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckToolGeneration(t *testing.T) {
+	dir := t.TempDir()
+
+	good := filepath.Join(dir, "good.json")
+	if err := os.WriteFile(good, []byte(testSpec), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two operations share an operation ID, so their tool names collide
+	broken := filepath.Join(dir, "broken.json")
+	if err := os.WriteFile(broken, []byte(`{
+		"swagger": "2.0",
+		"paths": {
+			"/api/v1/repository": {
+				"get": {"operationId": "listRepos", "tags": ["repository"]}
+			},
+			"/api/v1/organization/{orgname}/repository": {
+				"get": {"operationId": "listRepos", "tags": ["organization"]}
+			}
+		}
+	}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewQuayMCPServer("", "")
+	s.quayClient.SetSpecFile(good)
+	problems, err := s.CheckToolGeneration()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("Expected no problems for the good spec, got %v", problems)
+	}
+
+	s = NewQuayMCPServer("", "")
+	s.quayClient.SetSpecFile(broken)
+	problems, err = s.CheckToolGeneration()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(problems) != 1 || !strings.Contains(problems[0], "quay_listRepos is generated 2 times") {
+		t.Errorf("Expected a name collision, got %v", problems)
+	}
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// truncatedPlaceholder replaces objects and arrays nested deeper than the maximum depth
+var truncatedPlaceholder = map[string]interface{}{"...": "truncated"}
+
+// SetResponseMaxDepth prunes JSON responses to this many levels of nested objects and arrays,
+// replacing deeper ones with {"...": "truncated"} (0 disables pruning)
+func (s *QuayMCPServer) SetResponseMaxDepth(depth int) {
+	s.responseMaxDepth = depth
+}
+
+// pruneJSON replaces the objects and arrays of a JSON document nested more than maxDepth levels
+// deep, counting the top-level value as the first level. Scalars are kept at any depth.
+func pruneJSON(data []byte, maxDepth int) ([]byte, error) {
+	var parsed interface{}
+	if err := decodeJSON(data, &parsed); err != nil {
+		return nil, fmt.Errorf("response is not valid JSON: %v", err)
+	}
+	return json.Marshal(pruneValue(parsed, 1, maxDepth))
+}
+
+// pruneValue returns value, found at the given depth, with containers beyond maxDepth replaced
+func pruneValue(value interface{}, depth, maxDepth int) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if depth > maxDepth {
+			return truncatedPlaceholder
+		}
+		for key, field := range v {
+			v[key] = pruneValue(field, depth+1, maxDepth)
+		}
+	case []interface{}:
+		if depth > maxDepth {
+			return truncatedPlaceholder
+		}
+		for i, item := range v {
+			v[i] = pruneValue(item, depth+1, maxDepth)
+		}
+	}
+	return value
+}
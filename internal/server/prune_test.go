@@ -0,0 +1,45 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestPruneJSON(t *testing.T) {
+	data := []byte(`{
+		"name": "ubi8",
+		"tags": [{"name": "latest", "labels": {"arch": "amd64"}}],
+		"namespace": {"name": "redhat", "owner": {"kind": "org"}}
+	}`)
+
+	pruned, err := pruneJSON(data, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := `{"name":"ubi8","namespace":{"name":"redhat","owner":{"...":"truncated"}},"tags":[{"...":"truncated"}]}`
+	if string(pruned) != want {
+		t.Errorf("Expected %s, got %s", want, pruned)
+	}
+
+	// A document within the depth is unchanged apart from formatting
+	if pruned, _ := pruneJSON(data, 3); string(pruned) != `{"name":"ubi8","namespace":{"name":"redhat","owner":{"kind":"org"}},"tags":[{"labels":{"...":"truncated"},"name":"latest"}]}` {
+		t.Errorf("Unexpected pruning at depth 3: %s", pruned)
+	}
+	if _, err := pruneJSON([]byte("not json"), 2); err == nil {
+		t.Error("Expected an error for invalid JSON")
+	}
+}
+
+func TestResponseMaxDepth(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"repositories": [{"name": "ubi8", "state": {"mirror": {"status": "ok"}}}]}`))
+	})
+	s.SetResponseMaxDepth(2)
+	handler := s.createToolHandler()
+
+	text := resultText(t, callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "redhat"}))
+	if want := `{"repositories":[{"...":"truncated"}]}`; text != want {
+		t.Errorf("Expected %s, got %s", want, text)
+	}
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/quay/quay-mcp-server/internal/client"
+)
+
+// readiness tracks whether the registry answered the latest health poll
+type readiness struct {
+	mu     sync.Mutex
+	failed bool   // the latest poll couldn't reach the registry
+	reason string // why, for the /readyz response
+}
+
+// SetRegistryHealthPoll makes Start poll the registry at this interval and report not-ready on
+// /readyz while it is unreachable (0 disables polling, so /readyz is always ready)
+func (s *QuayMCPServer) SetRegistryHealthPoll(interval time.Duration) {
+	s.healthPollInterval = interval
+}
+
+// checkRegistryHealth polls the registry once and updates the readiness. A registry that rejects
+// the credentials still answers, so it counts as reachable.
+func (s *QuayMCPServer) checkRegistryHealth(ctx context.Context) {
+	err := s.quayClient.HealthCheck(ctx)
+	var authErr *client.AuthError
+	if errors.As(err, &authErr) {
+		err = nil
+	}
+
+	s.readiness.mu.Lock()
+	defer s.readiness.mu.Unlock()
+
+	switch {
+	case err != nil && !s.readiness.failed:
+		slog.Warn("Registry became unreachable, reporting not ready", "error", err)
+	case err == nil && s.readiness.failed:
+		slog.Info("Registry is reachable again, reporting ready")
+	}
+	s.readiness.failed = err != nil
+	s.readiness.reason = ""
+	if err != nil {
+		s.readiness.reason = err.Error()
+	}
+}
+
+// pollRegistryHealth checks the registry every interval until ctx is done
+func (s *QuayMCPServer) pollRegistryHealth(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkRegistryHealth(ctx)
+		}
+	}
+}
+
+// handleReadyz reports 200 while the registry is reachable and 503 with the reason otherwise
+func (s *QuayMCPServer) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	s.readiness.mu.Lock()
+	failed, reason := s.readiness.failed, s.readiness.reason
+	s.readiness.mu.Unlock()
+
+	if failed {
+		http.Error(w, fmt.Sprintf("not ready: %s", reason), http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(w, "ready")
+}
+
+// handleHealthz reports that the process is alive, regardless of the registry
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintln(w, "ok")
+}
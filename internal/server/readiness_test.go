@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// readyzStatus returns the status /readyz currently answers with
+func readyzStatus(s *QuayMCPServer) int {
+	rec := httptest.NewRecorder()
+	s.handleReadyz(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	return rec.Code
+}
+
+func TestRegistryHealthPoll(t *testing.T) {
+	var down atomic.Bool
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"username": "quay"}`))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.pollRegistryHealth(ctx, 10*time.Millisecond)
+
+	// waitForReadyz polls /readyz until it answers the wanted status
+	waitForReadyz := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			got := readyzStatus(s)
+			if got == want {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected /readyz to answer %d, still %d", want, got)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	waitForReadyz(http.StatusOK)
+	down.Store(true)
+	waitForReadyz(http.StatusServiceUnavailable)
+	down.Store(false)
+	waitForReadyz(http.StatusOK)
+
+	// Liveness doesn't depend on the registry
+	down.Store(true)
+	waitForReadyz(http.StatusServiceUnavailable)
+	rec := httptest.NewRecorder()
+	handleHealthz(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected /healthz to answer 200 while the registry is down, got %d", rec.Code)
+	}
+}
+
+func TestReadinessIgnoresRejectedCredentials(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	s.checkRegistryHealth(context.Background())
+	if s.readiness.failed {
+		t.Errorf("Expected a registry rejecting the token to count as reachable, got %q", s.readiness.reason)
+	}
+}
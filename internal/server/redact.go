@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+var (
+	// bearerPattern matches bearer credentials, e.g. an Authorization header echoed in an error
+	bearerPattern = regexp.MustCompile(`(?i)\b(bearer)\s+[A-Za-z0-9\-._~+/]+=*`)
+
+	// basicPattern matches what may be basic credentials; only values that decode to
+	// "user:password" are redacted, so prose such as "basic auth" is left alone
+	basicPattern = regexp.MustCompile(`(?i)\b(basic)\s+([A-Za-z0-9+/]+={0,2})`)
+
+	// secretParamPattern matches the value of query parameters that commonly carry secrets
+	secretParamPattern = regexp.MustCompile(`(?i)([?&](?:access_token|oauth_token|refresh_token|id_token|token|client_secret|secret|password|passwd|api_key|apikey|code)=)[^&#\s"'<>]+`)
+)
+
+// redact scrubs bearer tokens, basic credentials and secret-looking query parameters from text sent to the client,
+// such as an error echoing the URL or headers of a failed request
+func redact(text string) string {
+	text = bearerPattern.ReplaceAllString(text, "$1 [REDACTED]")
+	text = basicPattern.ReplaceAllStringFunc(text, func(match string) string {
+		groups := basicPattern.FindStringSubmatch(match)
+		if decoded, err := base64.StdEncoding.DecodeString(groups[2]); err != nil || !strings.Contains(string(decoded), ":") {
+			return match
+		}
+		return groups[1] + " [REDACTED]"
+	})
+	return secretParamPattern.ReplaceAllString(text, "${1}[REDACTED]")
+}
+
+// minRedactedTokenLength is the length from which the configured token itself is redacted wherever
+// it appears; shorter values would redact ordinary words
+const minRedactedTokenLength = 8
+
+// redactCredentials redacts text like redact and also scrubs the credentials this server was
+// configured with, which the generic patterns don't recognize: the value of a custom auth header
+// (-auth header) and the token itself
+func (s *QuayMCPServer) redactCredentials(text string) string {
+	text = redact(text)
+	if header := s.quayClient.AuthHeaderName(); header != "" {
+		headerPattern := regexp.MustCompile(`(?i)(\b` + regexp.QuoteMeta(header) + `"?\s*[:=]\s*\[?"?)[^\s"',;&\]]+`)
+		text = headerPattern.ReplaceAllString(text, "${1}[REDACTED]")
+	}
+	if token := s.quayClient.Token(); len(token) >= minRedactedTokenLength {
+		text = strings.ReplaceAll(text, token, "[REDACTED]")
+	}
+	return text
+}
+
+// redactError returns err with its message redacted
+func (s *QuayMCPServer) redactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	message := s.redactCredentials(err.Error())
+	if message == err.Error() {
+		return err
+	}
+	return errors.New(message)
+}
+
+// redactingHandler wraps a tool handler so the text of its results, including JSON embedded as a
+// resource by -result-as-resource, and its errors is redacted
+func (s *QuayMCPServer) redactingHandler(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, request)
+		if result != nil {
+			for i, content := range result.Content {
+				switch content := content.(type) {
+				case mcp.TextContent:
+					content.Text = s.redactCredentials(content.Text)
+					result.Content[i] = content
+				case mcp.EmbeddedResource:
+					if text, ok := content.Resource.(mcp.TextResourceContents); ok {
+						text.Text = s.redactCredentials(text.Text)
+						content.Resource = text
+						result.Content[i] = content
+					}
+				}
+			}
+		}
+		return result, s.redactError(err)
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/quay/quay-mcp-server/internal/client"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"Authorization: Bearer abc.DEF-123", "Authorization: Bearer [REDACTED]"},
+		{"authorization: bearer abc123==", "authorization: bearer [REDACTED]"},
+		{"GET https://quay.io/api?access_token=s3cr3t&page=2", "GET https://quay.io/api?access_token=[REDACTED]&page=2"},
+		{`"next": "/x?page=2&Password=hunter2"`, `"next": "/x?page=2&Password=[REDACTED]"`},
+		{"GET https://quay.io/api/v1/repository?namespace=redhat", "GET https://quay.io/api/v1/repository?namespace=redhat"},
+		{"Bearer [REDACTED]", "Bearer [REDACTED]"},
+		{"Authorization: Basic cm9ib3Q6czNjcjN0", "Authorization: Basic [REDACTED]"},
+		{"the proxy requires basic auth", "the proxy requires basic auth"},
+	}
+	for _, tt := range tests {
+		if got := redact(tt.input); got != tt.want {
+			t.Errorf("redact(%q) = %q, expected %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestToolResultsAreRedacted(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "bad request to /api/v1/repository?token=s3cr3t with Bearer abc123"}`))
+	})
+	s.registerTools(s.quayClient.GenerateTools())
+
+	result, err := s.CallTool(context.Background(), "quay_listRepos", map[string]interface{}{"namespace": "redhat"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	text := ResultText(result)
+	if strings.Contains(text, "s3cr3t") || strings.Contains(text, "abc123") {
+		t.Errorf("Expected the secrets to be redacted, got %s", text)
+	}
+	if !strings.Contains(text, "token=[REDACTED]") {
+		t.Errorf("Expected the redaction to be visible, got %s", text)
+	}
+}
+
+func TestEmbeddedResourcesAreRedacted(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"repositories": [], "next": "/api/v1/repository?page=2&token=s3cr3t"}`))
+	})
+	s.SetResultAsResource(true)
+	s.registerTools(s.quayClient.GenerateTools())
+
+	result, err := s.CallTool(context.Background(), "quay_listRepos", map[string]interface{}{"namespace": "redhat"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var resource *mcp.EmbeddedResource
+	for _, content := range result.Content {
+		if r, ok := mcp.AsEmbeddedResource(content); ok {
+			resource = r
+		}
+	}
+	if resource == nil {
+		t.Fatal("Expected an embedded resource in the result")
+	}
+	contents, ok := mcp.AsTextResourceContents(resource.Resource)
+	if !ok {
+		t.Fatalf("Expected text resource contents, got %T", resource.Resource)
+	}
+	if strings.Contains(contents.Text, "s3cr3t") || !strings.Contains(contents.Text, "token=[REDACTED]") {
+		t.Errorf("Expected the embedded JSON to be redacted, got %s", contents.Text)
+	}
+}
+
+func TestConfiguredCredentialsAreRedacted(t *testing.T) {
+	const token = "configured-t0ken"
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "rejected X-Registry-Key: other-s3cr3t", "headers": {"x-registry-key": "` + r.Header.Get("X-Registry-Key") + `"}}`))
+	})
+	s.quayClient = s.quayClient.ForRegistry(s.quayClient.GetRegistryURL(), token)
+	if err := s.quayClient.SetAuth(client.AuthHeader, "", "x-registry-key"); err != nil {
+		t.Fatal(err)
+	}
+	s.registerTools(s.quayClient.GenerateTools())
+
+	result, err := s.CallTool(context.Background(), "quay_listRepos", map[string]interface{}{"namespace": "redhat"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	text := ResultText(result)
+	if strings.Contains(text, "other-s3cr3t") {
+		t.Errorf("Expected the custom auth header value to be redacted, got %s", text)
+	}
+	if strings.Contains(text, token) {
+		t.Errorf("Expected the configured token to be redacted, got %s", text)
+	}
+	if !strings.Contains(text, "X-Registry-Key: [REDACTED]") {
+		t.Errorf("Expected the redaction to be visible, got %s", text)
+	}
+
+	// Short tokens aren't redacted on their own, as they would match ordinary words
+	s.quayClient = s.quayClient.ForRegistry(s.quayClient.GetRegistryURL(), "error")
+	if got := s.redactCredentials("error"); got != "error" {
+		t.Errorf("Expected a short token to be left alone, got %q", got)
+	}
+}
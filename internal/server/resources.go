@@ -0,0 +1,187 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/quay/quay-mcp-server/internal/client"
+	"github.com/quay/quay-mcp-server/internal/types"
+)
+
+// generateResourcesAndTemplates describes the discovered GET endpoints as MCP resources: endpoints
+// without path parameters become resources and parameterized ones become resource templates,
+// both sorted by URI
+func (s *QuayMCPServer) generateResourcesAndTemplates() ([]mcp.Resource, []mcp.ResourceTemplate) {
+	var resources []mcp.Resource
+	var templates []mcp.ResourceTemplate
+
+	for _, endpoint := range s.resourceEndpoints() {
+		uri := client.ResourceURI(endpoint.Path)
+		name, description := resourceNameAndDescription(endpoint)
+
+		if !s.quayClient.HasPathParameters(endpoint.Path) {
+			resources = append(resources, mcp.NewResource(uri, name,
+				mcp.WithResourceDescription(description),
+				mcp.WithMIMEType("application/json"),
+			))
+			continue
+		}
+
+		template, err := newResourceTemplate(uri, name, description)
+		if err != nil {
+			slog.Warn("Skipping resource template", "uri", uri, "error", err)
+			continue
+		}
+		templates = append(templates, template)
+	}
+	return resources, templates
+}
+
+// resourceEndpoints returns the discovered GET endpoints, sorted by path
+func (s *QuayMCPServer) resourceEndpoints() []*types.EndpointInfo {
+	var endpoints []*types.EndpointInfo
+	for _, endpoint := range s.quayClient.GetEndpoints() {
+		if endpoint.Method == http.MethodGet {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Path < endpoints[j].Path
+	})
+	return endpoints
+}
+
+// resourceNameAndDescription names a resource after its endpoint's summary, falling back to the
+// operation ID or path
+func resourceNameAndDescription(endpoint *types.EndpointInfo) (string, string) {
+	name := endpoint.Summary
+	if name == "" {
+		name = endpoint.OperationID
+	}
+	if name == "" {
+		name = fmt.Sprintf("%s %s", endpoint.Method, endpoint.Path)
+	}
+
+	description := fmt.Sprintf("Endpoint: %s %s", endpoint.Method, endpoint.Path)
+	if len(endpoint.Tags) > 0 {
+		description += fmt.Sprintf("\nTags: %s", strings.Join(endpoint.Tags, ", "))
+	}
+	return name, description
+}
+
+// newResourceTemplate builds a resource template, returning an error instead of panicking when a
+// spec path isn't a valid URI template
+func newResourceTemplate(uri, name, description string) (template mcp.ResourceTemplate, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid URI template: %v", r)
+		}
+	}()
+	return mcp.NewResourceTemplate(uri, name,
+		mcp.WithTemplateDescription(description),
+		mcp.WithTemplateMIMEType("application/json"),
+	), nil
+}
+
+// WriteResources writes the resources and resource templates generated from the discovered
+// endpoints, one per line with URI, name and description, under a heading with their count
+func (s *QuayMCPServer) WriteResources(w io.Writer) error {
+	resources, templates := s.generateResourcesAndTemplates()
+
+	fmt.Fprintf(w, "Resources (%d)\n", len(resources))
+	for _, resource := range resources {
+		if err := writeResourceLine(w, resource.URI, resource.Name, resource.Description); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(w, "\nResource templates (%d)\n", len(templates))
+	for _, template := range templates {
+		if err := writeResourceLine(w, template.URITemplate.Raw(), template.Name, template.Description); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeResourceLine writes one resource with its multi-line description joined on a single line
+func writeResourceLine(w io.Writer, uri, name, description string) error {
+	_, err := fmt.Fprintf(w, "  %s  %s  (%s)\n", uri, name, strings.ReplaceAll(description, "\n", "; "))
+	return err
+}
+
+// registerResources serves the discovered GET endpoints as MCP resources and resource templates
+func (s *QuayMCPServer) registerResources() {
+	resources, templates := s.generateResourcesAndTemplates()
+	endpoints := make(map[string]*types.EndpointInfo)
+	for _, endpoint := range s.resourceEndpoints() {
+		endpoints[client.ResourceURI(endpoint.Path)] = endpoint
+	}
+
+	for _, resource := range resources {
+		s.mcpServer.AddResource(resource, s.createResourceHandler(endpoints[resource.URI]))
+	}
+	for _, template := range templates {
+		s.mcpServer.AddResourceTemplate(template, server.ResourceTemplateHandlerFunc(s.createResourceHandler(endpoints[template.URITemplate.Raw()])))
+	}
+	slog.Info("Registered resources", "resources", len(resources), "templates", len(templates))
+}
+
+// createResourceHandler creates the handler reading a resource or resource template, passing the
+// variables matched in a template URI as path parameters
+func (s *QuayMCPServer) createResourceHandler(endpoint *types.EndpointInfo) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		params := make(map[string]interface{}, len(request.Params.Arguments))
+		for name, value := range request.Params.Arguments {
+			params[name] = templateValue(value)
+		}
+		if err := s.checkNamespace(endpoint, params); err != nil {
+			return nil, err
+		}
+
+		ctx, cancel := s.callContext(ctx, endpoint)
+		defer cancel()
+
+		slog.Info("Reading resource", "uri", request.Params.URI, "method", endpoint.Method, "path", endpoint.Path)
+		response, err := s.quayClient.CallAPI(ctx, endpoint, params)
+		if err != nil {
+			return nil, s.redactError(err)
+		}
+
+		mimeType := response.Header.Get("Content-Type")
+		if mimeType == "" {
+			mimeType = "application/json"
+		}
+		if !utf8.Valid(response.Body) {
+			return []mcp.ResourceContents{mcp.BlobResourceContents{
+				URI:      request.Params.URI,
+				MIMEType: mimeType,
+				Blob:     base64.StdEncoding.EncodeToString(response.Body),
+			}}, nil
+		}
+		return []mcp.ResourceContents{mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: mimeType,
+			Text:     string(response.Body),
+		}}, nil
+	}
+}
+
+// templateValue converts a matched URI template variable, which may hold several values, to a
+// parameter value
+func templateValue(value interface{}) interface{} {
+	if values, ok := value.([]string); ok {
+		return strings.Join(values, ",")
+	}
+	return value
+}
@@ -0,0 +1,145 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// resourceSpec has both parameterized and non-parameterized GET endpoints
+const resourceSpec = `{
+	"swagger": "2.0",
+	"paths": {
+		"/api/v1/organization": {
+			"get": {"operationId": "listOrganizations", "summary": "List organizations", "tags": ["organization"]}
+		},
+		"/api/v1/repository": {
+			"get": {"operationId": "listRepos", "summary": "List repositories", "tags": ["repository"]},
+			"post": {"operationId": "createRepo", "summary": "Create repository", "tags": ["repository"]}
+		},
+		"/api/v1/repository/{namespace}/{repository}": {
+			"get": {"operationId": "getRepository", "summary": "Get repository", "tags": ["repository"]}
+		},
+		"/api/v1/repository/{repopath:.*}/tag/": {
+			"get": {"operationId": "listTags", "tags": ["tag"]}
+		}
+	}
+}`
+
+func TestGenerateResourcesAndTemplates(t *testing.T) {
+	s := newTestServer(t, resourceSpec, nil)
+
+	resources, templates := s.generateResourcesAndTemplates()
+
+	// Non-parameterized GET endpoints become resources
+	var uris []string
+	for _, resource := range resources {
+		uris = append(uris, resource.URI)
+	}
+	if strings.Join(uris, " ") != "quay://api/v1/organization quay://api/v1/repository" {
+		t.Errorf("Expected the two static GET endpoints as resources, got %v", uris)
+	}
+
+	// Parameterized ones become templates, with placeholder constraints dropped
+	names := make(map[string]string)
+	for _, template := range templates {
+		names[template.URITemplate.Raw()] = template.Name
+	}
+	if len(names) != 2 {
+		t.Errorf("Expected 2 resource templates, got %v", names)
+	}
+	if got := names["quay://api/v1/repository/{namespace}/{repository}"]; got != "Get repository" {
+		t.Errorf("Expected template name 'Get repository', got %q", got)
+	}
+	if got := names["quay://api/v1/repository/{repopath}/tag/"]; got != "listTags" {
+		t.Errorf("Expected the operation ID to name a template without a summary, got %q", got)
+	}
+}
+
+func TestReadResourceTemplate(t *testing.T) {
+	var requested string
+	s := newTestServer(t, resourceSpec, func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name": "ubi9"}`))
+	})
+	s.registerResources()
+
+	message := `{"jsonrpc": "2.0", "id": 1, "method": "resources/read", "params": {"uri": "quay://api/v1/repository/redhat/ubi9"}}`
+	response := s.mcpServer.HandleMessage(context.Background(), json.RawMessage(message))
+
+	encoded, _ := json.Marshal(response)
+	var decoded struct {
+		Result struct {
+			Contents []mcp.TextResourceContents `json:"contents"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Expected a read result, got %s: %v", encoded, err)
+	}
+	if requested != "/api/v1/repository/redhat/ubi9" {
+		t.Errorf("Expected the template variables as path parameters, got request for %q", requested)
+	}
+	if len(decoded.Result.Contents) != 1 {
+		t.Fatalf("Expected one content item, got %s", encoded)
+	}
+	contents := decoded.Result.Contents[0]
+	if contents.Text != `{"name": "ubi9"}` || contents.URI != "quay://api/v1/repository/redhat/ubi9" {
+		t.Errorf("Expected the response body as text contents, got %s", encoded)
+	}
+}
+
+func TestReadResourceMappedNamespace(t *testing.T) {
+	var requests int
+	s := newTestServer(t, orgSpec, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{}`))
+	})
+	if err := s.quayClient.SetParamMapping(map[string]string{"orgname": "org"}); err != nil {
+		t.Fatalf("Expected a valid mapping, got %v", err)
+	}
+	s.SetAllowedNamespaces([]string{"redhat"})
+	s.registerResources()
+
+	message := `{"jsonrpc": "2.0", "id": 1, "method": "resources/read", "params": {"uri": "quay://api/v1/organization/acme"}}`
+	response := s.mcpServer.HandleMessage(context.Background(), json.RawMessage(message))
+
+	if _, ok := response.(mcp.JSONRPCError); !ok {
+		encoded, _ := json.Marshal(response)
+		t.Errorf("Expected a disallowed organization to be rejected, got %s", encoded)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no API requests for the rejected read, got %d", requests)
+	}
+}
+
+func TestWriteResources(t *testing.T) {
+	s := newTestServer(t, resourceSpec, nil)
+
+	var out strings.Builder
+	if err := s.WriteResources(&out); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Every generated resource and template is printed, and nothing else
+	resources, templates := s.generateResourcesAndTemplates()
+	want := []string{fmt.Sprintf("Resources (%d)", len(resources))}
+	for _, resource := range resources {
+		want = append(want, fmt.Sprintf("  %s  %s  (%s)", resource.URI, resource.Name, strings.ReplaceAll(resource.Description, "\n", "; ")))
+	}
+	want = append(want, "", fmt.Sprintf("Resource templates (%d)", len(templates)))
+	for _, template := range templates {
+		want = append(want, fmt.Sprintf("  %s  %s  (%s)", template.URITemplate.Raw(), template.Name, strings.ReplaceAll(template.Description, "\n", "; ")))
+	}
+	if out.String() != strings.Join(want, "\n")+"\n" {
+		t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(want, "\n"), out.String())
+	}
+	if !strings.Contains(out.String(), "  quay://api/v1/repository/{namespace}/{repository}  Get repository  (Endpoint: GET /api/v1/repository/{namespace}/{repository}; Tags: repository)\n") {
+		t.Errorf("Expected the repository template line, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SetSmokeTest makes Start call tool with arguments once the tools are registered, logging whether
+// Quay answered, so operators know the server works end to end (an empty tool disables it)
+func (s *QuayMCPServer) SetSmokeTest(tool string, arguments map[string]interface{}) {
+	s.smokeTestTool = tool
+	s.smokeTestArgs = arguments
+}
+
+// runSmokeTest makes the configured smoke test call. Only GET tools may be used, so the check
+// can't change anything. A failed call is a warning, or an error with -fail-closed.
+func (s *QuayMCPServer) runSmokeTest(ctx context.Context) error {
+	if s.smokeTestTool == "" {
+		return nil
+	}
+
+	err := s.smokeTest(ctx)
+	if err == nil {
+		return nil
+	}
+	if s.failClosed {
+		return fmt.Errorf("refusing to start with -fail-closed: smoke test failed: %w", err)
+	}
+	s.warn("Smoke test failed: %v", err)
+	return nil
+}
+
+// smokeTest calls the smoke test tool and returns why it failed, if it did
+func (s *QuayMCPServer) smokeTest(ctx context.Context) error {
+	endpoint, err := s.findEndpoint(s.smokeTestTool)
+	if err != nil {
+		return err
+	}
+	if endpoint.Method != http.MethodGet {
+		return fmt.Errorf("%s is a %s tool; only GET tools can be used for the smoke test", s.smokeTestTool, endpoint.Method)
+	}
+
+	start := time.Now()
+	result, err := s.CallTool(ctx, s.smokeTestTool, s.smokeTestArgs)
+	if err != nil {
+		return err
+	}
+	if result.IsError {
+		// Failed calls lead with their message, which is enough for the log
+		message := ResultText(result)
+		if len(result.Content) > 0 {
+			if text, ok := mcp.AsTextContent(result.Content[0]); ok {
+				message = text.Text
+			}
+		}
+		return fmt.Errorf("%s: %s", s.smokeTestTool, message)
+	}
+
+	slog.Info("Smoke test succeeded", "tool", s.smokeTestTool, "elapsed", time.Since(start).Round(time.Millisecond))
+	return nil
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSmokeTest(t *testing.T) {
+	var status int
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("namespace") != "redhat" {
+			t.Errorf("Expected the smoke test arguments to be sent, got %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(`{"repositories": []}`))
+	})
+	if err := s.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+	s.SetSmokeTest("quay_listRepos", map[string]interface{}{"namespace": "redhat"})
+
+	status = http.StatusOK
+	logs := captureLogs(t)
+	if err := s.runSmokeTest(context.Background()); err != nil {
+		t.Fatalf("Expected the smoke test to pass, got %v", err)
+	}
+	if !strings.Contains(logs.String(), "Smoke test succeeded") {
+		t.Errorf("Expected the success to be logged, got:\n%s", logs.String())
+	}
+
+	// A failure is only a warning unless the server fails closed
+	status = http.StatusInternalServerError
+	if err := s.runSmokeTest(context.Background()); err != nil {
+		t.Fatalf("Expected a failed smoke test to only warn, got %v", err)
+	}
+	if len(s.warnings) != 1 || !strings.Contains(s.warnings[0], "Smoke test failed: quay_listRepos") {
+		t.Errorf("Expected a smoke test warning, got %v", s.warnings)
+	}
+
+	s.SetFailClosed(true)
+	if err := s.runSmokeTest(context.Background()); err == nil || !strings.Contains(err.Error(), "status 500") {
+		t.Errorf("Expected the failed smoke test to stop startup, got %v", err)
+	}
+}
+
+func TestSmokeTestOnlyCallsGetTools(t *testing.T) {
+	s := newTestServer(t, `{
+		"swagger": "2.0",
+		"basePath": "/api/v1",
+		"paths": {
+			"/api/v1/repository": {
+				"post": {"operationId": "createRepo", "summary": "Create a repository", "tags": ["repository"]}
+			}
+		}
+	}`, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no call to Quay, got %s %s", r.Method, r.URL.Path)
+	})
+	if err := s.quayClient.SetMethods([]string{"GET", "POST"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Initialize(); err != nil {
+		t.Fatalf("Failed to initialize server: %v", err)
+	}
+	s.SetSmokeTest("quay_createRepo", nil)
+	s.SetFailClosed(true)
+
+	if err := s.runSmokeTest(context.Background()); err == nil || !strings.Contains(err.Error(), "only GET tools") {
+		t.Errorf("Expected a write tool to be refused, got %v", err)
+	}
+}
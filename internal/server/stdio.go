@@ -0,0 +1,60 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// reconnectDelay is how long to wait after stdin reaches EOF before serving it again
+var reconnectDelay = time.Second
+
+// SetReconnectOnEOF keeps the stdio server alive when stdin reaches EOF, serving it again so a
+// client wrapper that reconnects can keep using the same process
+func (s *QuayMCPServer) SetReconnectOnEOF(enabled bool) {
+	s.reconnectOnEOF = enabled
+}
+
+// serveStdioWithReconnect serves MCP over stdin/stdout until ctx is cancelled, resuming after
+// each EOF instead of exiting
+func (s *QuayMCPServer) serveStdioWithReconnect(ctx context.Context, stdin io.Reader, stdout io.Writer) error {
+	stdio := server.NewStdioServer(s.mcpServer)
+	for {
+		err := stdio.Listen(ctx, stdin, stdout)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		slog.Info("stdin reached EOF, serving again", "delay", reconnectDelay)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// serveStdio serves MCP over the process's stdin/stdout, stopping on SIGTERM or SIGINT
+func (s *QuayMCPServer) serveStdio() error {
+	if !s.reconnectOnEOF {
+		return server.ServeStdio(s.mcpServer)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	if err := s.serveStdioWithReconnect(ctx, os.Stdin, os.Stdout); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
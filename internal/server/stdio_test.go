@@ -0,0 +1,78 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// eofReader returns each chunk in turn, with an io.EOF between chunks as if the client went away
+type eofReader struct {
+	chunks  []string
+	pending bool
+}
+
+func (r *eofReader) Read(p []byte) (int, error) {
+	if r.pending || len(r.chunks) == 0 {
+		r.pending = false
+		return 0, io.EOF
+	}
+	n := copy(p, r.chunks[0])
+	r.chunks = r.chunks[1:]
+	r.pending = true
+	return n, nil
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestReconnectOnEOF(t *testing.T) {
+	defer func(delay time.Duration) { reconnectDelay = delay }(reconnectDelay)
+	reconnectDelay = time.Millisecond
+
+	s := NewQuayMCPServer("https://quay.io", "")
+	s.SetReconnectOnEOF(true)
+
+	stdin := &eofReader{chunks: []string{
+		`{"jsonrpc": "2.0", "id": 1, "method": "ping"}` + "\n",
+		`{"jsonrpc": "2.0", "id": 2, "method": "ping"}` + "\n",
+	}}
+	var stdout syncBuffer
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.serveStdioWithReconnect(ctx, stdin, &stdout) }()
+
+	// The second ping arrives after an EOF, so it is only answered if the server re-serves
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(stdout.String(), `"id":2`) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected a response to the ping sent after EOF, got %q", stdout.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Expected a clean shutdown, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"unicode/utf8"
+)
+
+// ResponseSummarizer condenses a large response before it is sent to the client, e.g. by asking
+// an LLM for a summary, to keep big listings from filling the client's context. Implementations
+// must be safe for concurrent use.
+type ResponseSummarizer interface {
+	// Summarize returns the condensed response of a tool call; an error sends the response as is
+	Summarize(ctx context.Context, toolName string, data []byte) ([]byte, error)
+}
+
+// Summarizers lists the built-in -summarize-with-llm values
+var Summarizers = []string{"none", "truncate"}
+
+// DefaultSummarizeThreshold is the response size above which the summarizer is used unless
+// configured otherwise
+const DefaultSummarizeThreshold = 32 * 1024
+
+// NewResponseSummarizer returns the built-in summarizer with this name; limit is the size its
+// output is held to
+func NewResponseSummarizer(name string, limit int) (ResponseSummarizer, error) {
+	switch name {
+	case "", "none":
+		return NoopSummarizer{}, nil
+	case "truncate":
+		return TruncatingSummarizer{MaxBytes: limit}, nil
+	}
+	return nil, fmt.Errorf("unknown summarizer %q (expected one of %v)", name, Summarizers)
+}
+
+// NoopSummarizer returns responses unchanged; it is the default
+type NoopSummarizer struct{}
+
+// Summarize returns data as is
+func (NoopSummarizer) Summarize(ctx context.Context, toolName string, data []byte) ([]byte, error) {
+	return data, nil
+}
+
+// TruncatingSummarizer keeps the first MaxBytes of a response and notes how much was dropped.
+// The result of a JSON response is no longer valid JSON.
+type TruncatingSummarizer struct {
+	MaxBytes int
+}
+
+// Summarize cuts data to MaxBytes on a UTF-8 boundary
+func (t TruncatingSummarizer) Summarize(ctx context.Context, toolName string, data []byte) ([]byte, error) {
+	if t.MaxBytes <= 0 || len(data) <= t.MaxBytes {
+		return data, nil
+	}
+	end := t.MaxBytes
+	for end > 0 && !utf8.RuneStart(data[end]) {
+		end--
+	}
+	note := fmt.Sprintf("\n\n[truncated: showing %d of %d bytes]", end, len(data))
+	return append(data[:end:end], note...), nil
+}
+
+// SetResponseSummarizer passes responses larger than threshold bytes through summarizer before
+// they are sent to the client (nil restores the no-op default)
+func (s *QuayMCPServer) SetResponseSummarizer(summarizer ResponseSummarizer, threshold int) {
+	if summarizer == nil {
+		summarizer = NoopSummarizer{}
+	}
+	s.summarizer = summarizer
+	s.summarizeThreshold = threshold
+}
+
+// summarizeLarge returns the summarized response when it is over the threshold. A failing
+// summarizer is logged and the response returned unchanged.
+func (s *QuayMCPServer) summarizeLarge(ctx context.Context, toolName string, data []byte) []byte {
+	if len(data) <= s.summarizeThreshold {
+		return data
+	}
+	summarized, err := s.summarizer.Summarize(ctx, toolName, data)
+	if err != nil {
+		slog.Warn("Returning response unsummarized", "tool", toolName, "error", err)
+		return data
+	}
+	if len(summarized) != len(data) {
+		slog.Debug("Summarized response", "tool", toolName, "bytes", len(data), "summarized_bytes", len(summarized))
+	}
+	return summarized
+}
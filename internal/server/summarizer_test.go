@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// stubSummarizer records the responses it is given and replaces them with a fixed summary
+type stubSummarizer struct {
+	calls []string
+}
+
+func (s *stubSummarizer) Summarize(ctx context.Context, toolName string, data []byte) ([]byte, error) {
+	s.calls = append(s.calls, toolName)
+	return []byte("3 repositories"), nil
+}
+
+func TestResponseSummarizer(t *testing.T) {
+	body := `{"repositories": [{"name": "ubi8"}, {"name": "ubi9"}, {"name": "fedora"}]}`
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	})
+	stub := &stubSummarizer{}
+	handler := s.createToolHandler()
+
+	// Responses under the threshold are sent as is
+	s.SetResponseSummarizer(stub, len(body))
+	if text := resultText(t, callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "redhat"})); text != body {
+		t.Errorf("Expected the small response unchanged, got %s", text)
+	}
+	if len(stub.calls) != 0 {
+		t.Errorf("Expected the summarizer not to be invoked, got %d calls", len(stub.calls))
+	}
+
+	s.SetResponseSummarizer(stub, len(body)-1)
+	if text := resultText(t, callTool(t, handler, "quay_listRepos", map[string]interface{}{"namespace": "redhat"})); text != "3 repositories" {
+		t.Errorf("Expected the summary, got %s", text)
+	}
+	if len(stub.calls) != 1 || stub.calls[0] != "quay_listRepos" {
+		t.Errorf("Expected one summarizer call for quay_listRepos, got %v", stub.calls)
+	}
+}
+
+func TestTruncatingSummarizer(t *testing.T) {
+	summarizer, err := NewResponseSummarizer("truncate", 4)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	out, err := summarizer.Summarize(context.Background(), "quay_listRepos", []byte("ab€cdefgh"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// The cut backs off to the start of the three-byte euro sign
+	if want := "ab\n\n[truncated: showing 2 of 11 bytes]"; string(out) != want {
+		t.Errorf("Expected %q, got %q", want, out)
+	}
+
+	if _, err := NewResponseSummarizer("gpt", 5); err == nil || !strings.Contains(err.Error(), "unknown summarizer") {
+		t.Errorf("Expected an unknown summarizer error, got %v", err)
+	}
+}
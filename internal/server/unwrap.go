@@ -0,0 +1,54 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// SetUnwrapResponse returns the inner value of wrapped JSON responses: for the tools in keys, the
+// value under the configured key, and with infer, the array of any other response whose only
+// top-level key holds one, e.g. {"repositories": [...]} becomes [...]
+func (s *QuayMCPServer) SetUnwrapResponse(infer bool, keys map[string]string) {
+	s.unwrapInfer = infer
+	s.unwrapKeys = keys
+}
+
+// unwrapResponse unwraps a tool's response as configured, returning it unchanged otherwise
+func (s *QuayMCPServer) unwrapResponse(toolName string, data []byte) []byte {
+	if key, ok := s.unwrapKeys[toolName]; ok {
+		if inner, ok := unwrapJSON(data, key); ok {
+			return inner
+		}
+		return data
+	}
+	if s.unwrapInfer {
+		if inner, ok := unwrapJSON(data, ""); ok {
+			return inner
+		}
+	}
+	return data
+}
+
+// unwrapJSON returns the raw value under key in a JSON object. With an empty key it unwraps only
+// an object whose single key holds an array. It reports false when there is nothing to unwrap.
+func unwrapJSON(data []byte, key string) ([]byte, bool) {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, false
+	}
+
+	if key == "" {
+		if len(object) != 1 {
+			return nil, false
+		}
+		for name, value := range object {
+			if !bytes.HasPrefix(bytes.TrimSpace(value), []byte("[")) {
+				return nil, false
+			}
+			key = name
+		}
+	}
+
+	inner, ok := object[key]
+	return inner, ok
+}
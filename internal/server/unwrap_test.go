@@ -0,0 +1,47 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestUnwrapJSON(t *testing.T) {
+	tests := []struct {
+		name, data, key, want string
+		ok                    bool
+	}{
+		{"single key array", `{"repositories": [{"name": "ubi8"}]}`, "", `[{"name": "ubi8"}]`, true},
+		{"other keys", `{"repositories": [], "next_page": "abc"}`, "", "", false},
+		{"single key object", `{"repository": {"name": "ubi8"}}`, "", "", false},
+		{"configured key", `{"repositories": [1], "next_page": "abc"}`, "repositories", `[1]`, true},
+		{"missing key", `{"tags": []}`, "repositories", "", false},
+		{"not an object", `[1, 2]`, "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := unwrapJSON([]byte(tt.data), tt.key)
+			if ok != tt.ok || string(got) != tt.want {
+				t.Errorf("Expected %q, %v, got %q, %v", tt.want, tt.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestUnwrapResponse(t *testing.T) {
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"repositories": [{"name": "ubi8"}, {"name": "ubi9"}]}`))
+	})
+	handler := s.createToolHandler()
+	args := map[string]interface{}{"namespace": "redhat"}
+
+	s.SetUnwrapResponse(true, nil)
+	if text := resultText(t, callTool(t, handler, "quay_listRepos", args)); text != `[{"name": "ubi8"}, {"name": "ubi9"}]` {
+		t.Errorf("Expected the inner array, got %s", text)
+	}
+
+	s.SetUnwrapResponse(false, map[string]string{"quay_getRepo": "repositories"})
+	if text := resultText(t, callTool(t, handler, "quay_listRepos", args)); text != `{"repositories": [{"name": "ubi8"}, {"name": "ubi9"}]}` {
+		t.Errorf("Expected tools without a configured key to be left whole, got %s", text)
+	}
+}
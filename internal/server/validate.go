@@ -0,0 +1,127 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SetValidateArgs enables validating tool arguments against the generated input schema before calling Quay
+func (s *QuayMCPServer) SetValidateArgs(enabled bool) {
+	s.validateArgs = enabled
+}
+
+// validateArguments checks arguments against a tool's input schema and returns every problem found:
+// missing required arguments, values of the wrong type and values outside a declared enum
+func validateArguments(tool mcp.Tool, arguments map[string]interface{}) []string {
+	var problems []string
+
+	for _, name := range tool.InputSchema.Required {
+		if value, exists := arguments[name]; !exists || value == nil || value == "" {
+			problems = append(problems, fmt.Sprintf("missing required argument %q", name))
+		}
+	}
+
+	names := make([]string, 0, len(arguments))
+	for name := range arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := tool.InputSchema.Properties[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		value := arguments[name]
+		if value == nil {
+			continue
+		}
+
+		if expected, ok := property["type"].(string); ok && !matchesSchemaType(expected, value) {
+			problems = append(problems, fmt.Sprintf("argument %q must be of type %s, got %s", name, expected, jsonTypeName(value)))
+			continue
+		}
+
+		if enum := enumValues(property["enum"]); len(enum) > 0 {
+			allowed := false
+			for _, candidate := range enum {
+				if fmt.Sprint(candidate) == fmt.Sprint(value) {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				options := make([]string, len(enum))
+				for i, candidate := range enum {
+					options[i] = fmt.Sprint(candidate)
+				}
+				problems = append(problems, fmt.Sprintf("argument %q must be one of [%s], got %v", name, strings.Join(options, ", "), value))
+			}
+		}
+	}
+
+	return problems
+}
+
+// matchesSchemaType reports whether a decoded JSON value has the given JSON Schema type
+func matchesSchemaType(expected string, value interface{}) bool {
+	switch expected {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		return reflect.TypeOf(value).Kind() == reflect.Slice
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	}
+	return true
+}
+
+// jsonTypeName returns the JSON type name of a decoded value for error messages
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	}
+	if reflect.TypeOf(value).Kind() == reflect.Slice {
+		return "array"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// enumValues normalizes a schema enum, which may be []string or []interface{}
+func enumValues(enum interface{}) []interface{} {
+	if enum == nil {
+		return nil
+	}
+	v := reflect.ValueOf(enum)
+	if v.Kind() != reflect.Slice {
+		return nil
+	}
+	values := make([]interface{}, v.Len())
+	for i := range values {
+		values[i] = v.Index(i).Interface()
+	}
+	return values
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestValidateArgumentsReportsAllProblems(t *testing.T) {
+	tool := mcp.NewTool("quay_listRepos",
+		mcp.WithString("namespace", mcp.Required()),
+		mcp.WithNumber("limit"),
+		mcp.WithBoolean("public"),
+		mcp.WithString("sort", mcp.Enum("asc", "desc")),
+	)
+
+	problems := validateArguments(tool, map[string]interface{}{
+		"limit":  "ten",
+		"public": "yes",
+		"sort":   "sideways",
+	})
+	if len(problems) != 4 {
+		t.Fatalf("Expected 4 problems, got %d: %v", len(problems), problems)
+	}
+
+	joined := strings.Join(problems, "\n")
+	for _, want := range []string{`missing required argument "namespace"`, `"limit" must be of type number`, `"public" must be of type boolean`, `"sort" must be one of [asc, desc]`} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("Expected problem %q, got %v", want, problems)
+		}
+	}
+
+	if problems := validateArguments(tool, map[string]interface{}{"namespace": "redhat", "limit": float64(5), "sort": "asc"}); len(problems) != 0 {
+		t.Errorf("Expected valid arguments to pass, got %v", problems)
+	}
+}
+
+func TestValidateArgsInHandler(t *testing.T) {
+	var requests int
+	s := newTestServer(t, testSpec, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{}`))
+	})
+	s.registerTools(s.quayClient.GenerateTools())
+	s.SetValidateArgs(true)
+
+	result := callTool(t, s.createToolHandler(), "quay_getRepo", map[string]interface{}{"resource_uri": 5.0})
+	if !result.IsError {
+		t.Fatalf("Expected a validation error, got %s", resultText(t, result))
+	}
+	text := resultText(t, result)
+	if !strings.Contains(text, `missing required argument "repository"`) || !strings.Contains(text, `"resource_uri" must be of type string`) {
+		t.Errorf("Expected both problems listed, got %s", text)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request for invalid arguments, got %d", requests)
+	}
+}
@@ -8,4 +8,7 @@ type EndpointInfo struct {
 	OperationID string
 	Tags        []string
 	Parameters  []interface{}
+
+	// ResponseExample is the documented example response (as JSON), if the spec declares one
+	ResponseExample string
 }